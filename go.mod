@@ -1,8 +1,9 @@
 module github.com/fpt/go-dev-mcp
 
-go 1.24.2
+go 1.25.0
 
 require (
+	github.com/bmatcuk/doublestar/v4 v4.10.2
 	github.com/google/go-github/v74 v74.0.0
 	github.com/google/subcommands v1.2.0
 	github.com/mark3labs/mcp-go v0.38.0
@@ -10,7 +11,9 @@ require (
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.10.0
 	github.com/yuin/goldmark v1.7.12
-	golang.org/x/net v0.41.0
+	golang.org/x/mod v0.35.0
+	golang.org/x/net v0.53.0
+	golang.org/x/tools v0.44.0
 )
 
 require (
@@ -25,5 +28,6 @@ require (
 	github.com/spf13/cast v1.7.1 // indirect
 	github.com/wk8/go-ordered-map/v2 v2.1.8 // indirect
 	github.com/yosida95/uritemplate/v3 v3.0.2 // indirect
+	golang.org/x/sync v0.20.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -3,8 +3,10 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
 
+	"github.com/fpt/go-dev-mcp/internal/app"
 	"github.com/fpt/go-dev-mcp/internal/subcmd"
 	"github.com/google/subcommands"
 )
@@ -20,11 +22,19 @@ func main() {
 	subcommands.Register(&subcmd.GithubCmd{}, "")
 	subcommands.Register(&subcmd.LocalSearchCmd{}, "")
 	subcommands.Register(&subcmd.OutlineGoPackageCmd{}, "")
+	subcommands.Register(&subcmd.RefsCmd{}, "")
+	subcommands.Register(&subcmd.DescribeCmd{}, "")
+	subcommands.Register(&subcmd.ImplsCmd{}, "")
 	subcommands.Register(&subcmd.MarkdownCmd{}, "")
 	subcommands.Register(&subcmd.ValidateCmd{}, "")
 	subcommands.Register(&subcmd.PyDocCmd{}, "")
+	subcommands.Register(&subcmd.CacheCmd{}, "")
 
 	flag.Parse()
+	if err := app.ConfigureDocSites(app.DocSitesFromEnv()); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(int(subcommands.ExitUsageError))
+	}
 	ctx := context.Background()
 	os.Exit(int(subcommands.Execute(ctx)))
 }
@@ -26,8 +26,10 @@ type DeclarationExtractResult struct {
 }
 
 type FunctionCall struct {
-	Name    string // Function name (e.g., "fmt.Println", "myFunc")
-	Package string // Package name if external call (e.g., "fmt")
+	Name      string // Function name (e.g., "fmt.Println", "myFunc")
+	Package   string // Package name if external call (e.g., "fmt"); import path in typed mode
+	Qualified string // Fully qualified callee (e.g., "(*net/http.Client).Do"); typed mode only
+	Dynamic   bool   // Whether the call is dispatched through an interface; typed mode only
 }
 
 type CallGraphEntry struct {
@@ -37,6 +39,7 @@ type CallGraphEntry struct {
 
 type CallGraphResult struct {
 	Filename  string           // Source file path
+	Package   string           // Import path of the file's package; typed mode only
 	CallGraph []CallGraphEntry // Call relationships
 }
 
@@ -65,11 +68,15 @@ type DependencyGraphResult struct {
 
 // ExtractDeclarations extracts all exported declarations from Go source files in the specified directory.
 func ExtractDeclarations(
-	ctx context.Context, fw repository.FileWalker, path string,
+	ctx context.Context, fw repository.FileWalker, path string, opts repository.WalkFileOptions,
 ) ([]DeclarationExtractResult, error) {
 	var results []DeclarationExtractResult
 	err := fw.Walk(ctx, func(filePath string) error {
-		declarations := extractDeclarationsFromFile(filePath)
+		src, err := fw.ReadFile(filePath)
+		if err != nil {
+			return nil // Skip unreadable files
+		}
+		declarations := extractDeclarationsFromFile(filePath, src)
 		if len(declarations) > 0 {
 			results = append(results, DeclarationExtractResult{
 				Filename:     filePath,
@@ -78,7 +85,7 @@ func ExtractDeclarations(
 		}
 
 		return nil
-	}, path, ".go", true)
+	}, path, goFiles(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -91,7 +98,7 @@ func ExtractDeclarations(
 func ExtractFunctionNames(
 	ctx context.Context, fw repository.FileWalker, path string,
 ) ([]DeclarationExtractResult, error) {
-	results, err := ExtractDeclarations(ctx, fw, path)
+	results, err := ExtractDeclarations(ctx, fw, path, repository.WalkFileOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -116,14 +123,14 @@ func ExtractFunctionNames(
 	return functionResults, nil
 }
 
-func extractDeclarationsFromFile(filePath string) []Declaration {
+func extractDeclarationsFromFile(filePath string, src []byte) []Declaration {
 	// Skip test files
 	if strings.HasSuffix(filePath, "_test.go") {
 		return nil
 	}
 
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		// Skip files that can't be parsed instead of failing
 		return nil
@@ -292,9 +299,10 @@ func getTypeName(expr ast.Expr) string {
 }
 
 // ExtractCallGraph extracts function call relationships from a single Go file.
-func ExtractCallGraph(filePath string) (*CallGraphResult, error) {
+// The file is read from filePath if src is nil.
+func ExtractCallGraph(filePath string, src []byte) (*CallGraphResult, error) {
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -388,6 +396,7 @@ func ExtractPackageDependencies(
 	ctx context.Context,
 	fw repository.FileWalker,
 	projectPath string,
+	opts repository.WalkFileOptions,
 ) (*DependencyGraphResult, error) {
 	result := &DependencyGraphResult{
 		ProjectPath:  projectPath,
@@ -395,7 +404,7 @@ func ExtractPackageDependencies(
 	}
 
 	// Try to find module name from go.mod
-	moduleName, err := findModuleName(projectPath)
+	moduleName, err := readModuleName(fw, projectPath)
 	if err != nil {
 		// If no go.mod found, use directory name as fallback
 		moduleName = filepath.Base(projectPath)
@@ -404,7 +413,11 @@ func ExtractPackageDependencies(
 
 	// Walk through all Go files and extract dependencies
 	err = fw.Walk(ctx, func(filePath string) error {
-		dep, err := extractPackageDependencyFromFile(filePath, moduleName)
+		src, err := fw.ReadFile(filePath)
+		if err != nil {
+			return nil // Skip unreadable files
+		}
+		dep, err := extractPackageDependencyFromFile(filePath, src, moduleName)
 		if err != nil {
 			// Skip files that can't be parsed instead of failing
 			return nil
@@ -415,7 +428,7 @@ func ExtractPackageDependencies(
 		}
 
 		return nil
-	}, projectPath, ".go", true)
+	}, projectPath, goFiles(opts))
 	if err != nil {
 		return nil, err
 	}
@@ -430,7 +443,21 @@ func findModuleName(projectPath string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	return parseModuleName(content)
+}
 
+// readModuleName reads the module name from the go.mod file of a directory
+// walked by fw.
+func readModuleName(fw repository.FileWalker, projectPath string) (string, error) {
+	content, err := fw.ReadFile(filepath.Join(projectPath, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	return parseModuleName(content)
+}
+
+// parseModuleName returns the module path declared in the content of a go.mod file.
+func parseModuleName(content []byte) (string, error) {
 	lines := strings.Split(string(content), "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
@@ -443,14 +470,16 @@ func findModuleName(projectPath string) (string, error) {
 }
 
 // extractPackageDependencyFromFile extracts package info and imports from a single Go file
-func extractPackageDependencyFromFile(filePath, moduleName string) (*PackageDependency, error) {
+func extractPackageDependencyFromFile(
+	filePath string, src []byte, moduleName string,
+) (*PackageDependency, error) {
 	// Skip test files
 	if strings.HasSuffix(filePath, "_test.go") {
 		return nil, nil
 	}
 
 	fset := token.NewFileSet()
-	node, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
+	node, err := parser.ParseFile(fset, filePath, src, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -518,11 +547,46 @@ func isLocalImport(importPath, moduleName string) bool {
 	return strings.HasPrefix(importPath, moduleName)
 }
 
+// OutlineMode selects how the call graph section is analyzed.
+type OutlineMode string
+
+const (
+	// OutlineModeHeuristic parses each file in isolation with go/parser.
+	OutlineModeHeuristic OutlineMode = "heuristic"
+	// OutlineModeTyped loads the packages with go/packages and resolves callees with go/types.
+	OutlineModeTyped OutlineMode = "typed"
+)
+
+// ParseOutlineMode converts a user supplied mode name to an OutlineMode.
+// An empty string selects the heuristic mode.
+func ParseOutlineMode(mode string) (OutlineMode, error) {
+	switch OutlineMode(mode) {
+	case "", OutlineModeHeuristic:
+		return OutlineModeHeuristic, nil
+	case OutlineModeTyped:
+		return OutlineModeTyped, nil
+	default:
+		return "", fmt.Errorf("unknown outline mode %q (expected heuristic or typed)", mode)
+	}
+}
+
 // OutlineGoPackageOptions controls which sections are included in the outline.
 type OutlineGoPackageOptions struct {
-	SkipDependencies  bool
-	SkipDeclarations  bool
-	SkipCallGraph     bool
+	SkipDependencies bool
+	SkipDeclarations bool
+	SkipCallGraph    bool
+	Mode             OutlineMode
+
+	// Files selects the files of the package by globs and size. The typed mode
+	// loads the call graph of all files with go/packages.
+	Files repository.WalkFileOptions
+}
+
+// goFiles returns opts restricted to the Go files outside of dot directories.
+func goFiles(opts repository.WalkFileOptions) repository.WalkFileOptions {
+	opts.Extensions = []string{".go"}
+	opts.IgnoreDot = true
+	return opts
 }
 
 // OutlineGoPackage produces a comprehensive outline of a Go package:
@@ -534,7 +598,7 @@ func OutlineGoPackage(
 	var sb strings.Builder
 
 	// Always extract dependencies for the module name header
-	depResult, err := ExtractPackageDependencies(ctx, fw, directory)
+	depResult, err := ExtractPackageDependencies(ctx, fw, directory, opts.Files)
 	if err != nil {
 		return "", fmt.Errorf("extracting dependencies: %w", err)
 	}
@@ -548,7 +612,7 @@ func OutlineGoPackage(
 	}
 
 	if !opts.SkipDeclarations {
-		declResults, err := ExtractDeclarations(ctx, fw, directory)
+		declResults, err := ExtractDeclarations(ctx, fw, directory, opts.Files)
 		if err != nil {
 			return "", fmt.Errorf("extracting declarations: %w", err)
 		}
@@ -576,54 +640,121 @@ func OutlineGoPackage(
 
 	if !opts.SkipCallGraph {
 		sb.WriteString("== Call Graph ==\n")
-		callGraphCount := 0
 
-		err = fw.Walk(ctx, func(filePath string) error {
-			if strings.HasSuffix(filePath, "_test.go") {
-				return nil
-			}
+		if opts.Mode == OutlineModeTyped {
+			err = writeTypedCallGraph(ctx, &sb, directory)
+		} else {
+			err = writeHeuristicCallGraph(ctx, &sb, fw, directory, opts.Files)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
 
-			result, cgErr := ExtractCallGraph(filePath)
-			if cgErr != nil {
-				return nil // skip unparseable files
-			}
+	return sb.String(), nil
+}
+
+// writeHeuristicCallGraph writes the call graph built by parsing each file in isolation.
+func writeHeuristicCallGraph(
+	ctx context.Context,
+	sb *strings.Builder,
+	fw repository.FileWalker,
+	directory string,
+	opts repository.WalkFileOptions,
+) error {
+	callGraphCount := 0
+
+	err := fw.Walk(ctx, func(filePath string) error {
+		if strings.HasSuffix(filePath, "_test.go") {
+			return nil
+		}
 
-			if len(result.CallGraph) == 0 {
-				return nil
+		src, err := fw.ReadFile(filePath)
+		if err != nil {
+			return nil // skip unreadable files
+		}
+		result, cgErr := ExtractCallGraph(filePath, src)
+		if cgErr != nil {
+			return nil // skip unparseable files
+		}
+
+		if len(result.CallGraph) == 0 {
+			return nil
+		}
+
+		callGraphCount++
+		sb.WriteString(fmt.Sprintf("File: %s\n", result.Filename))
+
+		for _, entry := range result.CallGraph {
+			calls := filterCallGraphNoise(entry.Calls)
+			if len(calls) == 0 {
+				continue
 			}
 
-			callGraphCount++
-			sb.WriteString(fmt.Sprintf("File: %s\n", result.Filename))
+			sb.WriteString(fmt.Sprintf("  %s\n", entry.Function))
 
-			for _, entry := range result.CallGraph {
-				calls := filterCallGraphNoise(entry.Calls)
-				if len(calls) == 0 {
-					continue
+			for _, call := range calls {
+				if call.Package != "" {
+					sb.WriteString(fmt.Sprintf("    -> %s.%s\n", call.Package, call.Name))
+				} else {
+					sb.WriteString(fmt.Sprintf("    -> %s\n", call.Name))
 				}
+			}
+		}
 
-				sb.WriteString(fmt.Sprintf("  %s\n", entry.Function))
+		return nil
+	}, directory, goFiles(opts))
+	if err != nil {
+		return fmt.Errorf("walking for call graph: %w", err)
+	}
 
-				for _, call := range calls {
-					if call.Package != "" {
-						sb.WriteString(fmt.Sprintf("    -> %s.%s\n", call.Package, call.Name))
-					} else {
-						sb.WriteString(fmt.Sprintf("    -> %s\n", call.Name))
-					}
-				}
+	if callGraphCount == 0 {
+		sb.WriteString("No exported function calls found.\n")
+	}
+
+	return nil
+}
+
+// writeTypedCallGraph writes the call graph resolved with full type information.
+// Interface method calls are suffixed with "(dynamic)".
+func writeTypedCallGraph(ctx context.Context, sb *strings.Builder, directory string) error {
+	results, err := ExtractTypedCallGraph(ctx, directory)
+	if err != nil {
+		return fmt.Errorf("extracting typed call graph: %w", err)
+	}
+
+	callGraphCount := 0
+	for _, result := range results {
+		fileHeaderWritten := false
+		for _, entry := range result.CallGraph {
+			calls := filterTypedCallGraphNoise(result.Package, entry.Calls)
+			if len(calls) == 0 {
+				continue
 			}
 
-			return nil
-		}, directory, ".go", true)
-		if err != nil {
-			return "", fmt.Errorf("walking for call graph: %w", err)
-		}
+			if !fileHeaderWritten {
+				callGraphCount++
+				sb.WriteString(fmt.Sprintf("File: %s\n", result.Filename))
+				fileHeaderWritten = true
+			}
+
+			sb.WriteString(fmt.Sprintf("  %s\n", entry.Function))
 
-		if callGraphCount == 0 {
-			sb.WriteString("No exported function calls found.\n")
+			for _, call := range calls {
+				if call.Dynamic {
+					sb.WriteString(fmt.Sprintf("    -> %s (dynamic)\n", call.Qualified))
+				} else {
+					sb.WriteString(fmt.Sprintf("    -> %s\n", call.Qualified))
+				}
+			}
 		}
 	}
 
-	return sb.String(), nil
+	if callGraphCount == 0 {
+		sb.WriteString("No exported function calls found.\n")
+	}
+
+	return nil
 }
 
 // formatDepsRelative writes dependency info using relative file paths.
@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"go/ast"
+	"go/types"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+// ExtractTypedCallGraph extracts call relationships for every exported function
+// in the packages under directory using full type information.
+// Unlike ExtractCallGraph, callees are fully qualified (e.g. "(*net/http.Client).Do"),
+// method calls on variables are resolved through their static type and
+// calls through an interface are marked as dynamic.
+func ExtractTypedCallGraph(ctx context.Context, directory string) ([]CallGraphResult, error) {
+	pkgs, err := loadTypedPackages(ctx, directory, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CallGraphResult
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, file := range pkg.Syntax {
+			filename := pkg.Fset.Position(file.Pos()).Filename
+			if strings.HasSuffix(filename, "_test.go") {
+				continue
+			}
+
+			result := CallGraphResult{
+				Filename:  filename,
+				Package:   pkg.PkgPath,
+				CallGraph: []CallGraphEntry{},
+			}
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Name == nil || !funcDecl.Name.IsExported() {
+					continue
+				}
+				result.CallGraph = append(result.CallGraph, CallGraphEntry{
+					Function: getFunctionSignature(funcDecl),
+					Calls:    extractTypedFunctionCalls(pkg, funcDecl),
+				})
+			}
+			if len(result.CallGraph) > 0 {
+				results = append(results, result)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// extractTypedFunctionCalls resolves every call within a function body to its callee.
+// Calls are returned in order of first appearance, without duplicates.
+func extractTypedFunctionCalls(pkg *packages.Package, funcDecl *ast.FuncDecl) []FunctionCall {
+	calls := []FunctionCall{}
+	if funcDecl.Body == nil {
+		return calls
+	}
+
+	seen := make(map[string]bool)
+	ast.Inspect(funcDecl.Body, func(n ast.Node) bool {
+		callExpr, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		call, ok := analyzeTypedFunctionCall(pkg.TypesInfo, callExpr)
+		if !ok || seen[call.Qualified] {
+			return true
+		}
+		seen[call.Qualified] = true
+		calls = append(calls, call)
+		return true
+	})
+
+	return calls
+}
+
+// analyzeTypedFunctionCall resolves the callee of a call expression.
+// It returns false for builtins, type conversions and calls of function values.
+func analyzeTypedFunctionCall(info *types.Info, callExpr *ast.CallExpr) (FunctionCall, bool) {
+	// Static calls: package-level functions and methods of concrete types
+	if fn := typeutil.StaticCallee(info, callExpr); fn != nil {
+		return newTypedFunctionCall(fn.Origin(), false), true
+	}
+
+	// Dynamic calls: methods invoked through an interface value
+	sel, ok := ast.Unparen(callExpr.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return FunctionCall{}, false
+	}
+	selection, ok := info.Selections[sel]
+	if !ok || selection.Kind() != types.MethodVal || !types.IsInterface(selection.Recv()) {
+		return FunctionCall{}, false
+	}
+	fn, ok := selection.Obj().(*types.Func)
+	if !ok {
+		return FunctionCall{}, false
+	}
+
+	return newTypedFunctionCall(fn.Origin(), true), true
+}
+
+func newTypedFunctionCall(fn *types.Func, dynamic bool) FunctionCall {
+	call := FunctionCall{
+		Name:      fn.Name(),
+		Qualified: fn.FullName(),
+		Dynamic:   dynamic,
+	}
+	if fn.Pkg() != nil {
+		call.Package = fn.Pkg().Path()
+	}
+	return call
+}
+
+// filterTypedCallGraphNoise is the typed counterpart of filterCallGraphNoise.
+// Builtins and type conversions are already excluded during resolution, so only
+// common stdlib packages and unexported callees of the calling package are dropped.
+func filterTypedCallGraphNoise(pkgPath string, calls []FunctionCall) []FunctionCall {
+	var filtered []FunctionCall
+	for _, c := range calls {
+		// Skip noisy stdlib packages (matched by the last path element, e.g. "log/slog")
+		if isStandardLibrary(c.Package) && callGraphNoisePackages[lastPathElem(c.Package)] {
+			continue
+		}
+		// Skip unexported functions and methods of the calling package
+		if c.Package == pkgPath && !ast.IsExported(c.Name) {
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+func lastPathElem(path string) string {
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		return path[i+1:]
+	}
+	return path
+}
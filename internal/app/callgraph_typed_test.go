@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const typedTestSource = `package sample
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+type Store struct{ f *os.File }
+
+func (s *Store) Close() error { return s.f.Close() }
+
+func Read(r io.Reader, s *Store) error {
+	buf := make([]byte, 8)
+	_, _ = r.Read(buf)
+	_ = strings.TrimSpace("x")
+	helper()
+	return s.Close()
+}
+
+func helper() {}
+`
+
+// writeTypedTestModule creates a minimal module in a temporary directory.
+func writeTypedTestModule(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	goMod := "module example.com/sample\n\ngo 1.21\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o600))
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	return dir
+}
+
+func TestExtractTypedCallGraph(t *testing.T) {
+	dir := writeTypedTestModule(t, map[string]string{"sample.go": typedTestSource})
+
+	results, err := ExtractTypedCallGraph(context.Background(), dir)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "example.com/sample", results[0].Package)
+
+	calls := map[string]FunctionCall{}
+	for _, entry := range results[0].CallGraph {
+		if entry.Function != "Read" {
+			continue
+		}
+		for _, c := range filterTypedCallGraphNoise(results[0].Package, entry.Calls) {
+			calls[c.Qualified] = c
+		}
+	}
+
+	// Interface calls are dynamic, concrete method calls are resolved statically
+	require.Contains(t, calls, "(io.Reader).Read")
+	assert.True(t, calls["(io.Reader).Read"].Dynamic)
+	require.Contains(t, calls, "(*example.com/sample.Store).Close")
+	assert.False(t, calls["(*example.com/sample.Store).Close"].Dynamic)
+
+	// Noise: common stdlib, builtins and unexported local functions
+	assert.NotContains(t, calls, "strings.TrimSpace")
+	assert.NotContains(t, calls, "example.com/sample.helper")
+	assert.Len(t, calls, 2)
+}
+
+func TestOutlineGoPackageTypedMode(t *testing.T) {
+	dir := writeTypedTestModule(t, map[string]string{"sample.go": typedTestSource})
+
+	output, err := OutlineGoPackage(
+		context.Background(),
+		infra.NewFileWalker(),
+		dir,
+		OutlineGoPackageOptions{
+			SkipDependencies: true,
+			SkipDeclarations: true,
+			Mode:             OutlineModeTyped,
+		},
+	)
+	require.NoError(t, err)
+	assert.Contains(t, output, "== Call Graph ==")
+	assert.Contains(t, output, "  Read\n")
+	assert.Contains(t, output, "    -> (io.Reader).Read (dynamic)\n")
+	assert.Contains(t, output, "    -> (*example.com/sample.Store).Close\n")
+}
+
+func TestParseOutlineMode(t *testing.T) {
+	mode, err := ParseOutlineMode("")
+	require.NoError(t, err)
+	assert.Equal(t, OutlineModeHeuristic, mode)
+
+	mode, err = ParseOutlineMode("typed")
+	require.NoError(t, err)
+	assert.Equal(t, OutlineModeTyped, mode)
+
+	_, err = ParseOutlineMode("ssa")
+	assert.Error(t, err)
+}
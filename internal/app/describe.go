@@ -0,0 +1,442 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/printer"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/go/packages"
+)
+
+const DefaultMaxSourceLines = 200
+
+// SymbolDescription describes a single Go declaration.
+type SymbolDescription struct {
+	Symbol    string   // Qualified name (e.g. "net/http.Client.Do")
+	Kind      string   // "func", "method", "struct", "interface", "type", "var", "const", "field"
+	Info      string   // Additional info as in the outline (e.g. "3 fields")
+	Package   string   // Import path of the declaring package
+	Module    string   // Module and version, "std" for the standard library
+	Signature string   // Declaration without body
+	Doc       string   // Doc comment text
+	Filename  string   // Source file of the declaration
+	StartLine int      // First line of the declaration (including doc comment)
+	EndLine   int      // Last line of the declaration
+	Source    []string // Source lines from StartLine to EndLine
+	Methods   []string // Method set for types ("*" prefix for pointer receivers)
+	Embedded  []string // Embedded fields (structs) or embedded interfaces
+}
+
+// DescribeSymbol resolves query from directory and describes the declaration.
+// The symbol may live in the local module, the module cache or GOROOT; query is
+// either "file:line:column" or a qualified name such as "net/http.Client.Do"
+// or "internal/app.ExtractDeclarations".
+func DescribeSymbol(ctx context.Context, directory, query string) (*SymbolDescription, error) {
+	pkg, obj, err := loadSymbol(ctx, directory, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return describeObject(pkg, obj)
+}
+
+// loadSymbol loads the package declaring the symbol with full syntax and
+// returns the object as seen from that package.
+func loadSymbol(
+	ctx context.Context, directory, query string,
+) (*packages.Package, types.Object, error) {
+	if file, line, col, ok := parsePositionQuery(query); ok {
+		absFile, err := filepath.Abs(file)
+		if err != nil {
+			absFile = file
+		}
+		pkgs, err := loadTypedPackages(ctx, directory, false, "file="+absFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		target, err := resolvePosition(pkgs, absFile, line, col)
+		if err != nil {
+			return nil, nil, err
+		}
+		obj := target.Object
+		if obj.Pkg() == nil {
+			return nil, nil, errors.Errorf("%s is a predeclared identifier", obj.Name())
+		}
+		if obj.Pkg() == target.Pkg.Types {
+			return target.Pkg, obj, nil
+		}
+
+		// Declared in another package: load it from source
+		declPkgs, err := loadTypedPackages(ctx, directory, false, obj.Pkg().Path())
+		if err != nil {
+			return nil, nil, err
+		}
+		declObj := findDefinition(declPkgs[0], target.Fset.Position(obj.Pos()), obj)
+		if declObj == nil {
+			return nil, nil, errors.Errorf("declaration of %s not found", qualifiedObjectName(obj))
+		}
+		return declPkgs[0], declObj, nil
+	}
+
+	var lastErr error
+	for _, candidate := range symbolPackageCandidates(directory, query) {
+		pkgs, err := loadTypedPackages(ctx, directory, false, candidate.pkgPath)
+		if err != nil || len(pkgs) != 1 || len(pkgs[0].Errors) > 0 && pkgs[0].TypesInfo == nil {
+			continue
+		}
+		pkg := pkgs[0]
+		if pkg.Types == nil || len(pkg.Syntax) == 0 {
+			continue
+		}
+		obj, err := lookupMember(pkg.Types, candidate.member)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return pkg, obj, nil
+	}
+
+	if lastErr != nil {
+		return nil, nil, lastErr
+	}
+	return nil, nil, errors.Errorf("cannot resolve %q: package not found", query)
+}
+
+type symbolCandidate struct {
+	pkgPath string
+	member  string
+}
+
+// symbolPackageCandidates splits a qualified name into possible package
+// path/member pairs. Paths relative to the enclosing module are also tried.
+func symbolPackageCandidates(directory, query string) []symbolCandidate {
+	_, modulePath := findModuleRoot(directory)
+
+	var candidates []symbolCandidate
+	slash := strings.LastIndex(query, "/")
+	for i := slash + 1; i < len(query); i++ {
+		if query[i] != '.' {
+			continue
+		}
+		pkgPath, member := query[:i], query[i+1:]
+		candidates = append(candidates, symbolCandidate{pkgPath: pkgPath, member: member})
+		if modulePath != "" && !strings.HasPrefix(pkgPath, modulePath) {
+			candidates = append(candidates, symbolCandidate{
+				pkgPath: modulePath + "/" + strings.TrimPrefix(pkgPath, "./"),
+				member:  member,
+			})
+		}
+	}
+	return candidates
+}
+
+// findModuleRoot walks up from dir to the nearest go.mod and returns
+// its directory and module path. Both are empty if no go.mod is found.
+func findModuleRoot(dir string) (string, string) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", ""
+	}
+	for {
+		if moduleName, err := findModuleName(absDir); err == nil {
+			return absDir, moduleName
+		}
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return "", ""
+		}
+		absDir = parent
+	}
+}
+
+// findDefinition finds the object of the same name and kind defined in pkg
+// at the given position. Positions are compared by base file name and line only
+// because export data may record files under a different root (e.g. "$GOROOT")
+// and does not preserve columns.
+func findDefinition(pkg *packages.Package, pos token.Position, obj types.Object) types.Object {
+	if pkg.TypesInfo == nil {
+		return nil
+	}
+	for id, def := range pkg.TypesInfo.Defs {
+		if def == nil || id.Name != obj.Name() || objectKind(def) != objectKind(obj) {
+			continue
+		}
+		defPos := pkg.Fset.Position(def.Pos())
+		if defPos.Line == pos.Line &&
+			filepath.Base(defPos.Filename) == filepath.Base(pos.Filename) {
+			return def
+		}
+	}
+	return nil
+}
+
+// describeObject builds the description of obj from the syntax of pkg.
+func describeObject(pkg *packages.Package, obj types.Object) (*SymbolDescription, error) {
+	desc := &SymbolDescription{
+		Symbol:  qualifiedObjectName(obj),
+		Kind:    objectKind(obj),
+		Package: obj.Pkg().Path(),
+		Module:  moduleLabel(pkg),
+	}
+
+	qualifier := packageNameQualifier(obj.Pkg())
+	if tn, ok := obj.(*types.TypeName); ok {
+		desc.Methods = methodSet(tn, qualifier)
+		desc.Embedded = embeddedTypes(tn, qualifier)
+	}
+
+	file := fileForPos(pkg, obj.Pos())
+	if file == nil {
+		desc.Signature = types.ObjectString(obj, qualifier)
+		return desc, nil
+	}
+
+	path, _ := astutil.PathEnclosingInterval(file, obj.Pos(), obj.Pos())
+	node, doc := declarationNode(path)
+	if node == nil {
+		desc.Signature = types.ObjectString(obj, qualifier)
+		return desc, nil
+	}
+
+	desc.Signature = declarationSignature(pkg.Fset, node, obj, qualifier)
+	if spec, ok := node.(*ast.TypeSpec); ok {
+		desc.Kind = getTypeSpecType(spec)
+		desc.Info = getTypeSpecInfo(spec)
+	} else if decl, ok := node.(*ast.GenDecl); ok && len(decl.Specs) == 1 {
+		if spec, ok := decl.Specs[0].(*ast.TypeSpec); ok {
+			desc.Kind = getTypeSpecType(spec)
+			desc.Info = getTypeSpecInfo(spec)
+		}
+	}
+	if doc != nil {
+		desc.Doc = strings.TrimSpace(doc.Text())
+	}
+
+	start := node.Pos()
+	if doc != nil && doc.Pos() < start {
+		start = doc.Pos()
+	}
+	startPos := pkg.Fset.Position(start)
+	endPos := pkg.Fset.Position(node.End())
+	desc.Filename = startPos.Filename
+	desc.StartLine = startPos.Line
+	desc.EndLine = endPos.Line
+
+	content, err := os.ReadFile(startPos.Filename)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read source")
+	}
+	lines := strings.Split(string(content), "\n")
+	if desc.EndLine <= len(lines) {
+		desc.Source = lines[desc.StartLine-1 : desc.EndLine]
+	}
+
+	return desc, nil
+}
+
+// declarationNode picks the declaration enclosing an identifier from an AST
+// path and returns it with its doc comment. Single-spec GenDecls are returned
+// whole so that the "type"/"var" keyword and the doc comment are included.
+func declarationNode(path []ast.Node) (ast.Node, *ast.CommentGroup) {
+	for i, n := range path {
+		switch node := n.(type) {
+		case *ast.FuncDecl:
+			return node, node.Doc
+		case *ast.Field:
+			return node, node.Doc
+		case *ast.TypeSpec, *ast.ValueSpec:
+			var specDoc *ast.CommentGroup
+			if ts, ok := node.(*ast.TypeSpec); ok {
+				specDoc = ts.Doc
+			} else {
+				specDoc = node.(*ast.ValueSpec).Doc
+			}
+			if i+1 < len(path) {
+				if gd, ok := path[i+1].(*ast.GenDecl); ok && len(gd.Specs) == 1 {
+					return gd, gd.Doc
+				}
+			}
+			return node, specDoc
+		case *ast.AssignStmt:
+			return node, nil
+		}
+	}
+	return nil, nil
+}
+
+// declarationSignature renders a declaration without its body or fields.
+func declarationSignature(
+	fset *token.FileSet, node ast.Node, obj types.Object, qualifier types.Qualifier,
+) string {
+	switch n := node.(type) {
+	case *ast.FuncDecl:
+		stripped := *n
+		stripped.Body = nil
+		stripped.Doc = nil
+		var buf bytes.Buffer
+		if err := printer.Fprint(&buf, fset, &stripped); err == nil {
+			return buf.String()
+		}
+	case *ast.TypeSpec, *ast.GenDecl:
+		if tn, ok := obj.(*types.TypeName); ok {
+			under := tn.Type().Underlying()
+			switch under.(type) {
+			case *types.Struct:
+				return fmt.Sprintf("type %s struct", tn.Name())
+			case *types.Interface:
+				return fmt.Sprintf("type %s interface", tn.Name())
+			}
+			if tn.IsAlias() {
+				return fmt.Sprintf("type %s = %s", tn.Name(), types.TypeString(tn.Type(), qualifier))
+			}
+			return fmt.Sprintf("type %s %s", tn.Name(), types.TypeString(under, qualifier))
+		}
+	}
+	return types.ObjectString(obj, qualifier)
+}
+
+// packageNameQualifier omits the package for objects of pkg and uses the
+// package name (rather than the full path) for all others.
+func packageNameQualifier(pkg *types.Package) types.Qualifier {
+	return func(other *types.Package) string {
+		if other == pkg {
+			return ""
+		}
+		return other.Name()
+	}
+}
+
+// methodSet lists the exported methods callable on *T (or T for interfaces).
+// Methods in the method set of *T but not of T are prefixed with "*". A method
+// promoted through an embedded pointer is in both, so it is not marked.
+func methodSet(tn *types.TypeName, qualifier types.Qualifier) []string {
+	t := tn.Type()
+	valueSet := types.NewMethodSet(t)
+	mset := valueSet
+	if !types.IsInterface(t) {
+		mset = types.NewMethodSet(types.NewPointer(t))
+	}
+
+	methods := make([]string, 0, mset.Len())
+	for i := 0; i < mset.Len(); i++ {
+		sel := mset.At(i)
+		fn, ok := sel.Obj().(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+		sig := types.TypeString(fn.Signature(), qualifier)
+		entry := fn.Name() + strings.TrimPrefix(sig, "func")
+		if valueSet.Lookup(fn.Pkg(), fn.Name()) == nil {
+			entry = "*" + entry
+		}
+		if len(sel.Index()) > 1 {
+			entry += " (promoted)"
+		}
+		methods = append(methods, entry)
+	}
+	return methods
+}
+
+// embeddedTypes lists embedded struct fields or embedded interfaces of a type.
+func embeddedTypes(tn *types.TypeName, qualifier types.Qualifier) []string {
+	var embedded []string
+	switch under := tn.Type().Underlying().(type) {
+	case *types.Struct:
+		for i := 0; i < under.NumFields(); i++ {
+			if f := under.Field(i); f.Embedded() {
+				embedded = append(embedded, types.TypeString(f.Type(), qualifier))
+			}
+		}
+	case *types.Interface:
+		for i := 0; i < under.NumEmbeddeds(); i++ {
+			embedded = append(embedded, types.TypeString(under.EmbeddedType(i), qualifier))
+		}
+	}
+	return embedded
+}
+
+func fileForPos(pkg *packages.Package, pos token.Pos) *ast.File {
+	for _, f := range pkg.Syntax {
+		if f.FileStart <= pos && pos <= f.FileEnd {
+			return f
+		}
+	}
+	return nil
+}
+
+// moduleLabel describes where a package comes from.
+func moduleLabel(pkg *packages.Package) string {
+	if pkg.Module == nil {
+		return "std"
+	}
+	if pkg.Module.Main {
+		return pkg.Module.Path + " (local)"
+	}
+	if pkg.Module.Version != "" {
+		return pkg.Module.Path + "@" + pkg.Module.Version
+	}
+	return pkg.Module.Path
+}
+
+// FormatSymbolDescription renders a symbol description as text.
+// At most maxSourceLines source lines are shown (0 means no limit).
+func FormatSymbolDescription(desc *SymbolDescription, maxSourceLines int) string {
+	var sb strings.Builder
+
+	kind := desc.Kind
+	if desc.Info != "" {
+		kind = fmt.Sprintf("%s, %s", desc.Kind, desc.Info)
+	}
+	sb.WriteString(fmt.Sprintf("Symbol: %s (%s)\n", desc.Symbol, kind))
+	sb.WriteString(fmt.Sprintf("Package: %s\n", desc.Package))
+	sb.WriteString(fmt.Sprintf("Module: %s\n", desc.Module))
+	if desc.Filename != "" {
+		sb.WriteString(
+			fmt.Sprintf("Location: %s:%d-%d\n", desc.Filename, desc.StartLine, desc.EndLine),
+		)
+	}
+
+	sb.WriteString(fmt.Sprintf("\n== Signature ==\n%s\n", desc.Signature))
+
+	if desc.Doc != "" {
+		sb.WriteString(fmt.Sprintf("\n== Doc ==\n%s\n", desc.Doc))
+	}
+
+	if len(desc.Embedded) > 0 {
+		sb.WriteString("\n== Embedded ==\n")
+		for _, e := range desc.Embedded {
+			sb.WriteString(fmt.Sprintf("- %s\n", e))
+		}
+	}
+
+	if len(desc.Methods) > 0 {
+		sb.WriteString("\n== Methods ==\n")
+		for _, m := range desc.Methods {
+			sb.WriteString(fmt.Sprintf("- %s\n", m))
+		}
+	}
+
+	if len(desc.Source) > 0 {
+		sb.WriteString("\n== Source ==\n")
+		for i, line := range desc.Source {
+			if maxSourceLines > 0 && i >= maxSourceLines {
+				sb.WriteString(
+					fmt.Sprintf("... (%d more lines truncated)\n", len(desc.Source)-maxSourceLines),
+				)
+				break
+			}
+			sb.WriteString(fmt.Sprintf("%5d: %s\n", desc.StartLine+i, line))
+		}
+	}
+
+	return sb.String()
+}
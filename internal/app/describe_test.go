@@ -0,0 +1,116 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var describeTestFiles = map[string]string{
+	"store/store.go": `package store
+
+import "sync"
+
+// Base provides an identifier.
+type Base struct{ ID string }
+
+// Name returns the identifier.
+func (b Base) Name() string { return b.ID }
+
+// Store keeps values in memory.
+type Store struct {
+	Base
+	sync.Mutex
+	values map[string]string
+}
+
+// Put stores a value.
+func (s *Store) Put(key, value string) {
+	s.values[key] = value
+}
+
+func (s *Store) reset() {}
+
+// New creates an empty Store.
+func New() *Store {
+	return &Store{values: map[string]string{}}
+}
+
+// Shared embeds a pointer to a Store.
+type Shared struct{ *Store }
+`,
+	"cmd/main.go": `package main
+
+import "example.com/sample/store"
+
+func main() {
+	s := store.New()
+	s.Put("a", "b")
+}
+`,
+}
+
+func TestDescribeSymbol_Type(t *testing.T) {
+	dir := writeTypedTestModule(t, describeTestFiles)
+
+	desc, err := DescribeSymbol(context.Background(), dir, "store.Store")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/sample/store.Store", desc.Symbol)
+	assert.Equal(t, "struct", desc.Kind)
+	assert.Equal(t, "example.com/sample (local)", desc.Module)
+	assert.Equal(t, "type Store struct", desc.Signature)
+	assert.Equal(t, "Store keeps values in memory.", desc.Doc)
+	assert.Equal(t, []string{"Base", "sync.Mutex"}, desc.Embedded)
+	assert.Contains(t, desc.Methods, "Name() string (promoted)")
+	assert.Contains(t, desc.Methods, "*Lock() (promoted)")
+	assert.Contains(t, desc.Methods, "*Put(key string, value string)")
+	for _, m := range desc.Methods {
+		assert.NotContains(t, m, "reset")
+	}
+	assert.Equal(t, 11, desc.StartLine)
+	assert.Equal(t, 16, desc.EndLine)
+}
+
+func TestDescribeSymbol_EmbeddedPointer(t *testing.T) {
+	dir := writeTypedTestModule(t, describeTestFiles)
+
+	desc, err := DescribeSymbol(context.Background(), dir, "store.Shared")
+	require.NoError(t, err)
+
+	// Methods promoted through *Store are callable on a Shared value.
+	assert.Contains(t, desc.Methods, "Put(key string, value string) (promoted)")
+	assert.Contains(t, desc.Methods, "Lock() (promoted)")
+	assert.Contains(t, desc.Methods, "Name() string (promoted)")
+	for _, m := range desc.Methods {
+		assert.NotContains(t, m, "*")
+	}
+}
+
+func TestDescribeSymbol_Position(t *testing.T) {
+	dir := writeTypedTestModule(t, describeTestFiles)
+
+	// "Put" in cmd/main.go resolves to its declaration in another package
+	query := filepath.Join(dir, "cmd", "main.go") + ":7:4"
+	desc, err := DescribeSymbol(context.Background(), dir, query)
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/sample/store.Store.Put", desc.Symbol)
+	assert.Equal(t, "method", desc.Kind)
+	assert.Equal(t, "func (s *Store) Put(key, value string)", desc.Signature)
+
+	output := FormatSymbolDescription(desc, 2)
+	assert.Contains(t, output, "== Doc ==\nPut stores a value.\n")
+	assert.Contains(t, output, "   18: // Put stores a value.\n")
+	assert.Contains(t, output, "... (2 more lines truncated)\n")
+}
+
+func TestDescribeSymbol_NotFound(t *testing.T) {
+	dir := writeTypedTestModule(t, describeTestFiles)
+
+	_, err := DescribeSymbol(context.Background(), dir, "store.Missing")
+	assert.Error(t, err)
+}
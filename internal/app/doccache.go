@@ -0,0 +1,232 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+	"golang.org/x/net/html"
+)
+
+// DefaultDocCacheTTL is how long fetched documentation is used without revalidation.
+const DefaultDocCacheTTL = 30 * time.Minute
+
+// docCacheVersion is the format of parsed documents on disk. Bump it whenever
+// a parser's output changes so that stale entries are refetched.
+const docCacheVersion = 1
+
+// DocCacheConfig configures the documentation cache.
+type DocCacheConfig struct {
+	Dir string        // Directory of the on-disk cache; empty keeps documents in memory only
+	TTL time.Duration // Time after which cached documents are revalidated
+}
+
+var (
+	// Global cache for storing parsed documentation
+	// TTL: 30 minutes unless configured, cleanup interval: 10 minutes
+	docCache = cache.New(DefaultDocCacheTTL, 10*time.Minute)
+
+	docCacheTTL  = DefaultDocCacheTTL
+	docDiskCache *infra.DiskCache
+)
+
+// ConfigureDocCache sets the TTL of cached documentation and enables the disk cache.
+// It must be called before any documentation is read.
+func ConfigureDocCache(cfg DocCacheConfig) error {
+	if cfg.TTL <= 0 {
+		return errors.Errorf("invalid cache TTL: %s", cfg.TTL)
+	}
+	docCacheTTL = cfg.TTL
+	docCache = cache.New(cfg.TTL, 10*time.Minute)
+
+	docDiskCache = nil
+	if cfg.Dir != "" {
+		dc, err := infra.NewDiskCache(cfg.Dir)
+		if err != nil {
+			return err
+		}
+		docDiskCache = dc
+	}
+	return nil
+}
+
+// fetchCachedDoc returns the document at url parsed by parse. Documents are
+// looked up in memory, then on disk; entries older than the TTL are revalidated
+// with a conditional request, and served stale if the server fails or cannot be reached.
+func fetchCachedDoc[T any](
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	cacheKey, url string,
+	parse func(*html.Node) (T, error),
+) (T, error) {
+	var zero T
+	if cached, found := docCache.Get(cacheKey); found {
+		return cached.(T), nil
+	}
+
+	entry := loadDiskEntry(cacheKey, url)
+	if entry != nil && time.Since(entry.FetchedAt) < docCacheTTL {
+		if doc, err := decodeDiskEntry[T](entry); err == nil {
+			docCache.Set(cacheKey, doc, cache.DefaultExpiration)
+			return doc, nil
+		}
+	}
+
+	var etag, lastModified string
+	if entry != nil {
+		etag, lastModified = entry.ETag, entry.LastModified
+	}
+	resp, err := httpcli.HttpGetConditional(ctx, url, etag, lastModified)
+	if err != nil {
+		if entry != nil && !errors.Is(err, ErrNotFound) && ctx.Err() == nil {
+			if doc, derr := decodeDiskEntry[T](entry); derr == nil {
+				slog.Warn("serving stale documentation", "key", cacheKey, "error", err)
+				return doc, nil
+			}
+		}
+		return zero, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified {
+		if entry == nil {
+			return zero, errors.New("unexpected 304 response without validators")
+		}
+		doc, err := decodeDiskEntry[T](entry)
+		if err != nil {
+			return zero, err
+		}
+		entry.FetchedAt = time.Now()
+		storeDiskEntry(entry)
+		docCache.Set(cacheKey, doc, cache.DefaultExpiration)
+		return doc, nil
+	}
+	defer resp.Body.Close()
+
+	node, err := html.Parse(resp.Body)
+	if err != nil {
+		return zero, errors.Wrap(err, "failed to parse HTML")
+	}
+	doc, err := parse(node)
+	if err != nil {
+		return zero, err
+	}
+
+	docCache.Set(cacheKey, doc, cache.DefaultExpiration)
+	if docDiskCache != nil {
+		data, err := json.Marshal(doc)
+		if err != nil {
+			return zero, errors.Wrap(err, "failed to encode document")
+		}
+		storeDiskEntry(&infra.DiskCacheEntry{
+			Key:          cacheKey,
+			URL:          url,
+			Version:      docCacheVersion,
+			FetchedAt:    time.Now(),
+			ETag:         resp.ETag,
+			LastModified: resp.LastModified,
+			Data:         data,
+		})
+	}
+	return doc, nil
+}
+
+// loadDiskEntry returns the disk cache entry of cacheKey if it was fetched from url
+// in the current format. Entries from another documentation site are ignored.
+func loadDiskEntry(cacheKey, url string) *infra.DiskCacheEntry {
+	if docDiskCache == nil {
+		return nil
+	}
+	entry, ok := docDiskCache.Get(cacheKey)
+	if !ok || entry.Version != docCacheVersion || entry.URL != url {
+		return nil
+	}
+	return entry
+}
+
+// storeDiskEntry writes entry to the disk cache. Failures only cost a refetch
+// later, so they are logged rather than returned.
+func storeDiskEntry(entry *infra.DiskCacheEntry) {
+	if docDiskCache == nil {
+		return
+	}
+	if err := docDiskCache.Put(entry); err != nil {
+		slog.Warn("failed to write documentation cache", "key", entry.Key, "error", err)
+	}
+}
+
+func decodeDiskEntry[T any](entry *infra.DiskCacheEntry) (T, error) {
+	var doc T
+	if err := json.Unmarshal(entry.Data, &doc); err != nil {
+		return doc, errors.Wrap(err, "failed to decode cached document")
+	}
+	return doc, nil
+}
+
+// WarmDocCache fetches documentation into the cache. ref names the documentation
+// as "<source>:<name>", e.g. "godoc:golang.org/x/net/html@v0.40.0", "rustdoc:serde"
+// or "pydoc:json".
+func WarmDocCache(ctx context.Context, httpcli *infra.HttpClient, ref string) error {
+	source, name, ok := strings.Cut(ref, ":")
+	if !ok || name == "" {
+		return errors.Errorf("invalid reference %q: expected <source>:<name>", ref)
+	}
+
+	var err error
+	switch source {
+	case "godoc":
+		pkg, version, _ := strings.Cut(name, "@")
+		_, err = fetchGoDoc(ctx, httpcli, pkg, version)
+	case "rustdoc":
+		_, err = fetchRustDoc(ctx, httpcli, name)
+	case "pydoc":
+		_, err = fetchPyDoc(ctx, httpcli, name)
+	default:
+		return errors.Errorf(
+			"unknown documentation source %q: expected godoc, rustdoc or pydoc",
+			source,
+		)
+	}
+	return err
+}
+
+// DocCacheStats summarizes the disk cache in dir.
+func DocCacheStats(dir string) (string, error) {
+	dc, err := infra.NewDiskCache(dir)
+	if err != nil {
+		return "", err
+	}
+	stats, err := dc.Stats()
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Cache directory: %s\n", dc.Dir()))
+	sb.WriteString(fmt.Sprintf("Entries: %d (%d bytes)\n", stats.Entries, stats.Bytes))
+	for _, kind := range []string{"godoc", "rustdoc", "pydoc"} {
+		if n := stats.Kinds[kind]; n > 0 {
+			sb.WriteString(fmt.Sprintf("  %s: %d\n", kind, n))
+		}
+	}
+	if stats.Entries > 0 {
+		sb.WriteString(fmt.Sprintf("Oldest fetch: %s\n", stats.Oldest.Format(time.RFC3339)))
+		sb.WriteString(fmt.Sprintf("Newest fetch: %s\n", stats.Newest.Format(time.RFC3339)))
+	}
+	return sb.String(), nil
+}
+
+// ClearDocCache removes all entries of the disk cache in dir.
+func ClearDocCache(dir string) (int, error) {
+	dc, err := infra.NewDiskCache(dir)
+	if err != nil {
+		return 0, err
+	}
+	return dc.Clear()
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+func TestFetchCachedDoc_Revalidation(t *testing.T) {
+	oldCache, oldTTL, oldDisk := docCache, docCacheTTL, docDiskCache
+	t.Cleanup(func() { docCache, docCacheTTL, docDiskCache = oldCache, oldTTL, oldDisk })
+
+	page, err := os.ReadFile(filepath.Join("testdata", "pydoc_doc_json.html"))
+	require.NoError(t, err)
+
+	var requests, revalidations int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			revalidations++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write(page)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, ConfigureDocCache(DocCacheConfig{Dir: dir, TTL: time.Hour}))
+
+	parse := func(doc *html.Node) (*parsedDocument, error) {
+		_, document := parsePyDocPage(doc)
+		return document, nil
+	}
+	cfg := infra.DefaultHttpClientConfig()
+	cfg.BaseDelay, cfg.MaxDelay = time.Millisecond, time.Millisecond
+	httpcli := infra.NewHttpClientWithConfig(cfg)
+
+	first, err := fetchCachedDoc(context.Background(), httpcli, "pydoc:json", srv.URL, parse)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	// A restart loses the memory cache but finds the fresh document on disk
+	require.NoError(t, ConfigureDocCache(DocCacheConfig{Dir: dir, TTL: time.Hour}))
+	second, err := fetchCachedDoc(context.Background(), httpcli, "pydoc:json", srv.URL, parse)
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, first, second)
+
+	// Once the TTL has passed, the document is revalidated
+	require.NoError(t, ConfigureDocCache(DocCacheConfig{Dir: dir, TTL: time.Nanosecond}))
+	third, err := fetchCachedDoc(context.Background(), httpcli, "pydoc:json", srv.URL, parse)
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, revalidations)
+	assert.Equal(t, first, third)
+
+	// A stale document is served when the server is unreachable
+	srv.Close()
+	docCache.Flush()
+	fourth, err := fetchCachedDoc(context.Background(), httpcli, "pydoc:json", srv.URL, parse)
+	require.NoError(t, err)
+	assert.Equal(t, first, fourth)
+
+	stats, err := DocCacheStats(dir)
+	require.NoError(t, err)
+	assert.Contains(t, stats, "Entries: 1 ")
+	assert.Contains(t, stats, "  pydoc: 1\n")
+
+	removed, err := ClearDocCache(dir)
+	require.NoError(t, err)
+	assert.Equal(t, 1, removed)
+}
+
+func TestWarmDocCache_InvalidReference(t *testing.T) {
+	httpcli := infra.NewHttpClient()
+	assert.Error(t, WarmDocCache(context.Background(), httpcli, "serde"))
+	assert.Error(t, WarmDocCache(context.Background(), httpcli, "javadoc:java.util"))
+}
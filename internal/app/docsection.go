@@ -0,0 +1,114 @@
+package app
+
+import (
+	"strings"
+)
+
+// docSection is a part of a parsed document addressed by its HTML anchor id
+// (e.g. "Client.Do" on pkg.go.dev, "json.dumps" on docs.python.org).
+type docSection struct {
+	Anchor string
+	Level  int // Heading depth: a section ends where the next one of the same or a lower level starts
+	Start  int // First line (0-based)
+	End    int // Line after the last one
+}
+
+// parsedDocument is the text of a documentation page together with
+// an index of its sections.
+type parsedDocument struct {
+	Text     string
+	Sections []docSection
+}
+
+// section returns the text of the first section whose anchor matches one of
+// anchors, trying exact matches before case-insensitive ones.
+func (d *parsedDocument) section(anchors ...string) (string, bool) {
+	for _, fold := range []bool{false, true} {
+		for _, anchor := range anchors {
+			for _, s := range d.Sections {
+				if s.Anchor == anchor || (fold && strings.EqualFold(s.Anchor, anchor)) {
+					return d.lines(s.Start, s.End), true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// lines returns lines [start, end) of the document without surrounding blank lines.
+func (d *parsedDocument) lines(start, end int) string {
+	lines := strings.Split(d.Text, "\n")
+	if end > len(lines) {
+		end = len(lines)
+	}
+	if start >= end {
+		return ""
+	}
+	return strings.Trim(strings.Join(lines[start:end], "\n"), "\n")
+}
+
+// docBuilder accumulates the text of a parsed document and records where
+// sections start. It is used in place of a strings.Builder by the parsers.
+type docBuilder struct {
+	sb       strings.Builder
+	lines    int
+	sections []docSection
+}
+
+func (b *docBuilder) WriteString(s string) {
+	b.sb.WriteString(s)
+	b.lines += strings.Count(s, "\n")
+}
+
+func (b *docBuilder) String() string {
+	return b.sb.String()
+}
+
+// startSection starts a section at the line the next write begins on.
+// Empty anchors are ignored.
+func (b *docBuilder) startSection(anchor string, level int) {
+	if anchor == "" {
+		return
+	}
+	b.sections = append(b.sections, docSection{Anchor: anchor, Level: level, Start: b.lines})
+}
+
+// document finishes the document and computes the end of every section.
+func (b *docBuilder) document() *parsedDocument {
+	text := b.sb.String()
+	total := strings.Count(text, "\n") + 1
+
+	sections := make([]docSection, len(b.sections))
+	copy(sections, b.sections)
+	for i := range sections {
+		sections[i].End = total
+		for _, next := range sections[i+1:] {
+			if next.Level <= sections[i].Level {
+				sections[i].End = next.Start
+				break
+			}
+		}
+	}
+
+	return &parsedDocument{Text: text, Sections: sections}
+}
+
+// pageLines applies line-based paging to a document.
+// Returns: content, totalLines, hasMore
+func pageLines(document string, offset, limit int) (string, int, bool) {
+	lines := strings.Split(document, "\n")
+	totalLines := len(lines)
+
+	startIdx := offset
+	if startIdx >= totalLines {
+		return "", totalLines, false
+	}
+
+	endIdx := startIdx + limit
+	hasMore := endIdx < totalLines
+	if endIdx > totalLines {
+		endIdx = totalLines
+	}
+
+	return strings.Join(lines[startIdx:endIdx], "\n"), totalLines, hasMore
+}
@@ -0,0 +1,65 @@
+package app
+
+import (
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DocSites holds the base URLs of the documentation sites. They can point to
+// mirrors such as a private pkgsite instance, or to a test server.
+type DocSites struct {
+	GoDoc   string // pkgsite, e.g. "https://pkg.go.dev"
+	RustDoc string // docs.rs, e.g. "https://docs.rs"
+	PyDoc   string // Python documentation of a version, e.g. "https://docs.python.org/3"
+}
+
+// Environment variables overriding the default documentation sites.
+const (
+	EnvGoDocURL   = "GODEVMCP_GODOC_URL"
+	EnvRustDocURL = "GODEVMCP_RUSTDOC_URL"
+	EnvPyDocURL   = "GODEVMCP_PYDOC_URL"
+)
+
+var docSites = DefaultDocSites()
+
+// DefaultDocSites returns the public documentation sites.
+func DefaultDocSites() DocSites {
+	return DocSites{
+		GoDoc:   "https://pkg.go.dev",
+		RustDoc: "https://docs.rs",
+		PyDoc:   "https://docs.python.org/3",
+	}
+}
+
+// DocSitesFromEnv returns the default documentation sites overridden by
+// GODEVMCP_GODOC_URL, GODEVMCP_RUSTDOC_URL and GODEVMCP_PYDOC_URL.
+func DocSitesFromEnv() DocSites {
+	sites := DefaultDocSites()
+	if v := os.Getenv(EnvGoDocURL); v != "" {
+		sites.GoDoc = v
+	}
+	if v := os.Getenv(EnvRustDocURL); v != "" {
+		sites.RustDoc = v
+	}
+	if v := os.Getenv(EnvPyDocURL); v != "" {
+		sites.PyDoc = v
+	}
+	return sites
+}
+
+// ConfigureDocSites sets the documentation sites all fetches go to.
+// It must be called before any documentation is read.
+func ConfigureDocSites(sites DocSites) error {
+	for _, base := range []*string{&sites.GoDoc, &sites.RustDoc, &sites.PyDoc} {
+		u, err := url.Parse(*base)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.Errorf("invalid documentation site URL: %q", *base)
+		}
+		*base = strings.TrimSuffix(*base, "/")
+	}
+	docSites = sites
+	return nil
+}
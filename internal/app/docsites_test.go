@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDocSites_Mirror reads documentation from a mirror serving the fixtures.
+func TestDocSites_Mirror(t *testing.T) {
+	oldSites, oldCache, oldTTL, oldDisk := docSites, docCache, docCacheTTL, docDiskCache
+	t.Cleanup(func() {
+		docSites, docCache, docCacheTTL, docDiskCache = oldSites, oldCache, oldTTL, oldDisk
+	})
+
+	pages := map[string]string{
+		"/go/net/http":                   "doc_net_http.html",
+		"/rust/releases/search":          "rustdoc_search_serde.html",
+		"/rust/serde/latest/serde/":      "rustdoc_doc_serde.html",
+		"/python/3/py-modindex.html":     "pydoc_modindex.html",
+		"/python/3/library/json.html":    "pydoc_doc_json.html",
+		"/rust/serde/latest/serde/de":    "",
+		"/python/3/library/unknown.html": "",
+	}
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		fixture := pages[r.URL.Path]
+		if fixture == "" {
+			http.NotFound(w, r)
+			return
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", fixture))
+	}))
+	defer srv.Close()
+
+	require.NoError(t, ConfigureDocSites(DocSites{
+		GoDoc:   srv.URL + "/go/",
+		RustDoc: srv.URL + "/rust",
+		PyDoc:   srv.URL + "/python/3",
+	}))
+	require.NoError(t, ConfigureDocCache(DocCacheConfig{TTL: time.Hour}))
+
+	cfg := infra.DefaultHttpClientConfig()
+	cfg.MaxRetries = 0
+	httpcli := infra.NewHttpClientWithConfig(cfg)
+	ctx := context.Background()
+
+	content, _, _, err := ReadGoDocPaged(
+		ctx, httpcli, "", "net/http", "", "Client.Do", DocSourceRemote, 0, 1000)
+	require.NoError(t, err)
+	assert.Contains(t, content, "func (c *Client) Do")
+
+	results, err := SearchRustDoc(ctx, httpcli, "serde")
+	require.NoError(t, err)
+	assert.Contains(t, results, "URL: "+srv.URL+"/rust/")
+
+	content, _, _, err = ReadRustDocPaged(ctx, httpcli, "serde", "", 0, 1000)
+	require.NoError(t, err)
+	assert.Contains(t, content, "Serde")
+
+	_, _, _, err = ReadRustDocPaged(ctx, httpcli, "serde/de", "", 0, 1000)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	results, err = SearchPyDoc(ctx, httpcli, "json")
+	require.NoError(t, err)
+	assert.Contains(t, results, "URL: "+srv.URL+"/python/3/library/json.html")
+
+	content, _, _, err = ReadPyDocPaged(ctx, httpcli, "json", "dumps", 0, 1000)
+	require.NoError(t, err)
+	assert.Contains(t, content, "json.dumps")
+
+	_, _, _, err = ReadPyDocPaged(ctx, httpcli, "unknown", "", 0, 1000)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, path := range requested {
+		assert.Contains(t, pages, path, "unexpected request to the mirror")
+	}
+}
+
+func TestConfigureDocSites(t *testing.T) {
+	oldSites := docSites
+	t.Cleanup(func() { docSites = oldSites })
+
+	sites := DefaultDocSites()
+	sites.PyDoc = "docs.python.org/3"
+	assert.Error(t, ConfigureDocSites(sites))
+
+	sites.PyDoc = "ftp://docs.python.org/3"
+	assert.Error(t, ConfigureDocSites(sites))
+
+	t.Setenv(EnvGoDocURL, "http://pkgsite.internal:8080/")
+	require.NoError(t, ConfigureDocSites(DocSitesFromEnv()))
+	assert.Equal(t, "http://pkgsite.internal:8080", docSites.GoDoc)
+	assert.Equal(t, DefaultDocSites().RustDoc, docSites.RustDoc)
+}
@@ -4,21 +4,35 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/fpt/go-dev-mcp/internal/repository"
 	"github.com/pkg/errors"
 )
 
-func GitHubSearchCode(
-	ctx context.Context, github repository.GitHubClient, query string, language, repo *string,
+// DefaultSearchCodePerPage is the number of code search results per page.
+// Each result takes one more request to locate its fragments.
+const DefaultSearchCodePerPage = 10
+
+// maxSearchResults is the number of results the GitHub search API returns at most.
+const maxSearchResults = 1000
+
+// ForgeSearchCode searches code on a GitHub or GitLab host and formats one page of results.
+func ForgeSearchCode(
+	ctx context.Context, forge repository.Forge, query string,
+	opt *repository.SearchCodeOption,
 ) (string, error) {
-	// Perform the search
-	opt := &repository.SearchCodeOption{
-		Language: language,
-		Repo:     repo,
+	options := *opt
+	if options.Page <= 0 {
+		options.Page = 1
+	}
+	if options.PerPage <= 0 {
+		options.PerPage = DefaultSearchCodePerPage
 	}
-	result, err := github.SearchCode(ctx, query, opt)
+
+	// Perform the search
+	result, err := forge.SearchCode(ctx, query, &options)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to search code")
 	}
@@ -27,31 +41,387 @@ func GitHubSearchCode(
 		return "", errors.New("no results found")
 	}
 
-	response := fmt.Sprintf("Total: %d\n", result.Total)
+	pages := (min(result.Total, maxSearchResults) + options.PerPage - 1) / options.PerPage
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total: %d (page %d of %d)\n", result.Total, options.Page, pages)
+	if result.Rate.Limit > 0 {
+		fmt.Fprintf(&sb, "Rate limit: %d of %d remaining, resets at %s\n",
+			result.Rate.Remaining, result.Rate.Limit, result.Rate.Reset.UTC().Format(time.TimeOnly))
+	}
 	for _, item := range result.Items {
 		// Use compact format: repository/path
-		response += fmt.Sprintf("%s/%s\n", item.Repository, item.Path)
+		fmt.Fprintf(&sb, "%s/%s\n", item.Repository, item.Path)
 		for _, fragment := range item.Fragments {
-			response += fmt.Sprintf("  %s\n", fragment)
+			writeFragment(&sb, fragment)
 		}
 	}
+	if options.Page < pages {
+		fmt.Fprintf(&sb, "(more results: use page=%d)\n", options.Page+1)
+	}
 
-	return response, nil
+	return sb.String(), nil
 }
 
-// PrintGitHubTree prints a tree representation of a GitHub repository path
-// using the same formatting as PrintTree does for local directories.
-func PrintGitHubTree(
+// writeFragment writes the lines of a fragment, numbered if it was located.
+func writeFragment(sb *strings.Builder, fragment repository.CodeFragment) {
+	if fragment.Line == 0 {
+		fmt.Fprintf(sb, "  %s\n", fragment.Text)
+		return
+	}
+	for i, line := range strings.Split(strings.TrimSuffix(fragment.Text, "\n"), "\n") {
+		fmt.Fprintf(sb, "  %d: %s\n", fragment.Line+i, line)
+	}
+}
+
+// PrintForgeTree prints a tree representation of a repository path on a forge at commit
+// (see repository.Forge.ResolveRef) using the same formatting as PrintTree does
+// for local directories.
+func PrintForgeTree(
 	ctx context.Context,
 	b *strings.Builder,
-	client *infra.GitHubClient,
-	owner, repo, path string,
+	forge repository.Forge,
+	owner, repo, commit, path string,
 	ignoreDot bool,
 	maxDepth int,
 ) error {
-	// Create a GitHub-specific walker
-	walker := infra.NewGitHubDirWalker(ctx, client, owner, repo)
+	// Create a forge-specific walker
+	walker := infra.NewForgeDirWalker(forge, owner, repo, commit)
 
-	// Use the existing PrintTree function with our GitHub-specific walker
+	// Use the existing PrintTree function with our forge-specific walker
 	return PrintTree(ctx, b, walker, path, ignoreDot, maxDepth)
 }
+
+// DefaultCommentsPerPage is the number of issue comments read_github_issue shows per page.
+const DefaultCommentsPerPage = 30
+
+// GitHubSearchIssues searches issues and pull requests, one compact line per result.
+func GitHubSearchIssues(
+	ctx context.Context,
+	github repository.GitHubClient,
+	query string,
+	opt *repository.SearchIssuesOption,
+) (string, error) {
+	result, err := github.SearchIssues(ctx, query, opt)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to search issues")
+	}
+
+	if result.Total == 0 {
+		return "", errors.New("no results found")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total: %d\n", result.Total)
+	for _, item := range result.Items {
+		kind := ""
+		if item.IsPullRequest {
+			kind = "PR "
+		}
+		fmt.Fprintf(&sb, "%s#%d [%s] %s%s (@%s, %d comments, updated %s)\n",
+			item.Repository, item.Number, item.State, kind, item.Title,
+			item.Author, item.Comments, formatDate(item.UpdatedAt))
+	}
+	return sb.String(), nil
+}
+
+// GitHubReadIssue formats an issue with a page (1-based) of its comments.
+// The body is only included on the first page.
+func GitHubReadIssue(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo string,
+	number, commentPage, commentsPerPage int,
+) (string, error) {
+	if commentPage < 1 {
+		commentPage = 1
+	}
+	if commentsPerPage <= 0 {
+		commentsPerPage = DefaultCommentsPerPage
+	}
+
+	issue, err := github.GetIssue(ctx, owner, repo, number)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get issue")
+	}
+
+	var sb strings.Builder
+	writeIssueHeader(&sb, &issue)
+	if commentPage == 1 {
+		writeBody(&sb, issue.Body)
+	}
+
+	if issue.Comments == 0 {
+		sb.WriteString("\nNo comments\n")
+		return sb.String(), nil
+	}
+	comments, hasMore, err := github.ListIssueComments(
+		ctx, owner, repo, number, commentPage, commentsPerPage)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list comments")
+	}
+
+	fmt.Fprintf(&sb, "\nComments (page %d, %d total):\n", commentPage, issue.Comments)
+	for _, comment := range comments {
+		fmt.Fprintf(&sb, "--- @%s %s\n%s\n",
+			comment.Author, formatDate(comment.CreatedAt), strings.TrimSpace(comment.Body))
+	}
+	if hasMore {
+		fmt.Fprintf(&sb, "... (use comment_page=%d to see more)\n", commentPage+1)
+	}
+	return sb.String(), nil
+}
+
+// GitHubReadPullRequest formats a pull request with a page of its unified diff.
+// The description, changed files and review comments are included with the
+// first page of the diff only.
+func GitHubReadPullRequest(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo string,
+	number, diffOffset, diffLimit int,
+) (string, error) {
+	if diffLimit <= 0 {
+		diffLimit = DefaultLinesPerPage
+	}
+
+	pr, err := github.GetPullRequest(ctx, owner, repo, number)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get pull request")
+	}
+
+	var sb strings.Builder
+	writeIssueHeader(&sb, &pr.Issue)
+	draft := ""
+	if pr.Draft {
+		draft = ", draft"
+	}
+	fmt.Fprintf(&sb, "Branches: %s <- %s (+%d -%d in %d files%s)\n",
+		pr.Base, pr.Head, pr.Additions, pr.Deletions, pr.ChangedFiles, draft)
+
+	if diffOffset == 0 {
+		writeBody(&sb, pr.Body)
+
+		files, err := github.ListPullRequestFiles(ctx, owner, repo, number)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list changed files")
+		}
+		fmt.Fprintf(&sb, "\nChanged files (%d):\n", len(files))
+		for _, file := range files {
+			fmt.Fprintf(&sb, "  %s %s (+%d -%d)\n",
+				fileStatusLetter(file.Status), file.Filename, file.Additions, file.Deletions)
+		}
+
+		comments, err := github.ListReviewComments(ctx, owner, repo, number)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list review comments")
+		}
+		if len(comments) > 0 {
+			fmt.Fprintf(&sb, "\nReview comments (%d):\n", len(comments))
+			for _, comment := range comments {
+				fmt.Fprintf(&sb, "--- @%s %s:%d %s\n%s\n", comment.Author, comment.Path,
+					comment.Line, formatDate(comment.CreatedAt), strings.TrimSpace(comment.Body))
+			}
+		}
+	}
+
+	diff, err := github.GetPullRequestDiff(ctx, owner, repo, number)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get diff")
+	}
+	sb.WriteString("\n")
+	sb.WriteString(
+		FormatDiffPage(strings.TrimSuffix(diff, "\n"), "diff_offset", diffOffset, diffLimit),
+	)
+	return sb.String(), nil
+}
+
+// FormatDiffPage formats limit lines of diff starting at line offset, with a header
+// giving the line range. offsetParam names the tool parameter that selects the page
+// and is used in the hint for the next page.
+func FormatDiffPage(diff, offsetParam string, offset, limit int) string {
+	if diff == "" {
+		return "Diff: (no changes)\n"
+	}
+	content, totalLines, hasMore := pageLines(diff, offset, limit)
+	if offset >= totalLines {
+		return fmt.Sprintf("(%s %d exceeds diff length of %d lines)\n",
+			offsetParam, offset, totalLines)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Diff (lines %d-%d of %d):\n%s\n",
+		offset+1, min(offset+limit, totalLines), totalLines, content)
+	if hasMore {
+		fmt.Fprintf(&sb, "... (use %s=%d to see more)\n", offsetParam, offset+limit)
+	}
+	return sb.String()
+}
+
+func writeIssueHeader(sb *strings.Builder, issue *repository.Issue) {
+	fmt.Fprintf(sb, "%s#%d [%s] %s\n", issue.Repository, issue.Number, issue.State, issue.Title)
+	fmt.Fprintf(sb, "Author: @%s, created %s, updated %s\n",
+		issue.Author, formatDate(issue.CreatedAt), formatDate(issue.UpdatedAt))
+	if len(issue.Labels) > 0 {
+		fmt.Fprintf(sb, "Labels: %s\n", strings.Join(issue.Labels, ", "))
+	}
+	if issue.URL != "" {
+		fmt.Fprintf(sb, "URL: %s\n", issue.URL)
+	}
+}
+
+func writeBody(sb *strings.Builder, body string) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		body = "(no description)"
+	}
+	fmt.Fprintf(sb, "\n%s\n", body)
+}
+
+// fileStatusLetter abbreviates a file status like git diff --name-status.
+func fileStatusLetter(status string) string {
+	switch status {
+	case "added":
+		return "A"
+	case "removed":
+		return "D"
+	case "renamed":
+		return "R"
+	case "copied":
+		return "C"
+	default:
+		return "M"
+	}
+}
+
+func formatDate(t time.Time) string {
+	return t.Format(time.DateOnly)
+}
+
+// DefaultLogLimit is the number of commits github_log lists by default.
+const DefaultLogLimit = 30
+
+// GitHubLog lists the commits of a ref touching a path, one compact line per commit.
+func GitHubLog(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo string,
+	opt *repository.ListCommitsOption,
+) (string, error) {
+	options := *opt
+	if options.Limit <= 0 {
+		options.Limit = DefaultLogLimit
+	}
+	commits, err := github.ListCommits(ctx, owner, repo, &options)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list commits")
+	}
+	if len(commits) == 0 {
+		return "", errors.New("no commits found")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Commits (%d):\n", len(commits))
+	for _, commit := range commits {
+		writeCommitLine(&sb, "", commit)
+	}
+	return sb.String(), nil
+}
+
+// GitHubBlame maps the lines of a file to the commits that last changed them.
+// Only ranges overlapping startLine-endLine are shown (0 for no bound).
+func GitHubBlame(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo, ref, path string,
+	startLine, endLine int,
+) (string, error) {
+	blame, err := github.Blame(ctx, owner, repo, ref, path)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to blame")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Blame of %s at %s:\n", path, blame.Commit)
+	count := 0
+	for _, r := range blame.Ranges {
+		if (startLine > 0 && r.EndLine < startLine) || (endLine > 0 && r.StartLine > endLine) {
+			continue
+		}
+		writeCommitLine(&sb, fmt.Sprintf("L%d-%d ", r.StartLine, r.EndLine), r.Commit)
+		count++
+	}
+	if count == 0 {
+		return "", errors.New("no lines in range")
+	}
+	return sb.String(), nil
+}
+
+// GitHubCompare compares two refs. It returns a summary of the commits and
+// changed files, and the unified diff. If path is set, only files under it are included.
+func GitHubCompare(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo, base, head, path string,
+) (string, string, error) {
+	comparison, err := github.Compare(ctx, owner, repo, base, head)
+	if err != nil {
+		return "", "", errors.Wrap(err, "failed to compare")
+	}
+
+	path = strings.Trim(path, "/")
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Compare %s...%s: %s (ahead %d, behind %d)\n",
+		base, head, comparison.Status, comparison.AheadBy, comparison.BehindBy)
+	fmt.Fprintf(&sb, "Commits (%d):\n", len(comparison.Commits))
+	for _, commit := range comparison.Commits {
+		writeCommitLine(&sb, "  ", commit)
+	}
+
+	var files []repository.PullRequestFile
+	for _, file := range comparison.Files {
+		if isUnderPath(file.Filename, path) {
+			files = append(files, file)
+		}
+	}
+	fmt.Fprintf(&sb, "Changed files (%d):\n", len(files))
+	for _, file := range files {
+		fmt.Fprintf(&sb, "  %s %s (+%d -%d)\n",
+			fileStatusLetter(file.Status), file.Filename, file.Additions, file.Deletions)
+	}
+
+	return sb.String(), filterDiff(strings.TrimSuffix(comparison.Diff, "\n"), path), nil
+}
+
+func writeCommitLine(sb *strings.Builder, prefix string, commit repository.CommitSummary) {
+	sha := commit.SHA
+	if len(sha) > 12 {
+		sha = sha[:12]
+	}
+	fmt.Fprintf(sb, "%s%s %s @%s %s\n",
+		prefix, sha, formatDate(commit.Date), commit.Author, commit.Message)
+}
+
+// filterDiff keeps the files of a unified diff that are under path.
+func filterDiff(diff, path string) string {
+	if path == "" {
+		return diff
+	}
+
+	var kept []string
+	keep := false
+	for _, line := range strings.Split(diff, "\n") {
+		if header, ok := strings.CutPrefix(line, "diff --git "); ok {
+			// "a/<old> b/<new>"
+			oldName, newName, _ := strings.Cut(header, " b/")
+			keep = isUnderPath(strings.TrimPrefix(oldName, "a/"), path) ||
+				isUnderPath(newName, path)
+		}
+		if keep {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+func isUnderPath(name, path string) bool {
+	return path == "" || name == path || strings.HasPrefix(name, path+"/")
+}
@@ -0,0 +1,277 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+// maxReleases is the number of releases and tags GitHubReleases reads.
+const maxReleases = 100
+
+// changelogFiles are the files searched for release notes of repositories
+// without GitHub releases, in order.
+var changelogFiles = []string{
+	"CHANGELOG.md",
+	"CHANGELOG",
+	"CHANGES.md",
+	"HISTORY.md",
+	"NEWS.md",
+	"docs/CHANGELOG.md",
+}
+
+// GitHubReleases lists the releases and tags of a repository if from and to
+// are empty. Otherwise it returns the release notes of the releases after from
+// up to and including to (the latest release if empty). If the repository has
+// no releases or none of those selected has notes, it returns the matching
+// section of the changelog, saying so. The result is paged by lines.
+func GitHubReleases(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo, from, to string,
+	offset, limit int,
+) (string, error) {
+	if limit <= 0 {
+		limit = DefaultLinesPerPage
+	}
+
+	releases, err := github.ListReleases(ctx, owner, repo, maxReleases)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list releases")
+	}
+
+	var text string
+	if from == "" && to == "" {
+		tags, err := github.ListTags(ctx, owner, repo, maxReleases)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to list tags")
+		}
+		text = formatReleaseList(owner+"/"+repo, releases, tags)
+	} else {
+		selected, err := selectReleases(releases, from, to)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case slices.ContainsFunc(selected, func(r repository.Release) bool {
+			return strings.TrimSpace(r.Body) != ""
+		}):
+			text = formatReleaseNotes(owner+"/"+repo, selected, from, to)
+		case len(selected) == 0 && len(releases) > 0:
+			text = fmt.Sprintf("No releases of %s/%s %s\n", owner, repo, describeRange(from, to))
+		default:
+			// Repositories without release notes on GitHub may keep a changelog
+			reason := fmt.Sprintf("%s/%s has no releases on GitHub", owner, repo)
+			if len(selected) > 0 {
+				reason = fmt.Sprintf("None of the releases of %s/%s %s has release notes on GitHub",
+					owner, repo, describeRange(from, to))
+			}
+			text, err = readChangelog(ctx, github, owner, repo, from, to, reason)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+
+	content, totalLines, hasMore := pageLines(strings.TrimSuffix(text, "\n"), offset, limit)
+	if offset >= totalLines {
+		return fmt.Sprintf("(offset %d exceeds length of %d lines)\n", offset, totalLines), nil
+	}
+	var sb strings.Builder
+	sb.WriteString(content)
+	sb.WriteString("\n")
+	if hasMore {
+		fmt.Fprintf(&sb, "... (showing lines %d-%d of %d, use offset=%d to see more)\n",
+			offset+1, offset+limit, totalLines, offset+limit)
+	}
+	return sb.String(), nil
+}
+
+func formatReleaseList(fullName string, releases []repository.Release, tags []string) string {
+	var sb strings.Builder
+	if len(releases) == 0 && len(tags) == 0 {
+		fmt.Fprintf(&sb, "%s has no releases or tags\n", fullName)
+		return sb.String()
+	}
+
+	released := make(map[string]bool, len(releases))
+	fmt.Fprintf(&sb, "Releases of %s (%d):\n", fullName, len(releases))
+	for _, release := range releases {
+		released[release.Tag] = true
+		fmt.Fprintf(&sb, "  %s %s", release.Tag, formatDate(release.PublishedAt))
+		if release.Name != "" && release.Name != release.Tag {
+			fmt.Fprintf(&sb, " %q", release.Name)
+		}
+		if release.Prerelease {
+			sb.WriteString(" (prerelease)")
+		}
+		sb.WriteString("\n")
+	}
+
+	var unreleased []string
+	for _, tag := range tags {
+		if !released[tag] {
+			unreleased = append(unreleased, tag)
+		}
+	}
+	if len(unreleased) > 0 {
+		fmt.Fprintf(&sb, "Tags without a release (%d): %s\n",
+			len(unreleased), strings.Join(unreleased, ", "))
+	}
+	return sb.String()
+}
+
+func formatReleaseNotes(
+	fullName string,
+	releases []repository.Release,
+	from, to string,
+) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Release notes of %s %s (%d releases):\n",
+		fullName, describeRange(from, to), len(releases))
+	for _, release := range releases {
+		fmt.Fprintf(&sb, "\n## %s", release.Tag)
+		if release.Name != "" && release.Name != release.Tag {
+			fmt.Fprintf(&sb, " %q", release.Name)
+		}
+		fmt.Fprintf(&sb, " (%s)\n", formatDate(release.PublishedAt))
+		if body := strings.TrimSpace(release.Body); body != "" {
+			sb.WriteString(strings.ReplaceAll(body, "\r\n", "\n"))
+			sb.WriteString("\n")
+		} else {
+			sb.WriteString("(no release notes)\n")
+		}
+	}
+	return sb.String()
+}
+
+func describeRange(from, to string) string {
+	switch {
+	case from == "":
+		return "up to " + to
+	case to == "":
+		return "after " + from
+	default:
+		return fmt.Sprintf("after %s up to %s", from, to)
+	}
+}
+
+// selectReleases returns the releases after from up to and including to.
+// Semantic versions are compared if from, to and the tags have them; otherwise
+// the releases are taken by their position in the list, newest first.
+func selectReleases(releases []repository.Release, from, to string) ([]repository.Release, error) {
+	fromVersion, toVersion := releaseVersion(from), releaseVersion(to)
+	if (from == "" || fromVersion != "") && (to == "" || toVersion != "") {
+		var selected []repository.Release
+		for _, release := range releases {
+			v := releaseVersion(release.Tag)
+			if v == "" || (from != "" && semver.Compare(v, fromVersion) <= 0) ||
+				(to != "" && semver.Compare(v, toVersion) > 0) {
+				continue
+			}
+			selected = append(selected, release)
+		}
+		slices.SortStableFunc(selected, func(a, b repository.Release) int {
+			return semver.Compare(releaseVersion(b.Tag), releaseVersion(a.Tag))
+		})
+		return selected, nil
+	}
+
+	start, end := 0, len(releases)
+	if to != "" {
+		start = slices.IndexFunc(releases, func(r repository.Release) bool { return r.Tag == to })
+		if start < 0 {
+			return nil, errors.Errorf("release %s not found", to)
+		}
+	}
+	if from != "" {
+		if i := slices.IndexFunc(releases, func(r repository.Release) bool {
+			return r.Tag == from
+		}); i >= start {
+			end = i
+		}
+	}
+	return releases[start:end], nil
+}
+
+// releaseVersion returns the semantic version of a tag such as "v1.2.3", "1.2.3"
+// or "cli/v1.2.3", or "" if it has none.
+func releaseVersion(tag string) string {
+	v := tag[strings.LastIndex(tag, "/")+1:]
+	if !strings.HasPrefix(v, "v") {
+		v = "v" + v
+	}
+	if !semver.IsValid(v) {
+		return ""
+	}
+	return v
+}
+
+// readChangelog returns the section of the first changelog file found
+// covering the versions after from up to and including to, introduced by
+// reason, the reason for reading the changelog.
+func readChangelog(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo, from, to, reason string,
+) (string, error) {
+	var missing []string
+	for _, path := range changelogFiles {
+		content, err := github.GetContent(ctx, owner, repo, path, "")
+		if err != nil {
+			continue
+		}
+		section, found := changelogSection(content, from, to)
+		if !found {
+			missing = append(missing, path)
+			continue
+		}
+		return fmt.Sprintf("%s, showing the changelog section from %s:\n\n%s",
+			reason, path, section), nil
+	}
+	if len(missing) > 0 {
+		return "", errors.Errorf("%s and %s has no section %s",
+			reason, strings.Join(missing, ", "), describeRange(from, to))
+	}
+	return "", errors.Errorf("%s and no changelog was found", reason)
+}
+
+// changelogSection returns the lines of a Markdown changelog from the heading
+// of version to (the start if empty) up to the heading of version from (the end
+// if empty). It returns false if the heading of to or from is not found.
+func changelogSection(content, from, to string) (string, bool) {
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	start := 0
+	if to != "" {
+		start = slices.IndexFunc(lines, versionHeading(to))
+		if start < 0 {
+			return "", false
+		}
+	}
+	end := len(lines)
+	if from != "" {
+		i := slices.IndexFunc(lines[start+1:], versionHeading(from))
+		if i < 0 {
+			return "", false
+		}
+		end = start + 1 + i
+	}
+	return strings.TrimRight(strings.Join(lines[start:end], "\n"), "\n") + "\n", true
+}
+
+// versionHeading returns a function reporting whether a line is a Markdown
+// heading naming version, with or without a "v" prefix, e.g. "## [1.2.0] - 2025-01-02".
+func versionHeading(version string) func(string) bool {
+	v := regexp.QuoteMeta(strings.TrimPrefix(version, "v"))
+	re := regexp.MustCompile(`(^|[^0-9A-Za-z.\-])v?` + v + `($|[^0-9A-Za-z.\-])`)
+	return func(line string) bool {
+		return strings.HasPrefix(line, "#") && re.MatchString(line)
+	}
+}
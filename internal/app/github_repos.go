@@ -0,0 +1,171 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+)
+
+// DefaultSearchReposPerPage is the number of repository search results per page.
+const DefaultSearchReposPerPage = 20
+
+// Repository sort orders of GitHubSearchRepos. An empty order sorts by best match.
+var RepoSortOrders = []string{"stars", "forks", "updated"}
+
+// GitHubSearchRepos searches repositories, one compact line per result.
+// Results are cached like documentation.
+func GitHubSearchRepos(
+	ctx context.Context,
+	github repository.GitHubClient,
+	query string,
+	opt *repository.SearchReposOption,
+) (string, error) {
+	options := *opt
+	if options.Page <= 0 {
+		options.Page = 1
+	}
+	if options.PerPage <= 0 {
+		options.PerPage = DefaultSearchReposPerPage
+	}
+	var language string
+	if options.Language != nil {
+		language = *options.Language
+	}
+	cacheKey := fmt.Sprintf("github:repos:%s:%q:%s:%d:%s:%d:%d", github.Host(), query,
+		language, options.MinStars, options.Sort, options.Page, options.PerPage)
+
+	var result repository.SearchReposResult
+	if cached, found := docCache.Get(cacheKey); found {
+		result = cached.(repository.SearchReposResult)
+	} else {
+		var err error
+		result, err = github.SearchRepositories(ctx, query, &options)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to search repositories")
+		}
+		docCache.Set(cacheKey, result, cache.DefaultExpiration)
+	}
+
+	if result.Total == 0 {
+		return "No repositories found\n", nil
+	}
+
+	pages := (min(result.Total, maxSearchResults) + options.PerPage - 1) / options.PerPage
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Total: %d (page %d of %d)\n", result.Total, options.Page, pages)
+	for _, repo := range result.Items {
+		fmt.Fprintf(&sb, "%s (%d stars", repo.FullName, repo.Stars)
+		if repo.Language != "" {
+			fmt.Fprintf(&sb, ", %s", repo.Language)
+		}
+		fmt.Fprintf(&sb, ", pushed %s", formatDate(repo.PushedAt))
+		if repo.Archived {
+			sb.WriteString(", archived")
+		}
+		sb.WriteString(")")
+		if repo.Description != "" {
+			fmt.Fprintf(&sb, " %s", repo.Description)
+		}
+		sb.WriteString("\n")
+	}
+	if options.Page < pages {
+		fmt.Fprintf(&sb, "(more results: use page=%d)\n", options.Page+1)
+	}
+	return sb.String(), nil
+}
+
+// GitHubRepoInfo describes a repository: its description, popularity, license,
+// topics, latest release, activity and languages. Results are cached like documentation.
+func GitHubRepoInfo(
+	ctx context.Context,
+	github repository.GitHubClient,
+	owner, repo string,
+) (string, error) {
+	cacheKey := fmt.Sprintf("github:repo:%s/%s/%s", github.Host(), owner, repo)
+
+	var info repository.RepositoryInfo
+	if cached, found := docCache.Get(cacheKey); found {
+		info = cached.(repository.RepositoryInfo)
+	} else {
+		var err error
+		info, err = github.GetRepositoryInfo(ctx, owner, repo)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to get repository")
+		}
+		docCache.Set(cacheKey, info, cache.DefaultExpiration)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(info.FullName)
+	if info.Description != "" {
+		fmt.Fprintf(&sb, ": %s", info.Description)
+	}
+	sb.WriteString("\n")
+	if info.Homepage != "" {
+		fmt.Fprintf(&sb, "Homepage: %s\n", info.Homepage)
+	}
+	fmt.Fprintf(&sb, "Default branch: %s\n", info.DefaultBranch)
+	fmt.Fprintf(&sb, "Stars: %d, forks: %d, open issues: %d\n",
+		info.Stars, info.Forks, info.OpenIssues)
+	if info.License != "" {
+		fmt.Fprintf(&sb, "License: %s\n", info.License)
+	} else {
+		sb.WriteString("License: none\n")
+	}
+	if len(info.Topics) > 0 {
+		fmt.Fprintf(&sb, "Topics: %s\n", strings.Join(info.Topics, ", "))
+	}
+	if len(info.Languages) > 0 {
+		sb.WriteString("Languages: ")
+		sb.WriteString(formatLanguages(info.Languages))
+		sb.WriteString("\n")
+	}
+	if release := info.LatestRelease; release != nil {
+		fmt.Fprintf(&sb, "Latest release: %s", release.Tag)
+		if release.Name != "" && release.Name != release.Tag {
+			fmt.Fprintf(&sb, " %q", release.Name)
+		}
+		fmt.Fprintf(&sb, " (%s)\n", formatDate(release.PublishedAt))
+	} else {
+		sb.WriteString("Latest release: none\n")
+	}
+	fmt.Fprintf(&sb, "Last push: %s\n", formatDate(info.PushedAt))
+	fmt.Fprintf(&sb, "Archived: %t\n", info.Archived)
+	return sb.String(), nil
+}
+
+// maxLanguages is the number of primary languages shown, the rest are summed up.
+const maxLanguages = 5
+
+// formatLanguages lists languages, largest first, with their share of the code.
+func formatLanguages(languages []repository.LanguageBytes) string {
+	total := 0
+	for _, l := range languages {
+		total += l.Bytes
+	}
+	if total == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, maxLanguages+1)
+	other := 0
+	for i, l := range languages {
+		if i >= maxLanguages {
+			other += l.Bytes
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%s %.1f%%", l.Language, percent(l.Bytes, total)))
+	}
+	if other > 0 {
+		parts = append(parts, fmt.Sprintf("other %.1f%%", percent(other, total)))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func percent(n, total int) float64 {
+	return float64(n) * 100 / float64(total)
+}
@@ -4,14 +4,40 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
 )
 
 // MockGitHubClient implements the GitHubClient interface for testing
 type MockGitHubClient struct {
 	searchResult repository.SearchCodeResult
 	searchError  error
+
+	issuesResult   repository.SearchIssuesResult
+	issue          repository.Issue
+	comments       []repository.IssueComment
+	pullRequest    repository.PullRequest
+	files          []repository.PullRequestFile
+	reviewComments []repository.ReviewComment
+	diff           string
+
+	commits    []repository.CommitSummary
+	blame      repository.BlameResult
+	comparison repository.Comparison
+
+	reposResult repository.SearchReposResult
+	repoInfo    repository.RepositoryInfo
+	calls       int // Of SearchRepositories and GetRepositoryInfo
+
+	releases []repository.Release
+	tags     []string
+	contents map[string]string // By path
+}
+
+func (m *MockGitHubClient) Host() string {
+	return "github.com"
 }
 
 func (m *MockGitHubClient) SearchCode(
@@ -24,9 +50,93 @@ func (m *MockGitHubClient) SearchCode(
 
 func (m *MockGitHubClient) GetContent(
 	ctx context.Context,
-	owner, repo, path string,
+	owner, repo, path, ref string,
 ) (string, error) {
-	return "", nil // Not used in this test
+	content, ok := m.contents[path]
+	if !ok {
+		return "", errors.New("not found")
+	}
+	return content, nil
+}
+
+func (m *MockGitHubClient) ResolveRef(
+	ctx context.Context,
+	owner, repo, ref string,
+) (string, error) {
+	return "c1", nil
+}
+
+func (m *MockGitHubClient) ListTree(
+	ctx context.Context,
+	owner, repo, commit string,
+) ([]repository.TreeEntry, bool, error) {
+	return nil, false, errors.New("not implemented")
+}
+
+func (m *MockGitHubClient) ListDir(
+	ctx context.Context,
+	owner, repo, commit, path string,
+) ([]repository.TreeEntry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (m *MockGitHubClient) SearchIssues(
+	ctx context.Context,
+	query string,
+	opt *repository.SearchIssuesOption,
+) (repository.SearchIssuesResult, error) {
+	return m.issuesResult, nil
+}
+
+func (m *MockGitHubClient) GetIssue(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) (repository.Issue, error) {
+	return m.issue, nil
+}
+
+// ListIssueComments pages the mock comments like the GitHub API.
+func (m *MockGitHubClient) ListIssueComments(
+	ctx context.Context,
+	owner, repo string,
+	number, page, perPage int,
+) ([]repository.IssueComment, bool, error) {
+	start := min((page-1)*perPage, len(m.comments))
+	end := min(start+perPage, len(m.comments))
+	return m.comments[start:end], end < len(m.comments), nil
+}
+
+func (m *MockGitHubClient) GetPullRequest(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) (repository.PullRequest, error) {
+	return m.pullRequest, nil
+}
+
+func (m *MockGitHubClient) ListPullRequestFiles(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) ([]repository.PullRequestFile, error) {
+	return m.files, nil
+}
+
+func (m *MockGitHubClient) ListReviewComments(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) ([]repository.ReviewComment, error) {
+	return m.reviewComments, nil
+}
+
+func (m *MockGitHubClient) GetPullRequestDiff(
+	ctx context.Context,
+	owner, repo string,
+	number int,
+) (string, error) {
+	return m.diff, nil
 }
 
 func TestGitHubSearchCodeCompactFormat(t *testing.T) {
@@ -39,20 +149,24 @@ func TestGitHubSearchCodeCompactFormat(t *testing.T) {
 					Name:       "main.go",
 					Path:       "cmd/main.go",
 					Repository: "owner/repo",
-					Fragments:  []string{"func main() {", "    fmt.Println(\"test\")"},
+					Fragments: []repository.CodeFragment{
+						{Text: "func main() {"},
+						{Text: "    fmt.Println(\"test\")"},
+					},
 				},
 				{
 					Name:       "utils.go",
 					Path:       "pkg/utils.go",
 					Repository: "owner/repo",
-					Fragments:  []string{"func testFunc() {"},
+					Fragments:  []repository.CodeFragment{{Text: "func testFunc() {"}},
 				},
 			},
 		},
 	}
 
 	// Call the function
-	result, err := GitHubSearchCode(context.Background(), mockClient, "test", nil, nil)
+	result, err := ForgeSearchCode(
+		context.Background(), mockClient, "test", &repository.SearchCodeOption{})
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -101,3 +215,502 @@ func TestGitHubSearchCodeCompactFormat(t *testing.T) {
 		t.Error("Expected fragments to be indented with spaces")
 	}
 }
+
+func (m *MockGitHubClient) ListCommits(
+	ctx context.Context,
+	owner, repo string,
+	opt *repository.ListCommitsOption,
+) ([]repository.CommitSummary, error) {
+	return m.commits[:min(opt.Limit, len(m.commits))], nil
+}
+
+func (m *MockGitHubClient) Blame(
+	ctx context.Context,
+	owner, repo, ref, path string,
+) (repository.BlameResult, error) {
+	return m.blame, nil
+}
+
+func (m *MockGitHubClient) Compare(
+	ctx context.Context,
+	owner, repo, base, head string,
+) (repository.Comparison, error) {
+	return m.comparison, nil
+}
+
+func TestGitHubSearchCode_LinesAndPaging(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		searchResult: repository.SearchCodeResult{
+			Total: 25,
+			Items: []repository.SearchCodeItem{{
+				Path:       "main.go",
+				Repository: "owner/repo",
+				Fragments: []repository.CodeFragment{
+					{Text: "func main() {\n\trun()\n", Line: 12},
+				},
+			}},
+			Rate: repository.RateLimit{
+				Limit: 10, Remaining: 9,
+				Reset: time.Date(2025, 1, 1, 12, 30, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	result, err := ForgeSearchCode(context.Background(), mockClient, "run",
+		&repository.SearchCodeOption{Page: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "Total: 25 (page 2 of 3)\n" +
+		"Rate limit: 9 of 10 remaining, resets at 12:30:00\n" +
+		"owner/repo/main.go\n" +
+		"  12: func main() {\n" +
+		"  13: \trun()\n" +
+		"(more results: use page=3)\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func (m *MockGitHubClient) SearchRepositories(
+	ctx context.Context,
+	query string,
+	opt *repository.SearchReposOption,
+) (repository.SearchReposResult, error) {
+	m.calls++
+	return m.reposResult, nil
+}
+
+func (m *MockGitHubClient) GetRepositoryInfo(
+	ctx context.Context,
+	owner, repo string,
+) (repository.RepositoryInfo, error) {
+	m.calls++
+	return m.repoInfo, nil
+}
+
+func (m *MockGitHubClient) ListReleases(
+	ctx context.Context,
+	owner, repo string,
+	limit int,
+) ([]repository.Release, error) {
+	return m.releases, nil
+}
+
+func (m *MockGitHubClient) ListTags(
+	ctx context.Context,
+	owner, repo string,
+	limit int,
+) ([]string, error) {
+	return m.tags, nil
+}
+
+func TestGitHubSearchIssues(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		issuesResult: repository.SearchIssuesResult{
+			Total: 2,
+			Items: []repository.IssueSummary{
+				{
+					Repository: "owner/repo", Number: 12, Title: "Crash on start",
+					State: "open", Author: "alice", Comments: 3,
+					UpdatedAt: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+				},
+				{
+					Repository: "owner/repo", Number: 13, Title: "Fix crash",
+					State: "closed", IsPullRequest: true, Author: "bob",
+					UpdatedAt: time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	result, err := GitHubSearchIssues(context.Background(), mockClient, "crash", nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := "Total: 2\n" +
+		"owner/repo#12 [open] Crash on start (@alice, 3 comments, updated 2025-01-02)\n" +
+		"owner/repo#13 [closed] PR Fix crash (@bob, 0 comments, updated 2025-01-03)\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestGitHubReadIssue_CommentPages(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		issue: repository.Issue{
+			IssueSummary: repository.IssueSummary{
+				Repository: "owner/repo", Number: 12, Title: "Crash on start",
+				State: "open", Author: "alice", Comments: 3,
+			},
+			Body:   "It crashes.",
+			Labels: []string{"bug"},
+		},
+		comments: []repository.IssueComment{
+			{Author: "bob", Body: "first"},
+			{Author: "carol", Body: "second"},
+			{Author: "alice", Body: "third"},
+		},
+	}
+
+	first, err := GitHubReadIssue(context.Background(), mockClient, "owner", "repo", 12, 1, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"owner/repo#12 [open] Crash on start\n",
+		"Labels: bug\n",
+		"\nIt crashes.\n",
+		"Comments (page 1, 3 total):\n--- @bob 0001-01-01\nfirst\n--- @carol",
+		"... (use comment_page=2 to see more)",
+	} {
+		if !strings.Contains(first, want) {
+			t.Errorf("Expected %q in:\n%s", want, first)
+		}
+	}
+
+	second, err := GitHubReadIssue(context.Background(), mockClient, "owner", "repo", 12, 2, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(second, "It crashes.") || strings.Contains(second, "@bob") {
+		t.Errorf("Expected only the comments of page 2, got:\n%s", second)
+	}
+	if !strings.Contains(second, "--- @alice 0001-01-01\nthird\n") ||
+		strings.Contains(second, "use comment_page") {
+		t.Errorf("Expected the last comment without more pages, got:\n%s", second)
+	}
+}
+
+func TestGitHubReadPullRequest_DiffPaging(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		pullRequest: repository.PullRequest{
+			Issue: repository.Issue{
+				IssueSummary: repository.IssueSummary{
+					Repository: "owner/repo", Number: 13, Title: "Fix crash",
+					State: "open", IsPullRequest: true, Author: "bob",
+				},
+				Body: "Fixes #12",
+			},
+			Base: "main", Head: "bob:fix", Additions: 1, Deletions: 1, ChangedFiles: 1,
+		},
+		files: []repository.PullRequestFile{
+			{Filename: "main.go", Status: "modified", Additions: 1, Deletions: 1},
+		},
+		reviewComments: []repository.ReviewComment{
+			{Author: "alice", Path: "main.go", Line: 2, Body: "Nice"},
+		},
+		diff: "diff --git a/main.go b/main.go\n--- a/main.go\n+++ b/main.go\n" +
+			"@@ -1,2 +1,2 @@\n package main\n-bad\n+good\n",
+	}
+
+	first, err := GitHubReadPullRequest(
+		context.Background(), mockClient, "owner", "repo", 13, 0, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, want := range []string{
+		"Branches: main <- bob:fix (+1 -1 in 1 files)\n",
+		"\nFixes #12\n",
+		"Changed files (1):\n  M main.go (+1 -1)\n",
+		"Review comments (1):\n--- @alice main.go:2 0001-01-01\nNice\n",
+		"Diff (lines 1-4 of 7):\ndiff --git a/main.go b/main.go\n",
+		"... (use diff_offset=4 to see more)",
+	} {
+		if !strings.Contains(first, want) {
+			t.Errorf("Expected %q in:\n%s", want, first)
+		}
+	}
+
+	second, err := GitHubReadPullRequest(
+		context.Background(), mockClient, "owner", "repo", 13, 4, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(second, "Changed files") || strings.Contains(second, "Fixes #12") {
+		t.Errorf("Expected only the diff on later pages, got:\n%s", second)
+	}
+	if !strings.HasSuffix(second, "Diff (lines 5-7 of 7):\n package main\n-bad\n+good\n") {
+		t.Errorf("Expected the rest of the diff, got:\n%s", second)
+	}
+
+	past, err := GitHubReadPullRequest(
+		context.Background(), mockClient, "owner", "repo", 13, 10, 4)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.HasSuffix(past, "(diff_offset 10 exceeds diff length of 7 lines)\n") {
+		t.Errorf("Expected an out-of-range note naming diff_offset, got:\n%s", past)
+	}
+}
+
+func TestGitHubLogAndBlame(t *testing.T) {
+	first := repository.CommitSummary{
+		SHA: "1111111111111111111111111111111111111111", Author: "alice",
+		Date: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), Message: "Add parser",
+	}
+	second := repository.CommitSummary{
+		SHA: "2222222222222222222222222222222222222222", Author: "bob",
+		Date: time.Date(2025, 2, 3, 0, 0, 0, 0, time.UTC), Message: "Fix parser",
+	}
+	mockClient := &MockGitHubClient{
+		commits: []repository.CommitSummary{second, first},
+		blame: repository.BlameResult{
+			Commit: second.SHA,
+			Ranges: []repository.BlameRange{
+				{StartLine: 1, EndLine: 10, Commit: first},
+				{StartLine: 11, EndLine: 12, Commit: second},
+				{StartLine: 13, EndLine: 40, Commit: first},
+			},
+		},
+	}
+
+	log, err := GitHubLog(context.Background(), mockClient, "owner", "repo",
+		&repository.ListCommitsOption{Path: "parser.go", Limit: 1})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if log != "Commits (1):\n222222222222 2025-02-03 @bob Fix parser\n" {
+		t.Errorf("Unexpected log:\n%s", log)
+	}
+
+	blame, err := GitHubBlame(
+		context.Background(), mockClient, "owner", "repo", "", "parser.go", 11, 12)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "Blame of parser.go at " + second.SHA + ":\n" +
+		"L11-12 222222222222 2025-02-03 @bob Fix parser\n"
+	if blame != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, blame)
+	}
+
+	blame, err = GitHubBlame(
+		context.Background(), mockClient, "owner", "repo", "", "parser.go", 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Count(blame, "\nL") != 3 {
+		t.Errorf("Expected all ranges without bounds, got:\n%s", blame)
+	}
+}
+
+func TestGitHubCompare_PathFilter(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		comparison: repository.Comparison{
+			Status: "ahead", AheadBy: 1,
+			Commits: []repository.CommitSummary{
+				{SHA: "3333333333333333", Author: "carol", Message: "Update docs and code"},
+			},
+			Files: []repository.PullRequestFile{
+				{Filename: "docs/README.md", Status: "modified", Additions: 1, Deletions: 1},
+				{Filename: "main.go", Status: "added", Additions: 1},
+			},
+			Diff: "diff --git a/docs/README.md b/docs/README.md\n" +
+				"--- a/docs/README.md\n+++ b/docs/README.md\n@@ -1 +1 @@\n-old\n+new\n" +
+				"diff --git a/main.go b/main.go\n" +
+				"--- /dev/null\n+++ b/main.go\n@@ -0,0 +1 @@\n+package main\n",
+		},
+	}
+
+	summary, diff, err := GitHubCompare(
+		context.Background(), mockClient, "owner", "repo", "v1.0", "main", "docs")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "Compare v1.0...main: ahead (ahead 1, behind 0)\n" +
+		"Commits (1):\n  333333333333 0001-01-01 @carol Update docs and code\n" +
+		"Changed files (1):\n  M docs/README.md (+1 -1)\n"
+	if summary != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, summary)
+	}
+	if strings.Contains(diff, "main.go") || !strings.HasSuffix(diff, "-old\n+new") {
+		t.Errorf("Expected only the diff of docs/, got:\n%s", diff)
+	}
+}
+
+func TestGitHubSearchRepos(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		reposResult: repository.SearchReposResult{
+			Total: 45,
+			Items: []repository.RepositorySummary{
+				{
+					FullName: "spf13/cobra", Description: "A Commander for modern Go CLI",
+					Language: "Go", Stars: 40000,
+					PushedAt: time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC),
+				},
+				{
+					FullName: "urfave/cli", Language: "Go", Stars: 20000, Archived: true,
+					PushedAt: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+			},
+		},
+	}
+
+	language := "go"
+	opt := &repository.SearchReposOption{Language: &language, MinStars: 1000, Sort: "stars"}
+	result, err := GitHubSearchRepos(context.Background(), mockClient, "cli", opt)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "Total: 45 (page 1 of 3)\n" +
+		"spf13/cobra (40000 stars, Go, pushed 2025-05-01) A Commander for modern Go CLI\n" +
+		"urfave/cli (20000 stars, Go, pushed 2024-01-02, archived)\n" +
+		"(more results: use page=2)\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	if _, err := GitHubSearchRepos(context.Background(), mockClient, "cli", opt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if mockClient.calls != 1 {
+		t.Errorf("Expected the second search to be cached, got %d calls", mockClient.calls)
+	}
+}
+
+func TestGitHubRepoInfo(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		repoInfo: repository.RepositoryInfo{
+			FullName:      "owner/info-test",
+			Description:   "A test repository",
+			DefaultBranch: "main",
+			Stars:         10, Forks: 2, OpenIssues: 3,
+			License: "MIT",
+			Topics:  []string{"go", "mcp"},
+			LatestRelease: &repository.Release{
+				Tag: "v1.2.0", Name: "Spring release",
+				PublishedAt: time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC),
+			},
+			PushedAt: time.Date(2025, 4, 2, 0, 0, 0, 0, time.UTC),
+			Languages: []repository.LanguageBytes{
+				{Language: "Go", Bytes: 900},
+				{Language: "Shell", Bytes: 100},
+			},
+		},
+	}
+
+	result, err := GitHubRepoInfo(context.Background(), mockClient, "owner", "info-test")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "owner/info-test: A test repository\n" +
+		"Default branch: main\n" +
+		"Stars: 10, forks: 2, open issues: 3\n" +
+		"License: MIT\n" +
+		"Topics: go, mcp\n" +
+		"Languages: Go 90.0%, Shell 10.0%\n" +
+		"Latest release: v1.2.0 \"Spring release\" (2025-04-01)\n" +
+		"Last push: 2025-04-02\n" +
+		"Archived: false\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+}
+
+func TestGitHubReleases(t *testing.T) {
+	date := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockClient := &MockGitHubClient{
+		releases: []repository.Release{
+			{Tag: "v1.3.0-rc1", PublishedAt: date, Prerelease: true, Body: "Try it"},
+			{Tag: "v1.2.0", Name: "Faster", PublishedAt: date, Body: "* Speed up\r\n"},
+			{Tag: "v1.1.0", PublishedAt: date},
+			{Tag: "v1.0.0", PublishedAt: date, Body: "First"},
+		},
+		tags: []string{"v1.3.0-rc1", "v1.2.0", "v1.1.0", "v1.0.1", "v1.0.0"},
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		offset   int
+		limit    int
+		expected string
+	}{
+		{
+			name: "list",
+			expected: "Releases of owner/repo (4):\n" +
+				"  v1.3.0-rc1 2025-03-01 (prerelease)\n" +
+				"  v1.2.0 2025-03-01 \"Faster\"\n" +
+				"  v1.1.0 2025-03-01\n" +
+				"  v1.0.0 2025-03-01\n" +
+				"Tags without a release (1): v1.0.1\n",
+		},
+		{
+			name: "range",
+			from: "1.0.0",
+			to:   "v1.2.0",
+			expected: "Release notes of owner/repo after 1.0.0 up to v1.2.0 (2 releases):\n" +
+				"\n## v1.2.0 \"Faster\" (2025-03-01)\n* Speed up\n" +
+				"\n## v1.1.0 (2025-03-01)\n(no release notes)\n",
+		},
+		{
+			name:   "paged",
+			from:   "v1.0.0",
+			to:     "v1.2.0",
+			offset: 1,
+			limit:  2,
+			expected: "\n## v1.2.0 \"Faster\" (2025-03-01)\n" +
+				"... (showing lines 2-3 of 7, use offset=3 to see more)\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := GitHubReleases(context.Background(), mockClient,
+				"owner", "repo", tt.from, tt.to, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Expected:\n%s\nGot:\n%s", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestGitHubReleases_Changelog(t *testing.T) {
+	mockClient := &MockGitHubClient{
+		releases: []repository.Release{{Tag: "v2.0.0"}, {Tag: "v1.0.0"}},
+		contents: map[string]string{
+			"CHANGES.md": "# Changelog\n\n## [2.1.0]\n- C\n\n## [2.0.0] - 2025-01-02\n- B\n\n" +
+				"## [2.0.0-rc1]\n- Pre\n\n## [1.0.0]\n- A\n",
+		},
+	}
+
+	result, err := GitHubReleases(
+		context.Background(), mockClient, "owner", "repo", "v1.0.0", "v2.0.0", 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	expected := "None of the releases of owner/repo after v1.0.0 up to v2.0.0 has release notes" +
+		" on GitHub, showing the changelog section from CHANGES.md:\n\n" +
+		"## [2.0.0] - 2025-01-02\n- B\n\n## [2.0.0-rc1]\n- Pre\n"
+	if result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	// Ranges without releases don't fall back to the changelog
+	result, err = GitHubReleases(
+		context.Background(), mockClient, "owner", "repo", "v2.0.0", "", 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := "No releases of owner/repo after v2.0.0\n"; result != expected {
+		t.Errorf("Expected:\n%s\nGot:\n%s", expected, result)
+	}
+
+	// The whole changelog isn't returned for versions it doesn't have
+	if _, err := GitHubReleases(
+		context.Background(), mockClient, "owner", "repo", "v0.9.0", "v1.0.0", 0, 0,
+	); err == nil {
+		t.Error("Expected error for a version missing from the changelog")
+	}
+
+	mockClient.contents = nil
+	if _, err := GitHubReleases(
+		context.Background(), mockClient, "owner", "repo", "v1.0.0", "", 0, 0,
+	); err == nil {
+		t.Error("Expected error without release notes and changelog")
+	}
+}
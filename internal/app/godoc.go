@@ -1,28 +1,26 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"log/slog"
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/fpt/go-dev-mcp/internal/contentsearch"
 	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/fpt/go-dev-mcp/internal/model"
 	"github.com/fpt/go-dev-mcp/pkg/dq"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"golang.org/x/net/html"
 )
 
 const DefaultLinesPerPage = 100
 
-var ErrNotFound = errors.New("not found")
-
-// Global cache for storing parsed documentation
-// TTL: 30 minutes, cleanup interval: 10 minutes
-var docCache = cache.New(30*time.Minute, 10*time.Minute)
+// ErrNotFound is returned when a package, symbol or document does not exist.
+// It is the same error as infra.ErrNotFound, which reports 404 responses.
+var ErrNotFound = infra.ErrNotFound
 
 // godocNodeFilter extends the default filter to also skip
 // pkg.go.dev version badge spans (e.g. "added in go1.21").
@@ -37,15 +35,12 @@ func godocNodeFilter(n *html.Node) bool {
 	return true
 }
 
-func SearchGoDoc(httpcli *infra.HttpClient, query string) (string, error) {
-	url := fmt.Sprintf("https://pkg.go.dev/search?q=%s", url.QueryEscape(query))
-	bodyrdr, err := httpcli.HttpGet(url)
+func SearchGoDoc(ctx context.Context, httpcli *infra.HttpClient, query string) (string, error) {
+	url := fmt.Sprintf("%s/search?q=%s", docSites.GoDoc, url.QueryEscape(query))
+	bodyrdr, err := httpcli.HttpGet(ctx, url)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to make HTTP request")
 	}
-	if bodyrdr == nil {
-		return "", ErrNotFound
-	}
 	defer bodyrdr.Close()
 
 	doc, err := html.Parse(bodyrdr)
@@ -56,82 +51,116 @@ func SearchGoDoc(httpcli *infra.HttpClient, query string) (string, error) {
 	if !matched {
 		matched, readme := parseReadme(doc)
 		if matched {
-			return readme, nil
+			return readme.Text, nil
 		}
 
-		_, document = parseDocument(doc)
+		_, parsed := parseDocument(doc)
+		document = parsed.Text
 	}
 
 	return document, nil
 }
 
 // ReadGoDocPaged reads Go documentation for a given package URL with line-based paging.
-// packageURL must be in "golang.org/x/net/html" format.
+// packageURL must be in "golang.org/x/net/html" format and version is either
+// empty (see ResolveGoDocVersion) or a version such as "v1.2.3" or "go1.22.0".
+// If symbol is set (e.g. "Client.Do" or "NewRequest"), only the documentation
+// of that declaration is returned.
+// source selects between local rendering (resolved from workdir) and pkg.go.dev.
 // Returns: content, totalLines, hasMore, error
 func ReadGoDocPaged(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
-	packageURL string,
+	workdir string,
+	packageURL, version, symbol string,
+	source DocSource,
 	offset, limit int,
 ) (string, int, bool, error) {
-	// Create cache key
-	cacheKey := fmt.Sprintf("godoc:%s", packageURL)
-
-	var document string
-
-	// Check cache first
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		// Cache miss - fetch and parse the document
-		url := fmt.Sprintf("https://pkg.go.dev/%s", url.PathEscape(packageURL))
-		bodyrdr, err := httpcli.HttpGet(url)
-		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return "", 0, false, ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to parse HTML")
-		}
-
-		// Get the full document first
-		matched, parsedDoc := parseDocument(doc)
+	document, err := loadGoDoc(ctx, httpcli, workdir, packageURL, version, source)
+	if err != nil {
+		return "", 0, false, err
+	}
 
-		// If no documentation found, try to parse the README section
-		if !matched {
-			_, parsedDoc = parseReadme(doc)
+	text := document.Text
+	if symbol != "" {
+		section, ok := document.section(goDocSymbolAnchors(symbol)...)
+		if !ok {
+			return "", 0, false, errors.Wrapf(ErrNotFound,
+				"symbol %s not found in %s", symbol, packageURL)
 		}
-
-		document = parsedDoc
-
-		// Cache the parsed document for future requests
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
+		text = section
 	}
 
-	// Split into lines and apply paging
-	lines := strings.Split(document, "\n")
-	totalLines := len(lines)
+	content, totalLines, hasMore := pageLines(text, offset, limit)
+	return content, totalLines, hasMore, nil
+}
 
-	// Apply offset and limit
-	startIdx := offset
-	if startIdx >= totalLines {
-		return "", totalLines, false, nil
+// goDocSymbolAnchors returns the anchors a symbol may refer to.
+// "Client.Do", "(*Client).Do" and "http.Client.Do" all denote "Client.Do".
+func goDocSymbolAnchors(symbol string) []string {
+	symbol = strings.NewReplacer("(", "", ")", "", "*", "").Replace(symbol)
+	anchors := []string{symbol}
+	if _, unqualified, ok := strings.Cut(symbol, "."); ok {
+		anchors = append(anchors, unqualified)
 	}
+	return anchors
+}
 
-	endIdx := startIdx + limit
-	hasMore := endIdx < totalLines
-	if endIdx > totalLines {
-		endIdx = totalLines
+// loadGoDoc returns the documentation of packageURL from the selected source.
+// With DocSourceAuto, pkg.go.dev is only used when the package cannot be
+// rendered locally.
+func loadGoDoc(
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	workdir string,
+	packageURL, version string,
+	source DocSource,
+) (*parsedDocument, error) {
+	if source != DocSourceRemote {
+		document, _, err := renderLocalGoDoc(workdir, packageURL, version)
+		if err == nil {
+			return document, nil
+		}
+		if source == DocSourceLocal {
+			return nil, err
+		}
+		slog.Debug("local documentation unavailable, using pkg.go.dev",
+			"package", packageURL, "error", err)
 	}
 
-	// Join the selected lines back together
-	pagedContent := strings.Join(lines[startIdx:endIdx], "\n")
+	return fetchGoDoc(ctx, httpcli, packageURL, version)
+}
 
-	return pagedContent, totalLines, hasMore, nil
+// fetchGoDoc fetches and parses the pkg.go.dev page of packageURL at version
+// (latest if empty). Parsed documents are cached per version.
+func fetchGoDoc(
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	packageURL, version string,
+) (*parsedDocument, error) {
+	ref := packageURL
+	if version != "" {
+		ref = packageURL + "@" + version
+	}
+
+	cacheKey := fmt.Sprintf("godoc:%s", ref)
+	url := fmt.Sprintf("%s/%s", docSites.GoDoc, url.PathEscape(ref))
+	return fetchCachedDoc(
+		ctx,
+		httpcli,
+		cacheKey,
+		url,
+		func(doc *html.Node) (*parsedDocument, error) {
+			// Get the full document first
+			matched, document := parseDocument(doc)
+
+			// If no documentation found, try to parse the README section
+			if !matched {
+				_, document = parseReadme(doc)
+			}
+			return document, nil
+		},
+	)
 }
 
 type GoDocSearchResult struct {
@@ -140,56 +169,31 @@ type GoDocSearchResult struct {
 	Truncated  bool
 }
 
-// SearchWithinGoDoc searches for a keyword within Go documentation and returns all matches.
+// SearchWithinGoDoc searches for the lines of Go documentation matching query and returns all matches.
 // Similar to SearchLocalFiles but for a single Go documentation page.
 func SearchWithinGoDoc(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
-	packageURL string,
-	keyword string,
-	maxMatches int,
+	workdir string,
+	packageURL, version string,
+	source DocSource,
+	query contentsearch.Query,
+	maxMatches, before, after int,
 ) (*GoDocSearchResult, error) {
-	// Create cache key
-	cacheKey := fmt.Sprintf("godoc:%s", packageURL)
-
-	var document string
-
-	// Check cache first
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		// Cache miss - fetch and parse the document
-		url := fmt.Sprintf("https://pkg.go.dev/%s", url.PathEscape(packageURL))
-		bodyrdr, err := httpcli.HttpGet(url)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return nil, ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse HTML")
-		}
-
-		// Get the full document first
-		matched, parsedDoc := parseDocument(doc)
-
-		// If no documentation found, try to parse the README section
-		if !matched {
-			_, parsedDoc = parseReadme(doc)
-		}
-
-		document = parsedDoc
+	matcher, err := contentsearch.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
 
-		// Cache the parsed document for future requests
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
+	document, err := loadGoDoc(ctx, httpcli, workdir, packageURL, version, source)
+	if err != nil {
+		return nil, err
 	}
 
 	// Search through the document using the shared contentsearch package
-	reader := strings.NewReader(document)
-	matches, truncated, err := contentsearch.SearchInContent(reader, keyword, maxMatches)
+	reader := strings.NewReader(document.Text)
+	matches, truncated, err := contentsearch.SearchInContent(
+		reader, matcher, maxMatches, before, after)
 	if err != nil {
 		return nil, err
 	}
@@ -275,8 +279,30 @@ func findVersionInNode(n *html.Node) string {
 	return ""
 }
 
-func parseDocument(doc *html.Node) (bool, string) {
-	builder := strings.Builder{}
+var (
+	isGoDocMemberHeader = dq.NewMatchFunc(
+		"h4.Documentation-typeFuncHeader,h4.Documentation-typeMethodHeader",
+	)
+	isGoDocCommentHeader = dq.NewMatchFunc("#hdr-*")
+)
+
+// goDocSectionLevel returns the section level of a pkg.go.dev heading. Constructors
+// and methods nest under their type, and doc comment headings under their declaration.
+func goDocSectionLevel(n *html.Node, level int) int {
+	switch {
+	case isGoDocCommentHeader(n):
+		return 6
+	case isGoDocMemberHeader(n):
+		return level + 1
+	default:
+		return level
+	}
+}
+
+// parseDocument parses the documentation section of a pkg.go.dev page.
+// Headings are indexed by their anchor (e.g. "Client", "Client.Do", "pkg-overview").
+func parseDocument(doc *html.Node) (bool, *parsedDocument) {
+	builder := &docBuilder{}
 	innerText := func(n *html.Node) string {
 		return dq.InnerTextWithFilter(n, true, godocNodeFilter)
 	}
@@ -286,6 +312,7 @@ func parseDocument(doc *html.Node) (bool, string) {
 		func(n *html.Node) {
 			h := strings.TrimPrefix(n.Data, "h")
 			hn, _ := strconv.Atoi(h)
+			builder.startSection(dq.GetAttr(n, "id"), goDocSectionLevel(n, hn))
 			builder.WriteString(
 				fmt.Sprintf("\n%s %s\n", strings.Repeat("#", hn), innerText(n)),
 			)
@@ -359,11 +386,11 @@ func parseDocument(doc *html.Node) (bool, string) {
 	)
 
 	dq.Traverse(doc, []dq.Matcher{rootMatcher})
-	return matched, builder.String()
+	return matched, builder.document()
 }
 
-func parseReadme(doc *html.Node) (bool, string) {
-	builder := strings.Builder{}
+func parseReadme(doc *html.Node) (bool, *parsedDocument) {
+	builder := &docBuilder{}
 	innerText := func(n *html.Node) string {
 		return dq.InnerTextWithFilter(n, true, godocNodeFilter)
 	}
@@ -373,6 +400,7 @@ func parseReadme(doc *html.Node) (bool, string) {
 		func(n *html.Node) {
 			h := strings.TrimPrefix(n.Data, "h")
 			hn, _ := strconv.Atoi(h)
+			builder.startSection(dq.GetAttr(n, "id"), hn)
 			builder.WriteString(
 				fmt.Sprintf("\n%s %s\n", strings.Repeat("#", hn), innerText(n)),
 			)
@@ -414,5 +442,5 @@ func parseReadme(doc *html.Node) (bool, string) {
 	)
 
 	dq.Traverse(doc, []dq.Matcher{rootMatcher})
-	return matched, builder.String()
+	return matched, builder.document()
 }
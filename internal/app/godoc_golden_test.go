@@ -42,16 +42,33 @@ func TestGolden_parseDocument(t *testing.T) {
 
 			goldenPath := filepath.Join("testdata", tt.golden)
 			if *update {
-				writeGolden(t, goldenPath, got)
+				writeGolden(t, goldenPath, got.Text)
 				return
 			}
 
 			want := readGolden(t, goldenPath)
-			assert.Equal(t, want, got, "output does not match golden file %s", tt.golden)
+			assert.Equal(t, want, got.Text, "output does not match golden file %s", tt.golden)
 		})
 	}
 }
 
+func TestParseDocument_Sections(t *testing.T) {
+	_, got := parseDocument(loadHTMLFixture(t, "doc_net_http.html"))
+
+	section, ok := got.section(goDocSymbolAnchors("(*http.Client).Do")...)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(section, "#### func (*Client) Do"), section)
+	assert.NotContains(t, section, "#### func (*Client) Get")
+
+	section, ok = got.section(goDocSymbolAnchors("Client")...)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(section, "#### type Client"), section)
+	assert.Contains(t, section, "#### func (*Client) Do")
+
+	_, ok = got.section(goDocSymbolAnchors("NoSuchSymbol")...)
+	assert.False(t, ok)
+}
+
 func TestGolden_parseSearchResult(t *testing.T) {
 	doc := loadHTMLFixture(t, "search_mcp_go.html")
 	matched, got := parseSearchResult(doc)
@@ -74,12 +91,12 @@ func TestGolden_parseReadme(t *testing.T) {
 
 	goldenPath := filepath.Join("testdata", "readme_testify.golden")
 	if *update {
-		writeGolden(t, goldenPath, got)
+		writeGolden(t, goldenPath, got.Text)
 		return
 	}
 
 	want := readGolden(t, goldenPath)
-	assert.Equal(t, want, got, "output does not match golden file readme_testify.golden")
+	assert.Equal(t, want, got.Text, "output does not match golden file readme_testify.golden")
 }
 
 func loadHTMLFixture(t *testing.T, name string) *html.Node {
@@ -0,0 +1,529 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/doc"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/patrickmn/go-cache"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/module"
+)
+
+// DocSource selects where Go documentation is read from.
+type DocSource string
+
+const (
+	// DocSourceAuto renders docs locally when the package can be resolved
+	// from the workdir's go.mod or GOROOT, and falls back to pkg.go.dev.
+	DocSourceAuto DocSource = "auto"
+	// DocSourceLocal renders docs with go/doc from GOROOT or the module cache.
+	DocSourceLocal DocSource = "local"
+	// DocSourceRemote scrapes pkg.go.dev.
+	DocSourceRemote DocSource = "remote"
+)
+
+// ParseDocSource validates a documentation source name.
+// An empty string selects DocSourceAuto.
+func ParseDocSource(source string) (DocSource, error) {
+	switch DocSource(source) {
+	case "", DocSourceAuto:
+		return DocSourceAuto, nil
+	case DocSourceLocal, DocSourceRemote:
+		return DocSource(source), nil
+	default:
+		return "", errors.Errorf("invalid source %q: must be local, remote or auto", source)
+	}
+}
+
+// LocalPackage is a package resolved to a directory on disk.
+type LocalPackage struct {
+	ImportPath string
+	Dir        string
+	Module     string // Module path, empty for the standard library
+	Version    string // Module version, empty for the standard library and the main module
+}
+
+// ResolveLocalPackage resolves an import path to its source directory
+// without network access. Standard library packages resolve to
+// $GOROOT/src. Without a version, other packages are looked up in the go.mod
+// enclosing workdir (honoring replace directives) and resolve to the main
+// module itself or to the exact required version in $GOMODCACHE. With a
+// version, the package is looked up at that version in $GOMODCACHE.
+func ResolveLocalPackage(workdir, importPath, version string) (*LocalPackage, error) {
+	env, err := readGoEnv(workdir)
+	if err != nil {
+		return nil, err
+	}
+
+	if isGorootPackage(importPath) {
+		if version != "" && version != env.GoVersion {
+			return nil, errors.Wrapf(
+				ErrNotFound,
+				"GOROOT provides %s, not %s",
+				env.GoVersion,
+				version,
+			)
+		}
+		dir := filepath.Join(env.GoRoot, "src", filepath.FromSlash(importPath))
+		if !isDir(dir) {
+			return nil, errors.Wrapf(ErrNotFound, "package %s not found in GOROOT", importPath)
+		}
+		return &LocalPackage{ImportPath: importPath, Dir: dir}, nil
+	}
+
+	if version != "" {
+		return resolveModuleCachePackage(env.GoModCache, importPath, version)
+	}
+
+	root, mf, err := readGoMod(workdir)
+	if err != nil {
+		return nil, err
+	}
+
+	// The main module
+	if mf.Module != nil {
+		if sub, ok := subpackagePath(mf.Module.Mod.Path, importPath); ok {
+			return newLocalPackage(importPath, mf.Module.Mod.Path, "", filepath.Join(root, sub))
+		}
+	}
+
+	req := requiredModule(mf, importPath)
+	if req == nil {
+		return nil, errors.Wrapf(ErrNotFound, "%s is not required by %s", importPath,
+			filepath.Join(root, "go.mod"))
+	}
+	sub, _ := subpackagePath(req.Path, importPath)
+	mod := *req
+
+	for _, r := range mf.Replace {
+		if r.Old.Path != mod.Path || (r.Old.Version != "" && r.Old.Version != mod.Version) {
+			continue
+		}
+		if modfile.IsDirectoryPath(r.New.Path) {
+			dir := r.New.Path
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(root, dir)
+			}
+			return newLocalPackage(importPath, req.Path, req.Version, filepath.Join(dir, sub))
+		}
+		mod = r.New
+	}
+
+	dir, err := moduleCacheDir(env.GoModCache, mod)
+	if err != nil {
+		return nil, err
+	}
+	if !isDir(dir) {
+		return nil, errors.Wrapf(ErrNotFound,
+			"%s@%s is not in the module cache (run 'go mod download')", mod.Path, mod.Version)
+	}
+
+	return newLocalPackage(importPath, req.Path, req.Version, filepath.Join(dir, sub))
+}
+
+// resolveModuleCachePackage finds importPath at version in the module cache.
+// Since the module boundary is unknown, every path prefix of importPath is
+// tried as the module path, longest first.
+func resolveModuleCachePackage(modcache, importPath, version string) (*LocalPackage, error) {
+	for modPath := importPath; ; {
+		dir, err := moduleCacheDir(modcache, module.Version{Path: modPath, Version: version})
+		if err == nil && isDir(dir) {
+			sub, _ := subpackagePath(modPath, importPath)
+			return newLocalPackage(importPath, modPath, version, filepath.Join(dir, sub))
+		}
+
+		i := strings.LastIndex(modPath, "/")
+		if i < 0 {
+			break
+		}
+		modPath = modPath[:i]
+	}
+
+	return nil, errors.Wrapf(ErrNotFound,
+		"%s@%s is not in the module cache (run 'go mod download')", importPath, version)
+}
+
+// moduleCacheDir returns the extracted source directory of mod in the module cache.
+func moduleCacheDir(modcache string, mod module.Version) (string, error) {
+	escPath, err := module.EscapePath(mod.Path)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid module path")
+	}
+	escVersion, err := module.EscapeVersion(mod.Version)
+	if err != nil {
+		return "", errors.Wrap(err, "invalid module version")
+	}
+	return filepath.Join(modcache, escPath+"@"+escVersion), nil
+}
+
+// readGoMod parses the go.mod enclosing dir and returns its directory.
+func readGoMod(dir string) (string, *modfile.File, error) {
+	root, _ := findModuleRoot(dir)
+	if root == "" {
+		return "", nil, errors.Wrapf(ErrNotFound, "no go.mod found for %s", dir)
+	}
+	gomod := filepath.Join(root, "go.mod")
+	data, err := os.ReadFile(gomod)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to read go.mod")
+	}
+	mf, err := modfile.Parse(gomod, data, nil)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to parse go.mod")
+	}
+	return root, mf, nil
+}
+
+// requiredModule returns the longest required module path that prefixes
+// importPath, or nil if there is none.
+func requiredModule(mf *modfile.File, importPath string) *module.Version {
+	var req *module.Version
+	for _, r := range mf.Require {
+		if _, ok := subpackagePath(r.Mod.Path, importPath); ok &&
+			(req == nil || len(r.Mod.Path) > len(req.Path)) {
+			req = &r.Mod
+		}
+	}
+	return req
+}
+
+// isGorootPackage reports whether importPath belongs to the standard library,
+// i.e. its first path element contains no dot. Unlike isStandardLibrary,
+// golang.org/x modules are not included.
+func isGorootPackage(importPath string) bool {
+	first, _, _ := strings.Cut(importPath, "/")
+	return !strings.Contains(first, ".")
+}
+
+func newLocalPackage(importPath, modPath, version, dir string) (*LocalPackage, error) {
+	if !isDir(dir) {
+		return nil, errors.Wrapf(ErrNotFound, "package %s not found in %s", importPath, dir)
+	}
+	return &LocalPackage{ImportPath: importPath, Dir: dir, Module: modPath, Version: version}, nil
+}
+
+// subpackagePath returns the directory of importPath relative to the root
+// of the module modPath.
+func subpackagePath(modPath, importPath string) (string, bool) {
+	if importPath == modPath {
+		return "", true
+	}
+	if strings.HasPrefix(importPath, modPath+"/") {
+		return filepath.FromSlash(importPath[len(modPath)+1:]), true
+	}
+	return "", false
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// goEnv holds the go command settings used to locate package sources.
+type goEnv struct {
+	GoRoot     string `json:"GOROOT"`
+	GoModCache string `json:"GOMODCACHE"`
+	GoVersion  string `json:"GOVERSION"`
+}
+
+// readGoEnv returns the go command settings as seen from workdir.
+func readGoEnv(workdir string) (*goEnv, error) {
+	cacheKey := "goenv:" + workdir
+	if cached, found := docCache.Get(cacheKey); found {
+		return cached.(*goEnv), nil
+	}
+
+	stdout, stderr, exitCode, err := infra.Run(
+		workdir,
+		"go",
+		"env",
+		"-json",
+		"GOROOT",
+		"GOMODCACHE",
+		"GOVERSION",
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to run go env")
+	}
+	if exitCode != 0 {
+		return nil, errors.Errorf("go env failed: %s", stderr)
+	}
+	var env goEnv
+	if err := json.Unmarshal([]byte(stdout), &env); err != nil {
+		return nil, errors.Wrap(err, "failed to parse go env output")
+	}
+
+	docCache.Set(cacheKey, &env, cache.NoExpiration)
+	return &env, nil
+}
+
+// renderLocalGoDoc resolves importPath (at version, if set) from workdir and renders its
+// documentation with go/doc in the same layout as the pkg.go.dev parser.
+func renderLocalGoDoc(workdir, importPath, version string) (*parsedDocument, *LocalPackage, error) {
+	lp, err := ResolveLocalPackage(workdir, importPath, version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cacheKey := fmt.Sprintf("godoc:local:%s", lp.Dir)
+	if cached, found := docCache.Get(cacheKey); found {
+		return cached.(*parsedDocument), lp, nil
+	}
+
+	fset := token.NewFileSet()
+	pkg, err := parseDocPackage(fset, lp)
+	if err != nil {
+		return nil, nil, err
+	}
+	document := renderGoDoc(fset, pkg)
+
+	docCache.Set(cacheKey, document, cache.DefaultExpiration)
+	return document, lp, nil
+}
+
+// parseDocPackage parses the files of a package, including tests for examples,
+// as selected by the default build context.
+func parseDocPackage(fset *token.FileSet, lp *LocalPackage) (*doc.Package, error) {
+	bp, err := build.Default.ImportDir(lp.Dir, build.ImportComment)
+	if err != nil {
+		var noGo *build.NoGoError
+		if errors.As(err, &noGo) {
+			return nil, errors.Wrapf(ErrNotFound, "no Go files in %s", lp.Dir)
+		}
+		return nil, errors.Wrap(err, "failed to import package")
+	}
+
+	var names []string
+	names = append(names, bp.GoFiles...)
+	names = append(names, bp.CgoFiles...)
+	names = append(names, bp.TestGoFiles...)
+	names = append(names, bp.XTestGoFiles...)
+
+	files := make([]*ast.File, 0, len(names))
+	for _, name := range names {
+		f, err := parser.ParseFile(fset, filepath.Join(lp.Dir, name), nil, parser.ParseComments)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s", name)
+		}
+		files = append(files, f)
+	}
+
+	pkg, err := doc.NewFromFiles(fset, files, lp.ImportPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute package documentation")
+	}
+	return pkg, nil
+}
+
+// renderGoDoc renders package documentation as markdown-like text with the
+// same sections, headings and section anchors as parseDocument produces for pkg.go.dev.
+func renderGoDoc(fset *token.FileSet, pkg *doc.Package) *parsedDocument {
+	r := &goDocRenderer{fset: fset, pkg: pkg}
+	sb := &r.sb
+
+	r.writeSection("pkg-overview", 3, "### Overview")
+	r.writeText(pkg.Doc)
+
+	// Index
+	r.writeSection("pkg-index", 3, "### Index")
+	if len(pkg.Consts) > 0 {
+		sb.WriteString("- Constants\n")
+	}
+	if len(pkg.Vars) > 0 {
+		sb.WriteString("- Variables\n")
+	}
+	for _, f := range pkg.Funcs {
+		sb.WriteString(fmt.Sprintf("- %s\n", r.funcSignature(f.Decl)))
+	}
+	for _, t := range pkg.Types {
+		sb.WriteString(fmt.Sprintf("- type %s\n", t.Name))
+		for _, f := range t.Funcs {
+			sb.WriteString(fmt.Sprintf("    - %s\n", r.funcSignature(f.Decl)))
+		}
+		for _, m := range t.Methods {
+			sb.WriteString(fmt.Sprintf("    - %s\n", r.funcSignature(m.Decl)))
+		}
+	}
+
+	if examples := allExamples(pkg); len(examples) > 0 {
+		r.writeSection("pkg-examples", 3, "### Examples")
+		for _, ex := range examples {
+			sb.WriteString(fmt.Sprintf("- %s\n", exampleName(ex)))
+		}
+	}
+
+	r.writeSection("pkg-constants", 3, "### Constants")
+	if len(pkg.Consts) == 0 {
+		sb.WriteString("This section is empty.\n")
+	}
+	r.writeValues(pkg.Consts)
+	r.writeSection("pkg-variables", 3, "### Variables")
+	if len(pkg.Vars) == 0 {
+		sb.WriteString("This section is empty.\n")
+	}
+	r.writeValues(pkg.Vars)
+
+	r.writeSection("pkg-functions", 3, "### Functions")
+	for _, f := range pkg.Funcs {
+		r.writeFunc(f, 4)
+	}
+
+	r.writeSection("pkg-types", 3, "### Types")
+	for _, t := range pkg.Types {
+		r.writeSection(t.Name, 4, "#### type "+t.Name)
+		r.writeCode(r.print(t.Decl))
+		r.writeText(t.Doc)
+		r.writeExamples(t.Examples)
+		r.writeValues(t.Consts)
+		r.writeValues(t.Vars)
+		for _, f := range t.Funcs {
+			r.writeFunc(f, 5)
+		}
+		for _, m := range t.Methods {
+			r.writeFunc(m, 5)
+		}
+	}
+
+	return sb.document()
+}
+
+type goDocRenderer struct {
+	fset *token.FileSet
+	pkg  *doc.Package
+	sb   docBuilder
+}
+
+// writeSection writes a heading that starts the section anchor at the given level.
+func (r *goDocRenderer) writeSection(anchor string, level int, heading string) {
+	r.sb.WriteString("\n")
+	r.sb.startSection(anchor, level)
+	r.sb.WriteString(heading + "\n")
+}
+
+func (r *goDocRenderer) writeText(comment string) {
+	if text := strings.TrimSpace(string(r.pkg.Text(comment))); text != "" {
+		r.sb.WriteString(text + "\n")
+	}
+}
+
+func (r *goDocRenderer) writeCode(code string) {
+	r.sb.WriteString(fmt.Sprintf("```\n%s\n```\n", code))
+}
+
+func (r *goDocRenderer) writeValues(values []*doc.Value) {
+	for _, v := range values {
+		r.writeCode(r.print(v.Decl))
+		r.writeText(v.Doc)
+	}
+}
+
+// writeFunc writes a function or method. Constructors and methods are passed
+// level 5 to nest them in the section of their type; the heading stays "####"
+// like on pkg.go.dev.
+func (r *goDocRenderer) writeFunc(f *doc.Func, level int) {
+	anchor, heading := f.Name, "#### func "+f.Name
+	if f.Recv != "" {
+		recv, _, _ := strings.Cut(strings.TrimPrefix(f.Recv, "*"), "[")
+		anchor = recv + "." + f.Name
+		heading = fmt.Sprintf("#### func (%s) %s", f.Recv, f.Name)
+	}
+	r.writeSection(anchor, level, heading)
+	r.writeCode(r.funcSignature(f.Decl))
+	r.writeText(f.Doc)
+	r.writeExamples(f.Examples)
+}
+
+func (r *goDocRenderer) writeExamples(examples []*doc.Example) {
+	for _, ex := range examples {
+		r.sb.WriteString(fmt.Sprintf("Example (%s):\n", exampleName(ex)))
+		if ex.Play != nil {
+			r.writeCode(r.print(ex.Play))
+		} else {
+			r.writeCode(exampleBody(r.print(ex.Code)))
+		}
+		if ex.Output != "" {
+			r.sb.WriteString(fmt.Sprintf("Output:\n```\n%s\n```\n", strings.TrimSpace(ex.Output)))
+		}
+	}
+}
+
+func (r *goDocRenderer) funcSignature(decl *ast.FuncDecl) string {
+	return r.print(stripBody(decl))
+}
+
+func (r *goDocRenderer) print(node any) string {
+	var buf bytes.Buffer
+	cfg := printer.Config{Mode: printer.UseSpaces | printer.TabIndent, Tabwidth: 8}
+	if err := cfg.Fprint(&buf, r.fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// stripBody returns a copy of decl without its body and doc comment.
+func stripBody(decl *ast.FuncDecl) *ast.FuncDecl {
+	stripped := *decl
+	stripped.Body = nil
+	stripped.Doc = nil
+	return &stripped
+}
+
+// exampleBody removes the braces and one level of indentation
+// from a printed example block.
+func exampleBody(code string) string {
+	code = strings.TrimSpace(code)
+	code = strings.TrimPrefix(code, "{")
+	code = strings.TrimSuffix(code, "}")
+	lines := strings.Split(strings.Trim(code, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, "\t")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func exampleName(ex *doc.Example) string {
+	name := ex.Name
+	if name == "" {
+		name = "Package"
+	}
+	if ex.Suffix != "" {
+		name = strings.TrimSuffix(name, "_"+ex.Suffix)
+		if name == "" {
+			name = "Package"
+		}
+		name = fmt.Sprintf("%s (%s)", name, ex.Suffix)
+	}
+	return name
+}
+
+// allExamples collects the examples of a package sorted by name.
+func allExamples(pkg *doc.Package) []*doc.Example {
+	examples := append([]*doc.Example{}, pkg.Examples...)
+	for _, f := range pkg.Funcs {
+		examples = append(examples, f.Examples...)
+	}
+	for _, t := range pkg.Types {
+		examples = append(examples, t.Examples...)
+		for _, f := range t.Funcs {
+			examples = append(examples, f.Examples...)
+		}
+		for _, m := range t.Methods {
+			examples = append(examples, m.Examples...)
+		}
+	}
+	sort.SliceStable(examples, func(i, j int) bool { return examples[i].Name < examples[j].Name })
+	return examples
+}
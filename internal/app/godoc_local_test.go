@@ -0,0 +1,167 @@
+package app
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var localDocTestFiles = map[string]string{
+	"greet/greet.go": `// Package greet builds greetings.
+package greet
+
+// Greeter greets people.
+type Greeter struct {
+	// Prefix is put before every name.
+	Prefix string
+}
+
+// New returns a Greeter with the default prefix.
+func New() *Greeter { return &Greeter{Prefix: "Hello"} }
+
+// Greet greets name.
+func (g *Greeter) Greet(name string) string { return g.Prefix + ", " + name }
+
+// Shout greets loudly.
+func Shout(name string) string { return name + "!" }
+`,
+	"greet/example_test.go": `package greet_test
+
+import (
+	"fmt"
+
+	"example.com/sample/greet"
+)
+
+func ExampleShout() {
+	fmt.Println(greet.Shout("hi"))
+	// Output: hi!
+}
+`,
+}
+
+func TestResolveLocalPackage(t *testing.T) {
+	dir := writeTypedTestModule(t, localDocTestFiles)
+
+	lp, err := ResolveLocalPackage(dir, "io/fs", "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(lp.Dir, filepath.Join("src", "io", "fs")), lp.Dir)
+	assert.Empty(t, lp.Module)
+
+	lp, err = ResolveLocalPackage(dir, "example.com/sample/greet", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "greet"), lp.Dir)
+	assert.Equal(t, "example.com/sample", lp.Module)
+
+	_, err = ResolveLocalPackage(dir, "example.com/unknown/pkg", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestResolveLocalPackage_Replace(t *testing.T) {
+	dir := writeTypedTestModule(t, nil)
+	other := writeTypedTestModule(t, map[string]string{"lib/lib.go": "package lib\n"})
+	goMod := "module example.com/sample\n\ngo 1.21\n\n" +
+		"require example.com/other v1.2.3\n\n" +
+		"replace example.com/other => " + other + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o600))
+
+	lp, err := ResolveLocalPackage(dir, "example.com/other/lib", "")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(other, "lib"), lp.Dir)
+	assert.Equal(t, "example.com/other", lp.Module)
+	assert.Equal(t, "v1.2.3", lp.Version)
+}
+
+func TestRenderLocalGoDoc(t *testing.T) {
+	dir := writeTypedTestModule(t, localDocTestFiles)
+
+	parsed, _, err := renderLocalGoDoc(dir, "example.com/sample/greet", "")
+	require.NoError(t, err)
+	document := parsed.Text
+
+	assert.Contains(t, document, "### Overview\nPackage greet builds greetings.\n")
+	assert.Contains(t, document, "- func Shout(name string) string\n")
+	assert.Contains(t, document, "- type Greeter\n    - func New() *Greeter\n"+
+		"    - func (g *Greeter) Greet(name string) string\n")
+	assert.Contains(t, document, "### Examples\n- Shout\n")
+	assert.Contains(t, document, "### Constants\nThis section is empty.\n")
+	assert.Contains(t, document, "#### type Greeter\n```\ntype Greeter struct {\n"+
+		"\t// Prefix is put before every name.\n\tPrefix string\n}\n```\nGreeter greets people.\n")
+	assert.Contains(t, document, "#### func (*Greeter) Greet\n")
+	assert.Contains(t, document, "Output:\n```\nhi!\n```\n")
+}
+
+func TestReadGoDocPaged_Local(t *testing.T) {
+	dir := writeTypedTestModule(t, localDocTestFiles)
+
+	content, totalLines, hasMore, err := ReadGoDocPaged(
+		context.Background(),
+		infra.NewHttpClient(),
+		dir,
+		"example.com/sample/greet",
+		"",
+		"",
+		DocSourceLocal,
+		0,
+		3,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "\n### Overview\nPackage greet builds greetings.", content)
+	assert.True(t, hasMore)
+	assert.Greater(t, totalLines, 3)
+
+	_, _, _, err = ReadGoDocPaged(
+		context.Background(),
+		infra.NewHttpClient(),
+		dir,
+		"example.com/unknown/pkg",
+		"",
+		"",
+		DocSourceLocal,
+		0,
+		3,
+	)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestReadGoDocPaged_Symbol(t *testing.T) {
+	ctx := context.Background()
+	dir := writeTypedTestModule(t, localDocTestFiles)
+
+	content, _, hasMore, err := ReadGoDocPaged(ctx, infra.NewHttpClient(), dir,
+		"example.com/sample/greet", "", "(*Greeter).Greet", DocSourceLocal, 0, 100)
+	require.NoError(t, err)
+	assert.False(t, hasMore)
+	assert.True(t, strings.HasPrefix(content, "#### func (*Greeter) Greet\n"), content)
+	assert.NotContains(t, content, "### Types")
+
+	content, _, _, err = ReadGoDocPaged(ctx, infra.NewHttpClient(), dir,
+		"example.com/sample/greet", "", "Greeter", DocSourceLocal, 0, 100)
+	require.NoError(t, err)
+	assert.Contains(t, content, "#### func New\n")
+	assert.Contains(t, content, "#### func (*Greeter) Greet\n")
+	assert.NotContains(t, content, "#### func Shout")
+
+	_, _, _, err = ReadGoDocPaged(ctx, infra.NewHttpClient(), dir,
+		"example.com/sample/greet", "", "Missing", DocSourceLocal, 0, 100)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestParseDocSource(t *testing.T) {
+	source, err := ParseDocSource("")
+	require.NoError(t, err)
+	assert.Equal(t, DocSourceAuto, source)
+
+	source, err = ParseDocSource("local")
+	require.NoError(t, err)
+	assert.Equal(t, DocSourceLocal, source)
+
+	_, err = ParseDocSource("cache")
+	assert.Error(t, err)
+}
@@ -0,0 +1,110 @@
+package app
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/mod/semver"
+)
+
+// GoDocVersionModule selects the version of a package that the module in the
+// server workdir depends on, as pinned by its go.mod or go.sum.
+const GoDocVersionModule = "module"
+
+// ResolveGoDocVersion splits a "pkg@version" reference and applies the
+// version argument, which takes precedence over a version in packageURL.
+// GoDocVersionModule is replaced by the version pinned in workdir.
+// An empty version means the latest version (remote) or the pinned version (local).
+func ResolveGoDocVersion(workdir, packageURL, version string) (string, string, error) {
+	pkg, refVersion, _ := strings.Cut(packageURL, "@")
+	if version == "" {
+		version = refVersion
+	}
+	if version != GoDocVersionModule {
+		return pkg, version, nil
+	}
+
+	pinned, err := PinnedModuleVersion(workdir, pkg)
+	if err != nil {
+		return "", "", err
+	}
+	return pkg, pinned, nil
+}
+
+// PinnedModuleVersion returns the version of the module providing importPath
+// that the module enclosing workdir builds with. Requirements in go.mod take
+// precedence; modules only listed in go.sum use the highest listed version.
+// Standard library packages resolve to the version of the go command.
+func PinnedModuleVersion(workdir, importPath string) (string, error) {
+	if isGorootPackage(importPath) {
+		env, err := readGoEnv(workdir)
+		if err != nil {
+			return "", err
+		}
+		return env.GoVersion, nil
+	}
+
+	root, mf, err := readGoMod(workdir)
+	if err != nil {
+		return "", err
+	}
+	if mf.Module != nil {
+		if _, ok := subpackagePath(mf.Module.Mod.Path, importPath); ok {
+			return "", errors.Errorf(
+				"%s is part of the main module %s",
+				importPath,
+				mf.Module.Mod.Path,
+			)
+		}
+	}
+	if req := requiredModule(mf, importPath); req != nil {
+		return req.Version, nil
+	}
+
+	version, err := goSumVersion(filepath.Join(root, "go.sum"), importPath)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", errors.Wrapf(ErrNotFound, "%s is not a dependency of %s", importPath, root)
+	}
+	return version, nil
+}
+
+// goSumVersion returns the highest version listed in a go.sum file for the
+// longest module path that prefixes importPath, or "" if none matches.
+func goSumVersion(gosum, importPath string) (string, error) {
+	f, err := os.Open(gosum)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", errors.Wrap(err, "failed to open go.sum")
+	}
+	defer f.Close()
+
+	var modPath, version string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Lines are "<module> <version>[/go.mod] <hash>"
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		path, v := fields[0], strings.TrimSuffix(fields[1], "/go.mod")
+		if _, ok := subpackagePath(path, importPath); !ok || len(path) < len(modPath) {
+			continue
+		}
+		if len(path) > len(modPath) || semver.Compare(v, version) > 0 {
+			modPath, version = path, v
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", errors.Wrap(err, "failed to read go.sum")
+	}
+
+	return version, nil
+}
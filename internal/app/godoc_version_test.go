@@ -0,0 +1,88 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const versionTestGoMod = `module example.com/sample
+
+go 1.21
+
+require example.com/lib v1.2.0
+`
+
+const versionTestGoSum = `example.com/lib v1.2.0 h1:abc=
+example.com/lib v1.2.0/go.mod h1:def=
+example.com/indirect v1.10.0 h1:abc=
+example.com/indirect v1.9.0 h1:abc=
+example.com/indirect v1.9.0/go.mod h1:def=
+`
+
+func writeVersionTestModule(t *testing.T) string {
+	t.Helper()
+
+	dir := writeTypedTestModule(t, map[string]string{"go.sum": versionTestGoSum})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(versionTestGoMod), 0o600))
+	return dir
+}
+
+func TestResolveGoDocVersion(t *testing.T) {
+	dir := writeVersionTestModule(t)
+
+	tests := []struct {
+		name       string
+		packageURL string
+		version    string
+		wantPkg    string
+		wantVer    string
+	}{
+		{"latest", "example.com/lib/sub", "", "example.com/lib/sub", ""},
+		{"reference", "example.com/lib/sub@v1.0.0", "", "example.com/lib/sub", "v1.0.0"},
+		{"argument overrides", "example.com/lib@v1.0.0", "v1.1.0", "example.com/lib", "v1.1.0"},
+		{"go.mod", "example.com/lib/sub", GoDocVersionModule, "example.com/lib/sub", "v1.2.0"},
+		{"go.sum", "example.com/indirect", GoDocVersionModule, "example.com/indirect", "v1.10.0"},
+		{"reference module", "example.com/lib@module", "", "example.com/lib", "v1.2.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, version, err := ResolveGoDocVersion(dir, tt.packageURL, tt.version)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPkg, pkg)
+			assert.Equal(t, tt.wantVer, version)
+		})
+	}
+}
+
+func TestPinnedModuleVersion_Errors(t *testing.T) {
+	dir := writeVersionTestModule(t)
+
+	_, err := PinnedModuleVersion(dir, "example.com/sample/internal")
+	assert.Error(t, err)
+
+	_, err = PinnedModuleVersion(dir, "example.com/unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestResolveLocalPackage_Version(t *testing.T) {
+	modcache := t.TempDir()
+	t.Setenv("GOMODCACHE", modcache)
+	dir := writeVersionTestModule(t)
+
+	pkgDir := filepath.Join(modcache, "example.com", "lib@v1.0.0", "sub")
+	require.NoError(t, os.MkdirAll(pkgDir, 0o755))
+
+	lp, err := ResolveLocalPackage(dir, "example.com/lib/sub", "v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, pkgDir, lp.Dir)
+	assert.Equal(t, "example.com/lib", lp.Module)
+	assert.Equal(t, "v1.0.0", lp.Version)
+
+	// The pinned version v1.2.0 has not been downloaded
+	_, err = ResolveLocalPackage(dir, "example.com/lib/sub", "")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,217 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Implementation is a type related to the queried type by interface satisfaction.
+type Implementation struct {
+	Type     string // Qualified type name (e.g. "github.com/user/repo/infra.FileWalker")
+	Package  string // Import path of the declaring package
+	Kind     string // "struct", "interface", "type"
+	Receiver string // "value" or "pointer": whether T or only *T satisfies the interface
+	Position string // Declaring position, empty if unknown
+}
+
+// ImplementationsResult holds the types related to a queried type.
+// For an interface, Implementations lists the concrete types satisfying it;
+// for any other type, it lists the interfaces the type implements.
+type ImplementationsResult struct {
+	Symbol          string
+	IsInterface     bool
+	Definition      string
+	Implementations []Implementation // Sorted by package and type name
+}
+
+// FindImplementations type-checks the module containing directory and relates
+// the type denoted by query to interfaces. Given an interface, every concrete
+// type of the module satisfying it is returned; given a concrete type, every
+// interface it implements. With includeDeps, types declared in dependencies
+// (including the standard library) are considered as well.
+// query is either "file:line:column" or a qualified name such as
+// "internal/repository.DirWalker".
+func FindImplementations(
+	ctx context.Context, directory, query string, includeDeps bool,
+) (*ImplementationsResult, error) {
+	pkgs, err := loadTypedPackages(ctx, directory, false)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := resolveSymbol(pkgs, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve symbol")
+	}
+	tn, ok := target.Object.(*types.TypeName)
+	if !ok {
+		return nil, errors.Errorf("%s is a %s, not a type",
+			qualifiedObjectName(target.Object), objectKind(target.Object))
+	}
+
+	result := &ImplementationsResult{
+		Symbol:      qualifiedObjectName(tn),
+		IsInterface: types.IsInterface(tn.Type()),
+	}
+	if tn.Pos().IsValid() {
+		result.Definition = target.Fset.Position(tn.Pos()).String()
+	}
+
+	var roots []*types.Package
+	for _, pkg := range pkgs {
+		if pkg.Types != nil {
+			roots = append(roots, pkg.Types)
+		}
+	}
+	scope := roots
+	if includeDeps {
+		scope = withImports(roots)
+	}
+
+	seen := make(map[*types.TypeName]bool)
+	for _, tpkg := range scope {
+		for _, name := range tpkg.Scope().Names() {
+			candidate, ok := tpkg.Scope().Lookup(name).(*types.TypeName)
+			if !ok || candidate == tn || candidate.IsAlias() || seen[candidate] {
+				continue
+			}
+			seen[candidate] = true
+
+			var receiver string
+			if result.IsInterface {
+				receiver = implementsWith(candidate, tn)
+			} else {
+				receiver = implementsWith(tn, candidate)
+			}
+			if receiver == "" {
+				continue
+			}
+
+			impl := Implementation{
+				Type:     qualifiedObjectName(candidate),
+				Package:  tpkg.Path(),
+				Kind:     typeKind(candidate),
+				Receiver: receiver,
+			}
+			if candidate.Pos().IsValid() {
+				impl.Position = target.Fset.Position(candidate.Pos()).String()
+			}
+			result.Implementations = append(result.Implementations, impl)
+		}
+	}
+
+	sort.Slice(result.Implementations, func(i, j int) bool {
+		a, b := result.Implementations[i], result.Implementations[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		return a.Type < b.Type
+	})
+
+	return result, nil
+}
+
+// implementsWith reports how the concrete type concrete satisfies the interface
+// iface: "value" if T does, "pointer" if only *T does, and "" if neither.
+// Generic types, empty interfaces and constraint interfaces never match.
+func implementsWith(concrete, iface *types.TypeName) string {
+	if types.IsInterface(concrete.Type()) || !types.IsInterface(iface.Type()) {
+		return ""
+	}
+	if isGeneric(concrete) || isGeneric(iface) {
+		return ""
+	}
+	it, ok := iface.Type().Underlying().(*types.Interface)
+	if !ok || it.NumMethods() == 0 || !it.IsMethodSet() {
+		return ""
+	}
+
+	if types.Implements(concrete.Type(), it) {
+		return "value"
+	}
+	if types.Implements(types.NewPointer(concrete.Type()), it) {
+		return "pointer"
+	}
+	return ""
+}
+
+func isGeneric(tn *types.TypeName) bool {
+	named, ok := tn.Type().(*types.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// typeKind classifies a named type like the outline does.
+func typeKind(tn *types.TypeName) string {
+	switch tn.Type().Underlying().(type) {
+	case *types.Struct:
+		return "struct"
+	case *types.Interface:
+		return "interface"
+	default:
+		return "type"
+	}
+}
+
+// withImports returns roots followed by all packages they transitively import.
+func withImports(roots []*types.Package) []*types.Package {
+	seen := make(map[*types.Package]bool)
+	var all []*types.Package
+	var visit func(p *types.Package)
+	visit = func(p *types.Package) {
+		if seen[p] {
+			return
+		}
+		seen[p] = true
+		all = append(all, p)
+		for _, imp := range p.Imports() {
+			visit(imp)
+		}
+	}
+	for _, p := range roots {
+		visit(p)
+	}
+	return all
+}
+
+// FormatImplementations renders implementations grouped by package.
+// File paths are shown relative to baseDir when possible.
+func FormatImplementations(result *ImplementationsResult, baseDir string) string {
+	var sb strings.Builder
+
+	noun := "interface"
+	if result.IsInterface {
+		sb.WriteString(fmt.Sprintf("Implementations of interface %s\n", result.Symbol))
+		noun = "type"
+	} else {
+		sb.WriteString(fmt.Sprintf("Interfaces implemented by %s\n", result.Symbol))
+	}
+	if result.Definition != "" {
+		sb.WriteString(fmt.Sprintf("Defined at: %s\n", relativePath(baseDir, result.Definition)))
+	}
+
+	if len(result.Implementations) == 0 {
+		sb.WriteString("None found.\n")
+		return sb.String()
+	}
+	sb.WriteString(fmt.Sprintf("Total: %s\n", pluralize(len(result.Implementations), noun)))
+
+	for i, impl := range result.Implementations {
+		if i == 0 || impl.Package != result.Implementations[i-1].Package {
+			sb.WriteString(fmt.Sprintf("\nPackage %s\n", impl.Package))
+		}
+
+		name := impl.Type[strings.LastIndex(impl.Type, ".")+1:]
+		line := fmt.Sprintf("  %s (%s, %s receiver)", name, impl.Kind, impl.Receiver)
+		if impl.Position != "" {
+			line += " " + relativePath(baseDir, impl.Position)
+		}
+		sb.WriteString(line + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var implementationsTestFiles = map[string]string{
+	"shape/shape.go": `package shape
+
+import "fmt"
+
+type Shape interface {
+	Area() float64
+}
+
+type Square struct{ Side float64 }
+
+func (s Square) Area() float64 { return s.Side * s.Side }
+
+type Circle struct{ R float64 }
+
+func (c *Circle) Area() float64 { return 3 * c.R * c.R }
+
+func (c *Circle) String() string { return fmt.Sprint(c.R) }
+
+type Label string
+`,
+	"render/render.go": `package render
+
+type Areaer interface {
+	Area() float64
+}
+`,
+}
+
+func TestFindImplementations_Interface(t *testing.T) {
+	dir := writeTypedTestModule(t, implementationsTestFiles)
+
+	result, err := FindImplementations(context.Background(), dir, "shape.Shape", false)
+	require.NoError(t, err)
+
+	assert.True(t, result.IsInterface)
+	require.Len(t, result.Implementations, 2)
+	assert.Equal(t, "example.com/sample/shape.Circle", result.Implementations[0].Type)
+	assert.Equal(t, "pointer", result.Implementations[0].Receiver)
+	assert.Equal(t, "example.com/sample/shape.Square", result.Implementations[1].Type)
+	assert.Equal(t, "value", result.Implementations[1].Receiver)
+
+	output := FormatImplementations(result, dir)
+	assert.Contains(t, output, "Implementations of interface example.com/sample/shape.Shape\n")
+	assert.Contains(t, output, "Total: 2 types\n")
+	assert.Contains(t, output, "  Circle (struct, pointer receiver) shape/shape.go:13:6\n")
+}
+
+func TestFindImplementations_Type(t *testing.T) {
+	dir := writeTypedTestModule(t, implementationsTestFiles)
+
+	result, err := FindImplementations(context.Background(), dir, "shape.Circle", false)
+	require.NoError(t, err)
+
+	assert.False(t, result.IsInterface)
+	var names []string
+	for _, impl := range result.Implementations {
+		names = append(names, impl.Type)
+	}
+	assert.Equal(
+		t,
+		[]string{"example.com/sample/render.Areaer", "example.com/sample/shape.Shape"},
+		names,
+	)
+
+	// fmt.Stringer is only found when dependencies are included
+	result, err = FindImplementations(context.Background(), dir, "shape.Circle", true)
+	require.NoError(t, err)
+	names = nil
+	for _, impl := range result.Implementations {
+		names = append(names, impl.Type)
+	}
+	assert.Contains(t, names, "fmt.Stringer")
+}
+
+func TestFindImplementations_NotAType(t *testing.T) {
+	dir := writeTypedTestModule(t, implementationsTestFiles)
+
+	_, err := FindImplementations(context.Background(), dir, "shape.Square.Area", false)
+	assert.Error(t, err)
+}
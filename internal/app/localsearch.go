@@ -17,11 +17,24 @@ import (
 const headerSize = 16
 
 func SearchLocalFiles(
-	ctx context.Context, fw repository.FileWalker, path, extension, query string, maxMatches int,
+	ctx context.Context,
+	fw repository.FileWalker,
+	path string,
+	opts repository.WalkFileOptions,
+	query contentsearch.Query,
+	maxMatches, before, after int,
 ) ([]model.SearchResult, error) {
+	matcher, err := contentsearch.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Dot files and directories are never searched
+	opts.IgnoreDot = true
+
 	var results []model.SearchResult
-	err := fw.Walk(ctx, func(filePath string) error {
-		matches, truncated, err := searchInFile(filePath, query, maxMatches)
+	err = fw.Walk(ctx, func(filePath string) error {
+		matches, truncated, err := searchInFile(filePath, matcher, maxMatches, before, after)
 		if err != nil {
 			return errors.Wrap(err, "failed to search in file")
 		}
@@ -34,7 +47,7 @@ func SearchLocalFiles(
 		}
 
 		return nil
-	}, path, extension, true)
+	}, path, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +55,11 @@ func SearchLocalFiles(
 	return results, nil
 }
 
-func searchInFile(filename, query string, maxMatches int) ([]model.SearchMatch, bool, error) {
+func searchInFile(
+	filename string,
+	matcher *contentsearch.Matcher,
+	maxMatches, before, after int,
+) ([]model.SearchMatch, bool, error) {
 	fp, err := os.Open(filename)
 	if err != nil {
 		return nil, false, err
@@ -54,7 +71,8 @@ func searchInFile(filename, query string, maxMatches int) ([]model.SearchMatch,
 		return nil, false, nil
 	}
 
-	matches, truncated, err := contentsearch.SearchInContent(reader, query, maxMatches)
+	matches, truncated, err := contentsearch.SearchInContent(
+		reader, matcher, maxMatches, before, after)
 	if err != nil {
 		return nil, false, err
 	}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/fpt/go-dev-mcp/internal/contentsearch"
 	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/fpt/go-dev-mcp/internal/repository"
 )
 
 func TestSearchLocalFiles(t *testing.T) {
@@ -33,7 +36,16 @@ func TestSearchLocalFiles(t *testing.T) {
 
 	fw := infra.NewFileWalker()
 	query := "test"
-	results, err := SearchLocalFiles(context.Background(), fw, tempDir, ".txt", query, 10)
+	results, err := SearchLocalFiles(
+		context.Background(),
+		fw,
+		tempDir,
+		repository.WalkFileOptions{Extensions: []string{".txt"}},
+		contentsearch.Query{Terms: []string{query}},
+		10,
+		0,
+		0,
+	)
 	if err != nil {
 		t.Fatalf("Error searching local files: %v", err)
 	}
@@ -76,7 +88,11 @@ func TestSearchLocalFilesMatchLimit(t *testing.T) {
 	query := "test"
 	maxMatches := 5
 
-	results, err := SearchLocalFiles(context.Background(), fw, tempDir, ".txt", query, maxMatches)
+	results, err := SearchLocalFiles(
+		context.Background(), fw, tempDir, repository.WalkFileOptions{Extensions: []string{".txt"}},
+		contentsearch.Query{Terms: []string{query}},
+		maxMatches, 0, 0,
+	)
 	if err != nil {
 		t.Fatalf("Error searching local files: %v", err)
 	}
@@ -95,7 +111,16 @@ func TestSearchLocalFilesMatchLimit(t *testing.T) {
 	}
 
 	// Test with no limit (0 should use default of 10)
-	results2, err := SearchLocalFiles(context.Background(), fw, tempDir, ".txt", query, 15)
+	results2, err := SearchLocalFiles(
+		context.Background(),
+		fw,
+		tempDir,
+		repository.WalkFileOptions{Extensions: []string{".txt"}},
+		contentsearch.Query{Terms: []string{query}},
+		15,
+		0,
+		0,
+	)
 	if err != nil {
 		t.Fatalf("Error searching local files: %v", err)
 	}
@@ -109,3 +134,80 @@ func TestSearchLocalFilesMatchLimit(t *testing.T) {
 		t.Error("Expected result to not be truncated when limit is higher than total matches")
 	}
 }
+
+func TestSearchLocalFiles_Modes(t *testing.T) {
+	tempDir := t.TempDir()
+	content := "func (w *Walker) Walk(ctx context.Context) error\n" +
+		"// walk the tree\n" +
+		"func walkDir(path string) error\n"
+	if err := os.WriteFile(filepath.Join(tempDir, "walk.go"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		query contentsearch.Query
+		lines []int
+	}{
+		{"literal", contentsearch.Query{Terms: []string{"walk"}}, []int{2, 3}},
+		{
+			"ignore case",
+			contentsearch.Query{Terms: []string{"walk"}, IgnoreCase: true},
+			[]int{1, 2, 3},
+		},
+		{
+			"regex",
+			contentsearch.Query{Terms: []string{`func .*Walk\(`}, Mode: contentsearch.ModeRegex},
+			[]int{1},
+		},
+		{
+			"word",
+			contentsearch.Query{Terms: []string{"walk"}, Mode: contentsearch.ModeWord},
+			[]int{2},
+		},
+		{
+			"or",
+			contentsearch.Query{
+				Terms:    []string{"Walker", "walkDir"},
+				Operator: contentsearch.OperatorOr,
+			},
+			[]int{1, 3},
+		},
+		{"and", contentsearch.Query{Terms: []string{"func", "error", "path"}}, []int{3}},
+	}
+
+	fw := infra.NewFileWalker()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := SearchLocalFiles(
+				context.Background(),
+				fw,
+				tempDir,
+				repository.WalkFileOptions{Extensions: []string{".go"}},
+				tt.query,
+				10,
+				0,
+				0,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var lines []int
+			for _, result := range results {
+				for _, match := range result.Matches {
+					lines = append(lines, match.LineNo)
+				}
+			}
+			if !slices.Equal(lines, tt.lines) {
+				t.Errorf("got lines %v, want %v", lines, tt.lines)
+			}
+		})
+	}
+
+	_, err := SearchLocalFiles(context.Background(), fw, tempDir,
+		repository.WalkFileOptions{Extensions: []string{".go"}},
+		contentsearch.Query{Terms: []string{"Walk("}, Mode: contentsearch.ModeRegex}, 10, 0, 0)
+	if err == nil {
+		t.Error("expected error for an invalid pattern")
+	}
+}
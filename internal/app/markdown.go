@@ -30,6 +30,7 @@ func ScanMarkdownFiles(
 	ctx context.Context,
 	fw repository.FileWalker,
 	path string,
+	opts repository.WalkFileOptions,
 ) ([]MarkdownFile, error) {
 	var results []MarkdownFile
 
@@ -41,6 +42,8 @@ func ScanMarkdownFiles(
 
 	if info.IsDir() {
 		// Directory case: use existing directory walking logic
+		opts.Extensions = []string{".md"}
+		opts.IgnoreDot = true
 		err := fw.Walk(ctx, func(filePath string) error {
 			headings, err := extractMarkdownHeadings(filePath)
 			if err != nil {
@@ -56,7 +59,7 @@ func ScanMarkdownFiles(
 			}
 
 			return nil
-		}, path, ".md", true)
+		}, path, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -0,0 +1,207 @@
+package app
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// RemoteGoPackage is a Go package of a GitHub repository or of a module version.
+type RemoteGoPackage struct {
+	Module  string // Module path, if the package is fetched from the Go module proxy
+	Version string // Module version, or "" for the latest
+
+	Host  string // GitHub Enterprise host, or "" for github.com
+	Owner string // Owner of the GitHub repository, if the package is fetched from GitHub
+	Repo  string
+	Ref   string // Branch, tag or commit SHA, or "" for the default branch
+
+	Path string // Directory of the package in the module or repository, "" for the root
+}
+
+// ParseRemoteGoPackage parses a package as "[host/]owner/repo[@ref][/path]"
+// (GitHub) or "module[@version][/path]" (Go module proxy). Module paths start
+// with a domain name, e.g. "golang.org/x/mod@v0.20.0/semver"; GitHub owners
+// never contain dots. A host is only taken for a GitHub host if it is one of
+// enterpriseHosts, so that "github.com/..." paths remain module paths. Without
+// a version, the module is the longest prefix of the path the proxy knows,
+// resolved by FetchRemoteGoPackage.
+func ParseRemoteGoPackage(arg string, enterpriseHosts []string) (RemoteGoPackage, error) {
+	arg = strings.Trim(arg, "/")
+	first, rest, _ := strings.Cut(arg, "/")
+	if slices.Contains(enterpriseHosts, first) {
+		pkg, err := parseGitHubPackage(rest)
+		pkg.Host = first
+		return pkg, err
+	}
+	if strings.Contains(first, ".") {
+		before, after, ok := strings.Cut(arg, "@")
+		if !ok {
+			return RemoteGoPackage{Module: arg}, nil
+		}
+		version, pkgPath, _ := strings.Cut(after, "/")
+		if before == "" || version == "" {
+			return RemoteGoPackage{}, errors.Errorf("invalid module %q", arg)
+		}
+		return RemoteGoPackage{Module: before, Version: version, Path: pkgPath}, nil
+	}
+	return parseGitHubPackage(arg)
+}
+
+// parseGitHubPackage parses a package as "owner/repo[@ref][/path]".
+func parseGitHubPackage(arg string) (RemoteGoPackage, error) {
+	parts := strings.SplitN(arg, "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return RemoteGoPackage{}, errors.Errorf(
+			"invalid package %q, expected '[host/]owner/repo[@ref][/path]' or 'module@version[/path]'",
+			arg,
+		)
+	}
+	pkg := RemoteGoPackage{Owner: parts[0], Repo: parts[1]}
+	if repo, ref, ok := strings.Cut(parts[1], "@"); ok {
+		if repo == "" || ref == "" {
+			return RemoteGoPackage{}, errors.Errorf("invalid repository %q", parts[1])
+		}
+		pkg.Repo, pkg.Ref = repo, ref
+	}
+	if len(parts) == 3 {
+		pkg.Path = parts[2]
+	}
+	return pkg, nil
+}
+
+// FetchRemoteGoPackage resolves the version of pkg and fetches the sources of
+// its module or repository into cache. It returns a walker of the sources,
+// whose paths start with a label naming them, e.g. "golang.org/x/mod@v0.20.0",
+// and the path of the package directory, e.g. "golang.org/x/mod@v0.20.0/semver".
+func FetchRemoteGoPackage(
+	ctx context.Context,
+	forges *infra.Forges,
+	proxy *infra.GoModuleProxy,
+	cache *infra.SourceCache,
+	pkg RemoteGoPackage,
+) (fw repository.FileWalker, dir string, err error) {
+	var fsys fs.FS
+	var label string
+	if pkg.Module != "" {
+		version := ""
+		if pkg.Version == "" {
+			pkg, err = resolveLatestModule(ctx, proxy, pkg)
+			version = pkg.Version
+		} else {
+			version, err = proxy.Resolve(ctx, pkg.Module, pkg.Version)
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		label = pkg.Module + "@" + version
+		zr, err := cache.Get("mod:"+label, func() ([]byte, error) {
+			return proxy.DownloadZip(ctx, pkg.Module, version)
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		// Files of module zips are prefixed with "module@version/"
+		if fsys, err = fs.Sub(zr, label); err != nil {
+			return nil, "", errors.Wrap(err, "failed to read module zip")
+		}
+	} else {
+		gh, err := forges.Client(pkg.Host)
+		if err != nil {
+			return nil, "", err
+		}
+		commit, err := gh.ResolveRef(ctx, pkg.Owner, pkg.Repo, pkg.Ref)
+		if err != nil {
+			return nil, "", errors.Wrap(err, "failed to resolve ref")
+		}
+		label = path.Join(pkg.Host, pkg.Owner, pkg.Repo) + "@" + commit[:min(12, len(commit))]
+		fsys, err = cache.Get(
+			fmt.Sprintf("github:%s/%s/%s/%s", gh.Host(), pkg.Owner, pkg.Repo, commit),
+			func() ([]byte, error) {
+				return gh.DownloadTarball(ctx, pkg.Owner, pkg.Repo, commit)
+			},
+		)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	pkgPath := cmp.Or(pkg.Path, ".")
+	if !fs.ValidPath(pkgPath) {
+		return nil, "", errors.Errorf("invalid package path %q", pkg.Path)
+	}
+	if info, err := fs.Stat(fsys, pkgPath); err != nil || !info.IsDir() {
+		return nil, "", errors.Errorf("package path %q not found in %s", pkg.Path, label)
+	}
+	return infra.NewFSFileWalker(fsys, label), path.Join(label, pkg.Path), nil
+}
+
+// resolveLatestModule splits a path without a version into the module, the
+// longest prefix known to the proxy, and the package path within it.
+func resolveLatestModule(
+	ctx context.Context,
+	proxy *infra.GoModuleProxy,
+	pkg RemoteGoPackage,
+) (RemoteGoPackage, error) {
+	modulePath := pkg.Module
+	var lastErr error
+	for {
+		version, err := proxy.Resolve(ctx, modulePath, "")
+		if err == nil {
+			pkgPath := strings.TrimPrefix(strings.TrimPrefix(pkg.Module, modulePath), "/")
+			return RemoteGoPackage{Module: modulePath, Version: version, Path: pkgPath}, nil
+		}
+		if !errors.Is(err, infra.ErrNotFound) {
+			return RemoteGoPackage{}, err
+		}
+		lastErr = err
+		i := strings.LastIndex(modulePath, "/")
+		if i < 0 {
+			return RemoteGoPackage{}, errors.Wrapf(lastErr, "no module found for %s", pkg.Module)
+		}
+		modulePath = modulePath[:i]
+	}
+}
+
+// OutlineRemoteGoPackage outlines a package of a repository of a configured
+// GitHub host or of a module version like OutlineGoPackage outlines a local directory. The sources are
+// read in memory, and file names are reported below the label of the module or
+// repository. The typed mode is not supported, as it loads packages from disk.
+func OutlineRemoteGoPackage(
+	ctx context.Context,
+	forges *infra.Forges,
+	proxy *infra.GoModuleProxy,
+	cache *infra.SourceCache,
+	arg string,
+	opts OutlineGoPackageOptions,
+) (string, error) {
+	if opts.Mode == OutlineModeTyped && !opts.SkipCallGraph {
+		return "", errors.New(
+			"the typed mode needs the package on disk, outline a local checkout instead")
+	}
+	var enterpriseHosts []string
+	if forges != nil {
+		for _, host := range forges.GitHubClients.Hosts() {
+			if host != infra.DefaultGitHubHost {
+				enterpriseHosts = append(enterpriseHosts, host)
+			}
+		}
+	}
+	pkg, err := ParseRemoteGoPackage(arg, enterpriseHosts)
+	if err != nil {
+		return "", err
+	}
+	fw, dir, err := FetchRemoteGoPackage(ctx, forges, proxy, cache, pkg)
+	if err != nil {
+		return "", err
+	}
+	return OutlineGoPackage(ctx, fw, dir, opts)
+}
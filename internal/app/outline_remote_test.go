@@ -0,0 +1,217 @@
+package app
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/fpt/go-dev-mcp/internal/infra"
+)
+
+func TestParseRemoteGoPackage(t *testing.T) {
+	tests := []struct {
+		arg         string
+		want        RemoteGoPackage
+		expectError bool
+	}{
+		{arg: "fpt/go-dev-mcp", want: RemoteGoPackage{Owner: "fpt", Repo: "go-dev-mcp"}},
+		{
+			arg: "fpt/go-dev-mcp@v1.2.0/internal/app",
+			want: RemoteGoPackage{
+				Owner: "fpt",
+				Repo:  "go-dev-mcp",
+				Ref:   "v1.2.0",
+				Path:  "internal/app",
+			},
+		},
+		{
+			arg:  "golang.org/x/mod@v0.20.0/semver",
+			want: RemoteGoPackage{Module: "golang.org/x/mod", Version: "v0.20.0", Path: "semver"},
+		},
+		{arg: "golang.org/x/mod/semver", want: RemoteGoPackage{Module: "golang.org/x/mod/semver"}},
+		{
+			arg: "ghe.example.com/team/lib@v1.0.0/pkg",
+			want: RemoteGoPackage{
+				Host:  "ghe.example.com",
+				Owner: "team",
+				Repo:  "lib",
+				Ref:   "v1.0.0",
+				Path:  "pkg",
+			},
+		},
+		{
+			arg:  "github.com/fpt/go-dev-mcp@v1.2.0",
+			want: RemoteGoPackage{Module: "github.com/fpt/go-dev-mcp", Version: "v1.2.0"},
+		},
+		{arg: "ghe.example.com/team", expectError: true},
+		{arg: "fpt", expectError: true},
+		{arg: "fpt/@main", expectError: true},
+		{arg: "golang.org/x/mod@", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			got, err := ParseRemoteGoPackage(tt.arg, []string{"ghe.example.com"})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOutlineRemoteGoPackage_ModuleProxy(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range map[string]string{
+		"go.mod": "module example.com/lib\n",
+		"semver/semver.go": "package semver\n\nimport \"strings\"\n\n" +
+			"// Compare compares versions.\nfunc Compare(v, w string) int {\n" +
+			"\treturn strings.Compare(v, w)\n}\n",
+	} {
+		w, err := zw.Create("example.com/lib@v1.0.0/" + name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	downloads := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/example.com/lib/@latest", "/example.com/lib/@v/v1.0.0.info":
+			w.Write([]byte(`{"Version":"v1.0.0"}`))
+		case "/example.com/lib/@v/v1.0.0.zip":
+			downloads++
+			w.Write(buf.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	cache := infra.NewSourceCache(infra.DefaultSourceCacheSize)
+	proxy := infra.NewGoModuleProxyWithURL(srv.URL)
+
+	for range 2 {
+		output, err := OutlineRemoteGoPackage(
+			context.Background(), nil, proxy, cache, "example.com/lib/semver",
+			OutlineGoPackageOptions{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []string{
+			"Package outline for: example.com/lib@v1.0.0/semver\n",
+			"File: example.com/lib@v1.0.0/semver/semver.go\n",
+			"- function: Compare",
+			"Stdlib: strings",
+		} {
+			if !strings.Contains(output, want) {
+				t.Errorf("output does not contain %q:\n%s", want, output)
+			}
+		}
+	}
+	if downloads != 1 {
+		t.Errorf("module downloaded %d times, want once", downloads)
+	}
+
+	_, err := OutlineRemoteGoPackage(
+		context.Background(), nil, proxy, cache, "example.com/lib@v1.0.0/missing",
+		OutlineGoPackageOptions{},
+	)
+	if err == nil || !strings.Contains(err.Error(), "not found in example.com/lib@v1.0.0") {
+		t.Errorf("expected error for a missing package path, got %v", err)
+	}
+
+	_, err = OutlineRemoteGoPackage(
+		context.Background(), nil, proxy, cache, "example.com/lib@v1.0.0/semver",
+		OutlineGoPackageOptions{Mode: OutlineModeTyped},
+	)
+	if err == nil {
+		t.Error("expected error for the typed mode")
+	}
+}
+
+func TestOutlineRemoteGoPackage_GitHubEnterprise(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	content := "package pkg\n\n// Run runs.\nfunc Run() {}\n"
+	if err := tw.WriteHeader(&tar.Header{
+		Name: "team-lib-0123456/pkg/pkg.go", Mode: 0o644, Size: int64(len(content)),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	const commit = "0123456789abcdef0123456789abcdef01234567"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/team/lib/commits/v1.0.0":
+			w.Write([]byte(commit))
+		case "/api/v3/repos/team/lib/tarball/" + commit:
+			http.Redirect(w, r, "http://"+r.Host+"/download", http.StatusFound)
+		case "/download":
+			w.Write(buf.Bytes())
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	forges, err := infra.NewForges(context.Background(), infra.ForgeConfig{
+		GitHub: infra.GitHubConfig{Hosts: []infra.GitHubHost{
+			{Host: "ghe.example.com", APIURL: srv.URL, Token: "x"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output, err := OutlineRemoteGoPackage(
+		context.Background(), forges, nil, infra.NewSourceCache(infra.DefaultSourceCacheSize),
+		"ghe.example.com/team/lib@v1.0.0/pkg", OutlineGoPackageOptions{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"Package outline for: ghe.example.com/team/lib@0123456789ab/pkg\n",
+		"File: ghe.example.com/team/lib@0123456789ab/pkg/pkg.go\n",
+		"- function: Run",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output does not contain %q:\n%s", want, output)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package app
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// typedLoadMode is the go/packages mode used by the type-aware analyses.
+// It loads syntax and full type information for the root packages while
+// dependencies are imported from export data.
+const typedLoadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedCompiledGoFiles |
+	packages.NeedImports |
+	packages.NeedSyntax |
+	packages.NeedTypes |
+	packages.NeedTypesInfo |
+	packages.NeedModule
+
+// loadTypedPackages loads the packages matching patterns, resolved from dir,
+// with full type information. Packages with type errors are still returned
+// so that callers can work with partial information.
+func loadTypedPackages(
+	ctx context.Context, dir string, tests bool, patterns ...string,
+) ([]*packages.Package, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	cfg := &packages.Config{
+		Context: ctx,
+		Mode:    typedLoadMode,
+		Dir:     dir,
+		Tests:   tests,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load packages")
+	}
+	if len(pkgs) == 0 {
+		return nil, errors.Errorf("no Go packages found in %s", dir)
+	}
+
+	return pkgs, nil
+}
@@ -1,6 +1,7 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"strconv"
 	"strings"
@@ -9,7 +10,6 @@ import (
 	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/fpt/go-dev-mcp/internal/model"
 	"github.com/fpt/go-dev-mcp/pkg/dq"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"golang.org/x/net/html"
 )
@@ -20,36 +20,23 @@ type pyModEntry struct {
 	URL         string
 }
 
-func SearchPyDoc(httpcli *infra.HttpClient, query string) (string, error) {
-	cacheKey := "pydoc:modindex"
-
-	var entries []pyModEntry
-
-	if cached, found := docCache.Get(cacheKey); found {
-		entries = cached.([]pyModEntry)
-	} else {
-		u := "https://docs.python.org/3/py-modindex.html"
-		bodyrdr, err := httpcli.HttpGet(u)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return "", ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to parse HTML")
-		}
-
-		matched, parsed := parsePyModIndex(doc)
-		if !matched {
-			return "", errors.New("no module index found")
-		}
-
-		entries = parsed
-		docCache.Set(cacheKey, entries, cache.DefaultExpiration)
+func SearchPyDoc(ctx context.Context, httpcli *infra.HttpClient, query string) (string, error) {
+	u := docSites.PyDoc + "/py-modindex.html"
+	entries, err := fetchCachedDoc(
+		ctx,
+		httpcli,
+		"pydoc:modindex",
+		u,
+		func(doc *html.Node) ([]pyModEntry, error) {
+			matched, entries := parsePyModIndex(doc)
+			if !matched {
+				return nil, errors.New("no module index found")
+			}
+			return entries, nil
+		},
+	)
+	if err != nil {
+		return "", err
 	}
 
 	// Filter entries by query (case-insensitive)
@@ -76,55 +63,47 @@ func SearchPyDoc(httpcli *infra.HttpClient, query string) (string, error) {
 	return fmt.Sprintf("Found %d matching Python modules:\n%s", count, builder.String()), nil
 }
 
+// ReadPyDocPaged reads the documentation of a Python module with line-based paging.
+// If symbol is set (e.g. "json.dumps", "dumps" or "JSONDecoder.decode"), only the
+// documentation of that object is returned.
 func ReadPyDocPaged(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
-	moduleName string,
+	moduleName, symbol string,
 	offset, limit int,
 ) (string, int, bool, error) {
-	cacheKey := fmt.Sprintf("pydoc:%s", moduleName)
-
-	var document string
-
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		u := buildPyDocURL(moduleName)
-		bodyrdr, err := httpcli.HttpGet(u)
-		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return "", 0, false, ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to parse HTML")
-		}
-
-		_, document = parsePyDocPage(doc)
-
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
-	}
-
-	lines := strings.Split(document, "\n")
-	totalLines := len(lines)
-
-	startIdx := offset
-	if startIdx >= totalLines {
-		return "", totalLines, false, nil
+	document, err := fetchPyDoc(ctx, httpcli, moduleName)
+	if err != nil {
+		return "", 0, false, err
 	}
 
-	endIdx := startIdx + limit
-	hasMore := endIdx < totalLines
-	if endIdx > totalLines {
-		endIdx = totalLines
+	text := document.Text
+	if symbol != "" {
+		section, ok := document.section(symbol, moduleName+"."+symbol)
+		if !ok {
+			return "", 0, false, errors.Wrapf(ErrNotFound,
+				"symbol %s not found in %s", symbol, moduleName)
+		}
+		text = section
 	}
 
-	pagedContent := strings.Join(lines[startIdx:endIdx], "\n")
+	content, totalLines, hasMore := pageLines(text, offset, limit)
+	return content, totalLines, hasMore, nil
+}
 
-	return pagedContent, totalLines, hasMore, nil
+// fetchPyDoc fetches and parses the docs.python.org page of moduleName.
+// Parsed documents are cached.
+func fetchPyDoc(
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	moduleName string,
+) (*parsedDocument, error) {
+	cacheKey := fmt.Sprintf("pydoc:%s", moduleName)
+	u := buildPyDocURL(moduleName)
+	return fetchCachedDoc(ctx, httpcli, cacheKey, u, func(doc *html.Node) (*parsedDocument, error) {
+		_, document := parsePyDocPage(doc)
+		return document, nil
+	})
 }
 
 type PyDocSearchResult struct {
@@ -134,40 +113,25 @@ type PyDocSearchResult struct {
 }
 
 func SearchWithinPyDoc(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
 	moduleName string,
-	keyword string,
-	maxMatches int,
+	query contentsearch.Query,
+	maxMatches, before, after int,
 ) (*PyDocSearchResult, error) {
-	cacheKey := fmt.Sprintf("pydoc:%s", moduleName)
-
-	var document string
-
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		u := buildPyDocURL(moduleName)
-		bodyrdr, err := httpcli.HttpGet(u)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return nil, ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse HTML")
-		}
-
-		_, document = parsePyDocPage(doc)
+	matcher, err := contentsearch.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
 
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
+	document, err := fetchPyDoc(ctx, httpcli, moduleName)
+	if err != nil {
+		return nil, err
 	}
 
-	reader := strings.NewReader(document)
-	matches, truncated, err := contentsearch.SearchInContent(reader, keyword, maxMatches)
+	reader := strings.NewReader(document.Text)
+	matches, truncated, err := contentsearch.SearchInContent(
+		reader, matcher, maxMatches, before, after)
 	if err != nil {
 		return nil, err
 	}
@@ -179,11 +143,12 @@ func SearchWithinPyDoc(
 	}, nil
 }
 
-// buildPyDocURL constructs the docs.python.org URL from a module name.
+// buildPyDocURL constructs the docs.python.org URL from a module name
+// (on the configured site, see ConfigureDocSites).
 // "json" → "https://docs.python.org/3/library/json.html"
 // "os.path" → "https://docs.python.org/3/library/os.path.html"
 func buildPyDocURL(moduleName string) string {
-	return fmt.Sprintf("https://docs.python.org/3/library/%s.html", moduleName)
+	return fmt.Sprintf("%s/library/%s.html", docSites.PyDoc, moduleName)
 }
 
 // cleanPyDocHeading strips the ¶ anchor symbol from Python docs headings.
@@ -256,19 +221,30 @@ func extractModuleFromRow(tr *html.Node) *pyModEntry {
 	return &pyModEntry{
 		Name:        moduleName,
 		Description: description,
-		URL:         fmt.Sprintf("https://docs.python.org/3/%s", moduleHref),
+		URL:         fmt.Sprintf("%s/%s", docSites.PyDoc, moduleHref),
 	}
 }
 
+// parsePyDocPage parses a module page of docs.python.org. Headings are indexed
+// by the id of their section (e.g. "basic-usage") and objects by their
+// qualified name (e.g. "json.dumps", "json.JSONDecoder.decode").
+//
 //nolint:funlen // Parser function with multiple matchers
-func parsePyDocPage(doc *html.Node) (bool, string) {
-	builder := strings.Builder{}
+func parsePyDocPage(doc *html.Node) (bool, *parsedDocument) {
+	builder := &docBuilder{}
+	sectionID := func(n *html.Node) string {
+		if n.Parent != nil && n.Parent.Data == "section" {
+			return dq.GetAttr(n.Parent, "id")
+		}
+		return ""
+	}
 
 	// h1 matcher for page title
 	h1Matcher := dq.NewNodeMatcher(
 		dq.NewMatchFunc("h1"),
 		func(n *html.Node) {
 			text := cleanPyDocHeading(dq.InnerText(n, true))
+			builder.startSection(sectionID(n), 1)
 			builder.WriteString(fmt.Sprintf("# %s\n\n", text))
 		},
 	)
@@ -280,9 +256,9 @@ func parsePyDocPage(doc *html.Node) (bool, string) {
 			text := cleanPyDocHeading(dq.InnerText(n, true))
 			h := strings.TrimPrefix(n.Data, "h")
 			hn, _ := strconv.Atoi(h)
-			builder.WriteString(
-				fmt.Sprintf("\n%s %s\n", strings.Repeat("#", hn), text),
-			)
+			builder.WriteString("\n")
+			builder.startSection(sectionID(n), hn)
+			builder.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat("#", hn), text))
 		},
 	)
 
@@ -308,12 +284,19 @@ func parsePyDocPage(doc *html.Node) (bool, string) {
 		},
 	)
 
-	// Signature matcher (dt.sig-object inside dl.py)
+	// Signature matcher (dt.sig-object inside dl.py). Members of a class are
+	// one level deeper so that the section of a class includes its members.
 	dtMatcher := dq.NewNodeMatcher(
 		dq.NewMatchFunc("dt.sig-object"),
 		func(n *html.Node) {
+			level := 5
+			if dl := n.Parent; dl != nil && dl.Parent != nil && dl.Parent.Data == "dd" {
+				level = 6
+			}
 			sig := cleanPyDocSignature(dq.RawInnerText(n, true))
-			builder.WriteString(fmt.Sprintf("\n%s\n", sig))
+			builder.WriteString("\n")
+			builder.startSection(dq.GetAttr(n, "id"), level)
+			builder.WriteString(fmt.Sprintf("%s\n", sig))
 		},
 	)
 
@@ -373,5 +356,5 @@ func parsePyDocPage(doc *html.Node) (bool, string) {
 	)
 
 	dq.Traverse(doc, []dq.Matcher{rootMatcher})
-	return matched, builder.String()
+	return matched, builder.document()
 }
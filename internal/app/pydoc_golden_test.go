@@ -1,6 +1,7 @@
 package app
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,12 +34,12 @@ func TestGolden_parsePyDocPage(t *testing.T) {
 
 			goldenPath := "testdata/" + tt.golden
 			if *update {
-				writeGolden(t, goldenPath, got)
+				writeGolden(t, goldenPath, got.Text)
 				return
 			}
 
 			want := readGolden(t, goldenPath)
-			assert.Equal(t, want, got, "output does not match golden file %s", tt.golden)
+			assert.Equal(t, want, got.Text, "output does not match golden file %s", tt.golden)
 		})
 	}
 }
@@ -73,3 +74,21 @@ func formatPyModEntries(entries []pyModEntry) string {
 	}
 	return result
 }
+
+func TestParsePyDocPage_Sections(t *testing.T) {
+	_, got := parsePyDocPage(loadHTMLFixture(t, "pydoc_doc_json.html"))
+
+	section, ok := got.section("json.dumps")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(section, "json.dumps("), section)
+	assert.NotContains(t, section, "json.load(")
+
+	section, ok = got.section("json.JSONDecoder")
+	require.True(t, ok)
+	assert.Contains(t, section, "decode(")
+	assert.NotContains(t, section, "class json.JSONEncoder")
+
+	section, ok = got.section("basic-usage")
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(section, "## Basic Usage"), section)
+}
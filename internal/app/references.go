@@ -0,0 +1,234 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Reference is a single use of a Go identifier.
+type Reference struct {
+	Package  string // Import path of the package containing the reference
+	Filename string // Absolute path of the file
+	Line     int
+	Column   int
+	Function string // Enclosing function, empty at package scope
+	Context  string // Source line containing the reference
+}
+
+// ReferencesResult holds all references to a resolved symbol.
+type ReferencesResult struct {
+	Symbol     string      // Qualified name of the symbol
+	Kind       string      // Object kind (e.g. "func", "type", "var")
+	Definition string      // Declaring position, empty if unknown
+	References []Reference // Sorted by package, file and position
+}
+
+// FindReferences type-checks the module containing directory (including tests)
+// and returns every reference to the symbol denoted by query.
+// query is either "file:line:column" or a qualified name such as
+// "internal/repository.FileWalker.Walk".
+func FindReferences(ctx context.Context, directory, query string) (*ReferencesResult, error) {
+	pkgs, err := loadTypedPackages(ctx, directory, true)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := resolveSymbol(pkgs, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to resolve symbol")
+	}
+
+	key := objectKey(target.Fset, target.Object)
+	result := &ReferencesResult{
+		Symbol: qualifiedObjectName(target.Object),
+		Kind:   objectKind(target.Object),
+	}
+	if target.Object.Pos().IsValid() {
+		result.Definition = target.Fset.Position(target.Object.Pos()).String()
+	}
+
+	seen := make(map[string]bool)
+	lines := newSourceLineCache()
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil || strings.HasSuffix(pkg.PkgPath, ".test") {
+			continue
+		}
+		files := make(map[string]*ast.File, len(pkg.Syntax))
+		for _, file := range pkg.Syntax {
+			files[pkg.Fset.Position(file.Pos()).Filename] = file
+		}
+
+		for id, obj := range pkg.TypesInfo.Uses {
+			if objectKey(pkg.Fset, obj) != key {
+				continue
+			}
+
+			pos := pkg.Fset.Position(id.Pos())
+			if seen[pos.String()] {
+				continue // same file compiled into a test variant
+			}
+			seen[pos.String()] = true
+
+			function := ""
+			if file := files[pos.Filename]; file != nil {
+				function = enclosingFunction(file, id.Pos())
+			}
+			result.References = append(result.References, Reference{
+				Package:  strings.TrimSuffix(pkg.PkgPath, "_test"),
+				Filename: pos.Filename,
+				Line:     pos.Line,
+				Column:   pos.Column,
+				Function: function,
+				Context:  lines.line(pos.Filename, pos.Line),
+			})
+		}
+	}
+
+	sort.Slice(result.References, func(i, j int) bool {
+		a, b := result.References[i], result.References[j]
+		if a.Package != b.Package {
+			return a.Package < b.Package
+		}
+		if a.Filename != b.Filename {
+			return a.Filename < b.Filename
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return result, nil
+}
+
+// FormatReferences renders references grouped by package.
+// File paths are shown relative to baseDir when possible.
+// At most maxRefs references are listed (0 means no limit).
+func FormatReferences(result *ReferencesResult, baseDir string, maxRefs int) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("References to %s %s\n", result.Kind, result.Symbol))
+	if result.Definition != "" {
+		sb.WriteString(fmt.Sprintf("Defined at: %s\n", relativePath(baseDir, result.Definition)))
+	}
+
+	if len(result.References) == 0 {
+		sb.WriteString("No references found.\n")
+		return sb.String()
+	}
+
+	pkgCount := 0
+	for i, ref := range result.References {
+		if i == 0 || ref.Package != result.References[i-1].Package {
+			pkgCount++
+		}
+	}
+	sb.WriteString(fmt.Sprintf("Total: %s in %s\n",
+		pluralize(len(result.References), "reference"), pluralize(pkgCount, "package")))
+
+	for i, ref := range result.References {
+		if maxRefs > 0 && i >= maxRefs {
+			sb.WriteString(fmt.Sprintf("... (%d more references truncated)\n",
+				len(result.References)-maxRefs))
+			break
+		}
+		if i == 0 || ref.Package != result.References[i-1].Package {
+			sb.WriteString(fmt.Sprintf("\nPackage %s\n", ref.Package))
+		}
+
+		location := fmt.Sprintf(
+			"%s:%d:%d",
+			relativePath(baseDir, ref.Filename),
+			ref.Line,
+			ref.Column,
+		)
+		if ref.Function != "" {
+			sb.WriteString(fmt.Sprintf("  %s in %s\n", location, ref.Function))
+		} else {
+			sb.WriteString(fmt.Sprintf("  %s\n", location))
+		}
+		sb.WriteString(fmt.Sprintf("    %s\n", strings.TrimSpace(ref.Context)))
+	}
+
+	return sb.String()
+}
+
+// pluralize formats a count with a singular or plural noun (e.g. "1 package", "2 packages").
+func pluralize(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// relativePath returns path relative to baseDir, or path itself if that fails.
+func relativePath(baseDir, path string) string {
+	if baseDir == "" {
+		return path
+	}
+	absBase, err := filepath.Abs(baseDir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(absBase, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
+
+// sourceLineCache reads source files at most once when collecting context lines.
+type sourceLineCache struct {
+	files map[string][]string
+}
+
+func newSourceLineCache() *sourceLineCache {
+	return &sourceLineCache{files: make(map[string][]string)}
+}
+
+func (c *sourceLineCache) line(filename string, line int) string {
+	lines, ok := c.files[filename]
+	if !ok {
+		content, err := os.ReadFile(filename)
+		if err == nil {
+			lines = strings.Split(string(content), "\n")
+		}
+		c.files[filename] = lines
+	}
+	if line < 1 || line > len(lines) {
+		return ""
+	}
+	return lines[line-1]
+}
+
+// objectKind returns a short description of the kind of a types.Object.
+func objectKind(obj types.Object) string {
+	switch o := obj.(type) {
+	case *types.Func:
+		if o.Signature().Recv() != nil {
+			return "method"
+		}
+		return "func"
+	case *types.TypeName:
+		return "type"
+	case *types.Var:
+		if o.IsField() {
+			return "field"
+		}
+		return "var"
+	case *types.Const:
+		return "const"
+	case *types.PkgName:
+		return "package"
+	default:
+		return "object"
+	}
+}
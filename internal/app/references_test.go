@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var referencesTestFiles = map[string]string{
+	"store/store.go": `package store
+
+// Walker walks things.
+type Walker interface {
+	Walk(path string) error
+}
+
+func Open(w Walker) error {
+	return w.Walk("/")
+}
+`,
+	"store/store_test.go": `package store
+
+import "testing"
+
+func TestOpen(t *testing.T) {
+	var w Walker
+	_ = w
+	// Walk is mentioned here but this comment is not a reference
+}
+`,
+	"cmd/main.go": `package main
+
+import "example.com/sample/store"
+
+type walker struct{}
+
+func (walker) Walk(string) error { return nil }
+
+func main() {
+	var w store.Walker = walker{}
+	_ = w.Walk("x")
+	_ = store.Open(w)
+}
+`,
+}
+
+func TestFindReferences_QualifiedName(t *testing.T) {
+	dir := writeTypedTestModule(t, referencesTestFiles)
+
+	result, err := FindReferences(context.Background(), dir, "store.Walker.Walk")
+	require.NoError(t, err)
+
+	assert.Equal(t, "example.com/sample/store.Walker.Walk", result.Symbol)
+	assert.Equal(t, "method", result.Kind)
+	require.Len(t, result.References, 2)
+
+	// Sorted by package path
+	assert.Equal(t, "example.com/sample/cmd", result.References[0].Package)
+	assert.Equal(t, "main", result.References[0].Function)
+	assert.Equal(t, `_ = w.Walk("x")`, strings.TrimSpace(result.References[0].Context))
+	assert.Equal(t, "example.com/sample/store", result.References[1].Package)
+	assert.Equal(t, "Open", result.References[1].Function)
+}
+
+func TestFindReferences_Position(t *testing.T) {
+	dir := writeTypedTestModule(t, referencesTestFiles)
+
+	// "Walker" in "type Walker interface" (line 4, column 6)
+	target := filepath.Join(dir, "store", "store.go") + ":4:6"
+	result, err := FindReferences(context.Background(), dir, target)
+	require.NoError(t, err)
+
+	assert.Equal(t, "type", result.Kind)
+	// store.go (Open), store_test.go (TestOpen, once despite the test variant), cmd/main.go
+	require.Len(t, result.References, 3)
+
+	functions := []string{}
+	for _, ref := range result.References {
+		functions = append(functions, ref.Function)
+	}
+	assert.ElementsMatch(t, []string{"main", "Open", "TestOpen"}, functions)
+
+	output := FormatReferences(result, dir, 0)
+	assert.Contains(t, output, "Total: 3 references in 2 packages")
+	assert.Contains(t, output, "\nPackage example.com/sample/cmd\n")
+	assert.Contains(t, output, filepath.Join("cmd", "main.go")+":10:14 in main")
+}
+
+func TestFindReferences_Unresolved(t *testing.T) {
+	dir := writeTypedTestModule(t, referencesTestFiles)
+
+	_, err := FindReferences(context.Background(), dir, "store.Missing")
+	assert.Error(t, err)
+}
@@ -1,8 +1,10 @@
 package app
 
 import (
+	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"strconv"
 	"strings"
 
@@ -10,23 +12,20 @@ import (
 	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/fpt/go-dev-mcp/internal/model"
 	"github.com/fpt/go-dev-mcp/pkg/dq"
-	"github.com/patrickmn/go-cache"
 	"github.com/pkg/errors"
 	"golang.org/x/net/html"
 )
 
-func SearchRustDoc(httpcli *infra.HttpClient, query string) (string, error) {
+func SearchRustDoc(ctx context.Context, httpcli *infra.HttpClient, query string) (string, error) {
 	u := fmt.Sprintf(
-		"https://docs.rs/releases/search?query=%s",
+		"%s/releases/search?query=%s",
+		docSites.RustDoc,
 		url.QueryEscape(query),
 	)
-	bodyrdr, err := httpcli.HttpGet(u)
+	bodyrdr, err := httpcli.HttpGet(ctx, u)
 	if err != nil {
 		return "", errors.Wrap(err, "failed to make HTTP request")
 	}
-	if bodyrdr == nil {
-		return "", ErrNotFound
-	}
 	defer bodyrdr.Close()
 
 	doc, err := html.Parse(bodyrdr)
@@ -44,55 +43,109 @@ func SearchRustDoc(httpcli *infra.HttpClient, query string) (string, error) {
 
 // ReadRustDocPaged reads Rust documentation for a given crate URL with line-based paging.
 // crateURL can be "serde", "serde/de", "tokio/runtime", etc.
+// If symbol is set (e.g. "Deserialize", "serde::de::Visitor" or
+// "Deserializer::deserialize_any"), only the documentation of that item is returned.
 func ReadRustDocPaged(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
-	crateURL string,
+	crateURL, symbol string,
 	offset, limit int,
 ) (string, int, bool, error) {
-	cacheKey := fmt.Sprintf("rustdoc:%s", crateURL)
-
-	var document string
-
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		u := buildDocsRsURL(crateURL)
-		bodyrdr, err := httpcli.HttpGet(u)
-		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return "", 0, false, ErrNotFound
-		}
-		defer bodyrdr.Close()
+	page, err := fetchRustDoc(ctx, httpcli, crateURL)
+	if err != nil {
+		return "", 0, false, err
+	}
 
-		doc, err := html.Parse(bodyrdr)
+	text := page.Text
+	if symbol != "" {
+		text, err = findRustDocSymbol(ctx, httpcli, crateURL, page, symbol)
 		if err != nil {
-			return "", 0, false, errors.Wrap(err, "failed to parse HTML")
+			return "", 0, false, err
 		}
+	}
 
-		_, document = parseDocsRsDocument(doc)
+	content, totalLines, hasMore := pageLines(text, offset, limit)
+	return content, totalLines, hasMore, nil
+}
 
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
-	}
+// rustDocPage is a parsed docs.rs page together with the items listed in its item tables.
+type rustDocPage struct {
+	parsedDocument
+	Items map[string]string // Item name to relative page (e.g. "Deserialize": "trait.Deserialize.html")
+}
 
-	lines := strings.Split(document, "\n")
-	totalLines := len(lines)
+// fetchRustDoc fetches and parses the docs.rs page of crateURL.
+// Parsed pages are cached.
+func fetchRustDoc(
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	crateURL string,
+) (*rustDocPage, error) {
+	cacheKey := fmt.Sprintf("rustdoc:%s", crateURL)
+	u := buildDocsRsURL(crateURL)
+	return fetchCachedDoc(ctx, httpcli, cacheKey, u, func(doc *html.Node) (*rustDocPage, error) {
+		_, page := parseDocsRsDocument(doc)
+		return page, nil
+	})
+}
 
-	startIdx := offset
-	if startIdx >= totalLines {
-		return "", totalLines, false, nil
+// findRustDocSymbol follows the "::"-separated path of symbol from the page of
+// crateURL through item pages. The last segment may also name a member of an
+// item, such as a method, variant or field.
+func findRustDocSymbol(
+	ctx context.Context,
+	httpcli *infra.HttpClient,
+	crateURL string,
+	page *rustDocPage,
+	symbol string,
+) (string, error) {
+	segments := strings.Split(symbol, "::")
+	crateName := strings.ReplaceAll(strings.SplitN(crateURL, "/", 2)[0], "-", "_")
+	if len(segments) > 1 && (segments[0] == crateName || segments[0] == "crate") {
+		segments = segments[1:]
 	}
 
-	endIdx := startIdx + limit
-	hasMore := endIdx < totalLines
-	if endIdx > totalLines {
-		endIdx = totalLines
+	pageURL := crateURL
+	for i, segment := range segments {
+		if href, ok := page.Items[segment]; ok {
+			pageURL = rustDocItemURL(pageURL, href)
+			next, err := fetchRustDoc(ctx, httpcli, pageURL)
+			if err != nil {
+				return "", err
+			}
+			page = next
+			continue
+		}
+		if i == len(segments)-1 {
+			if section, ok := page.section(rustDocMemberAnchors(segment)...); ok {
+				return section, nil
+			}
+		}
+		return "", errors.Wrapf(ErrNotFound, "%s not found in %s", segment, pageURL)
 	}
 
-	pagedContent := strings.Join(lines[startIdx:endIdx], "\n")
+	return page.Text, nil
+}
 
-	return pagedContent, totalLines, hasMore, nil
+// rustDocItemURL resolves the item link href relative to the page of pageURL.
+// "serde" + "de/index.html" → "serde/de/index.html"
+// "serde/de/index.html" + "trait.Visitor.html" → "serde/de/trait.Visitor.html"
+func rustDocItemURL(pageURL, href string) string {
+	if strings.HasSuffix(pageURL, ".html") {
+		return path.Join(path.Dir(pageURL), href)
+	}
+	return path.Join(pageURL, href)
+}
+
+// rustDocMemberAnchors returns the anchors rustdoc gives to members named name.
+func rustDocMemberAnchors(name string) []string {
+	anchors := []string{name}
+	for _, kind := range []string{
+		"tymethod", "method", "variant", "structfield", "associatedtype", "associatedconstant",
+	} {
+		anchors = append(anchors, kind+"."+name)
+	}
+	return anchors
 }
 
 type RustDocSearchResult struct {
@@ -102,40 +155,25 @@ type RustDocSearchResult struct {
 }
 
 func SearchWithinRustDoc(
+	ctx context.Context,
 	httpcli *infra.HttpClient,
 	crateURL string,
-	keyword string,
-	maxMatches int,
+	query contentsearch.Query,
+	maxMatches, before, after int,
 ) (*RustDocSearchResult, error) {
-	cacheKey := fmt.Sprintf("rustdoc:%s", crateURL)
-
-	var document string
-
-	if cached, found := docCache.Get(cacheKey); found {
-		document = cached.(string)
-	} else {
-		u := buildDocsRsURL(crateURL)
-		bodyrdr, err := httpcli.HttpGet(u)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to make HTTP request")
-		}
-		if bodyrdr == nil {
-			return nil, ErrNotFound
-		}
-		defer bodyrdr.Close()
-
-		doc, err := html.Parse(bodyrdr)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to parse HTML")
-		}
-
-		_, document = parseDocsRsDocument(doc)
+	matcher, err := contentsearch.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
 
-		docCache.Set(cacheKey, document, cache.DefaultExpiration)
+	page, err := fetchRustDoc(ctx, httpcli, crateURL)
+	if err != nil {
+		return nil, err
 	}
 
-	reader := strings.NewReader(document)
-	matches, truncated, err := contentsearch.SearchInContent(reader, keyword, maxMatches)
+	reader := strings.NewReader(page.Text)
+	matches, truncated, err := contentsearch.SearchInContent(
+		reader, matcher, maxMatches, before, after)
 	if err != nil {
 		return nil, err
 	}
@@ -147,7 +185,8 @@ func SearchWithinRustDoc(
 	}, nil
 }
 
-// buildDocsRsURL constructs the docs.rs URL from a crate URL.
+// buildDocsRsURL constructs the docs.rs URL from a crate URL
+// (on the configured site, see ConfigureDocSites).
 // "serde" → "https://docs.rs/serde/latest/serde/"
 // "serde/de" → "https://docs.rs/serde/latest/serde/de/"
 // "serde-json" → "https://docs.rs/serde-json/latest/serde_json/"
@@ -158,11 +197,12 @@ func buildDocsRsURL(crateURL string) string {
 	crateModule := strings.ReplaceAll(crateName, "-", "_")
 
 	if len(parts) == 1 {
-		return fmt.Sprintf("https://docs.rs/%s/latest/%s/", crateName, crateModule)
+		return fmt.Sprintf("%s/%s/latest/%s/", docSites.RustDoc, crateName, crateModule)
 	}
 
 	return fmt.Sprintf(
-		"https://docs.rs/%s/latest/%s/%s",
+		"%s/%s/latest/%s/%s",
+		docSites.RustDoc,
 		crateName,
 		crateModule,
 		parts[1],
@@ -199,7 +239,7 @@ func parseDocsRsSearchResult(doc *html.Node) (bool, string) {
 			desc := strings.TrimSpace(dq.InnerText(n, true))
 			builder.WriteString(fmt.Sprintf("* %s\n", currentName))
 			if currentHref != "" {
-				builder.WriteString(fmt.Sprintf("\tURL: https://docs.rs%s\n", currentHref))
+				builder.WriteString(fmt.Sprintf("\tURL: %s%s\n", docSites.RustDoc, currentHref))
 			}
 			if desc != "" {
 				builder.WriteString(fmt.Sprintf("\tDescription: %s\n", desc))
@@ -243,9 +283,23 @@ func parseDocsRsSearchResult(doc *html.Node) (bool, string) {
 	return matched, builder.String()
 }
 
+var (
+	isRustDocMember = dq.NewMatchFunc(
+		"section.method,section.variant,section.impl,span.structfield",
+	)
+	isRustDocBlock  = dq.NewMatchFunc("div.docblock")
+	isRustDocTopDoc = dq.NewMatchFunc("details.top-doc")
+	isRustDocImpl   = dq.NewMatchFunc("section.impl")
+)
+
+// parseDocsRsDocument parses a docs.rs module or item page.
+// Section headers, doc headings and members (methods, variants, fields, impls)
+// are indexed by their anchor (e.g. "structs", "method.deserialize_any").
+//
 //nolint:funlen // Parser function with multiple matchers
-func parseDocsRsDocument(doc *html.Node) (bool, string) {
-	builder := strings.Builder{}
+func parseDocsRsDocument(doc *html.Node) (bool, *rustDocPage) {
+	builder := &docBuilder{}
+	items := make(map[string]string)
 
 	// Title from div.main-heading > h1
 	titleMatcher := dq.NewNodeMatcher(
@@ -267,8 +321,10 @@ func parseDocsRsDocument(doc *html.Node) (bool, string) {
 			text := cleanRustDocHeading(dq.InnerText(n, true))
 			h := strings.TrimPrefix(n.Data, "h")
 			hn, _ := strconv.Atoi(h)
+			builder.WriteString("\n")
+			builder.startSection(dq.GetAttr(n, "id"), hn)
 			builder.WriteString(
-				fmt.Sprintf("\n%s %s\n", strings.Repeat("#", hn), text),
+				fmt.Sprintf("%s %s\n", strings.Repeat("#", hn), text),
 			)
 		},
 	)
@@ -301,6 +357,13 @@ func parseDocsRsDocument(doc *html.Node) (bool, string) {
 		),
 	)
 
+	docblockMatchers := []dq.Matcher{
+		docblockHeaderMatcher,
+		docblockPMatcher,
+		docblockPreMatcher,
+		docblockListMatcher,
+	}
+
 	// Overview: details.top-doc > div.docblock
 	overviewMatcher := dq.NewNodeMatcher(
 		dq.NewMatchFunc("details.top-doc"),
@@ -308,19 +371,53 @@ func parseDocsRsDocument(doc *html.Node) (bool, string) {
 		dq.NewNodeMatcher(
 			dq.NewMatchFunc("div.docblock"),
 			nil,
-			docblockHeaderMatcher,
-			docblockPMatcher,
-			docblockPreMatcher,
-			docblockListMatcher,
+			docblockMatchers...,
 		),
 	)
 
+	// Item declaration on item pages: pre.item-decl
+	declMatcher := dq.NewNodeMatcher(
+		dq.NewMatchFunc("pre.item-decl"),
+		func(n *html.Node) {
+			builder.WriteString(fmt.Sprintf("```\n%s\n```\n", dq.RawInnerText(n, true)))
+		},
+	)
+
+	// Members on item pages: methods, variants, fields and impl blocks.
+	// Impl blocks are one level above so that their section includes their methods.
+	memberMatcher := dq.NewNodeMatcher(
+		isRustDocMember,
+		func(n *html.Node) {
+			level := 4
+			if isRustDocImpl(n) {
+				level = 3
+			}
+			header := n
+			if code := dq.FindOne(n, ".code-header"); code != nil {
+				header = code
+			}
+			text := cleanRustDocHeading(dq.InnerText(header, true))
+			builder.WriteString("\n")
+			builder.startSection(dq.GetAttr(n, "id"), level)
+			builder.WriteString(fmt.Sprintf("%s %s\n", strings.Repeat("#", level), text))
+		},
+	)
+	memberDocMatcher := dq.NewNodeMatcher(
+		func(n *html.Node) bool {
+			return isRustDocBlock(n) && n.Parent != nil && !isRustDocTopDoc(n.Parent)
+		},
+		nil,
+		docblockMatchers...,
+	)
+
 	// Section headers: h2.section-header
 	sectionHeaderMatcher := dq.NewNodeMatcher(
 		dq.NewMatchFunc("h2.section-header"),
 		func(n *html.Node) {
 			text := cleanRustDocHeading(dq.InnerText(n, true))
-			builder.WriteString(fmt.Sprintf("\n## %s\n", text))
+			builder.WriteString("\n")
+			builder.startSection(dq.GetAttr(n, "id"), 2)
+			builder.WriteString(fmt.Sprintf("## %s\n", text))
 		},
 	)
 
@@ -330,6 +427,15 @@ func parseDocsRsDocument(doc *html.Node) (bool, string) {
 		dq.NewMatchFunc("dt"),
 		func(n *html.Node) {
 			currentItem = strings.TrimSpace(dq.InnerText(n, true))
+			if a := dq.FindOne(n, "a[title]"); a != nil {
+				// title is e.g. "trait serde::Deserialize" or "mod serde::de"
+				name := dq.GetAttr(a, "title")
+				name = name[strings.LastIndexAny(name, ": ")+1:]
+				href := dq.GetHref(a)
+				if _, seen := items[name]; !seen && !strings.Contains(href, "://") {
+					items[name] = href
+				}
+			}
 		},
 	)
 	ddMatcher := dq.NewNodeMatcher(
@@ -357,11 +463,14 @@ func parseDocsRsDocument(doc *html.Node) (bool, string) {
 			matched = true
 		},
 		titleMatcher,
+		declMatcher,
 		overviewMatcher,
 		sectionHeaderMatcher,
 		itemTableMatcher,
+		memberMatcher,
+		memberDocMatcher,
 	)
 
 	dq.Traverse(doc, []dq.Matcher{rootMatcher})
-	return matched, builder.String()
+	return matched, &rustDocPage{parsedDocument: *builder.document(), Items: items}
 }
@@ -1,8 +1,12 @@
 package app
 
 import (
+	"context"
+	"strings"
 	"testing"
 
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/patrickmn/go-cache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -34,12 +38,12 @@ func TestGolden_parseDocsRsDocument(t *testing.T) {
 
 			goldenPath := "testdata/" + tt.golden
 			if *update {
-				writeGolden(t, goldenPath, got)
+				writeGolden(t, goldenPath, got.Text)
 				return
 			}
 
 			want := readGolden(t, goldenPath)
-			assert.Equal(t, want, got, "output does not match golden file %s", tt.golden)
+			assert.Equal(t, want, got.Text, "output does not match golden file %s", tt.golden)
 		})
 	}
 }
@@ -58,3 +62,51 @@ func TestGolden_parseDocsRsSearchResult(t *testing.T) {
 	want := readGolden(t, goldenPath)
 	assert.Equal(t, want, got, "output does not match golden file rustdoc_search_serde.golden")
 }
+
+func TestReadRustDocPaged_Symbol(t *testing.T) {
+	// Seed the cache with fixtures so that no page is fetched from docs.rs.
+	_, crate := parseDocsRsDocument(loadHTMLFixture(t, "rustdoc_doc_serde.html"))
+	_, item := parseDocsRsDocument(loadHTMLFixture(t, "rustdoc_item_serde_deserialize.html"))
+	docCache.Set("rustdoc:serde", crate, cache.NoExpiration)
+	docCache.Set("rustdoc:serde/trait.Deserialize.html", item, cache.NoExpiration)
+	t.Cleanup(func() {
+		docCache.Delete("rustdoc:serde")
+		docCache.Delete("rustdoc:serde/trait.Deserialize.html")
+	})
+
+	httpcli := infra.NewHttpClient()
+
+	content, _, _, err := ReadRustDocPaged(
+		context.Background(),
+		httpcli,
+		"serde",
+		"serde::Deserialize",
+		0,
+		100,
+	)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(content, "# Trait Deserialize\n"), content)
+
+	content, _, _, err = ReadRustDocPaged(
+		context.Background(),
+		httpcli,
+		"serde",
+		"Deserialize::deserialize",
+		0,
+		100,
+	)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(content, "#### fn deserialize"), content)
+	assert.Contains(t, content, "Deserialize this value from the given Serde deserializer.")
+	assert.NotContains(t, content, "## Dyn Compatibility")
+
+	_, _, _, err = ReadRustDocPaged(
+		context.Background(),
+		httpcli,
+		"serde",
+		"Deserialize::missing",
+		0,
+		100,
+	)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
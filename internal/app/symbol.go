@@ -0,0 +1,251 @@
+package app
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/tools/go/packages"
+)
+
+// resolvedSymbol is a Go object found by a symbol query together with
+// the package it was looked up from.
+type resolvedSymbol struct {
+	Object types.Object
+	Pkg    *packages.Package // nil when the object comes from export data only
+	Fset   *token.FileSet
+}
+
+// resolveSymbol resolves query against the loaded packages.
+// query is either a "file:line:column" position or a qualified name such as
+// "net/http.Client.Do" or "internal/repository.FileWalker.Walk"
+// (package paths may be relative to the module root).
+func resolveSymbol(pkgs []*packages.Package, query string) (*resolvedSymbol, error) {
+	if file, line, col, ok := parsePositionQuery(query); ok {
+		return resolvePosition(pkgs, file, line, col)
+	}
+	return resolveQualifiedName(pkgs, query)
+}
+
+// parsePositionQuery splits a "file:line:column" query.
+func parsePositionQuery(query string) (string, int, int, bool) {
+	parts := strings.Split(query, ":")
+	if len(parts) < 3 {
+		return "", 0, 0, false
+	}
+	n := len(parts)
+	line, err1 := strconv.Atoi(parts[n-2])
+	col, err2 := strconv.Atoi(parts[n-1])
+	if err1 != nil || err2 != nil || line < 1 || col < 1 {
+		return "", 0, 0, false
+	}
+	return strings.Join(parts[:n-2], ":"), line, col, true
+}
+
+// resolvePosition finds the object denoted by the identifier at file:line:col.
+func resolvePosition(
+	pkgs []*packages.Package,
+	file string,
+	line, col int,
+) (*resolvedSymbol, error) {
+	absFile, err := filepath.Abs(file)
+	if err != nil {
+		absFile = file
+	}
+
+	for _, pkg := range pkgs {
+		if pkg.TypesInfo == nil {
+			continue
+		}
+		for _, f := range pkg.Syntax {
+			tf := pkg.Fset.File(f.Pos())
+			if tf == nil || !sameFile(tf.Name(), absFile) {
+				continue
+			}
+			if line > tf.LineCount() {
+				return nil, errors.Errorf("line %d is out of range for %s", line, file)
+			}
+			pos := tf.LineStart(line) + token.Pos(col-1)
+
+			var found *ast.Ident
+			ast.Inspect(f, func(n ast.Node) bool {
+				if n == nil || found != nil || pos < n.Pos() || pos >= n.End() {
+					return false
+				}
+				if id, ok := n.(*ast.Ident); ok {
+					found = id
+					return false
+				}
+				return true
+			})
+			if found == nil {
+				return nil, errors.Errorf("no identifier at %s:%d:%d", file, line, col)
+			}
+
+			obj := pkg.TypesInfo.Defs[found]
+			if obj == nil {
+				obj = pkg.TypesInfo.Uses[found]
+			}
+			if obj == nil {
+				return nil, errors.Errorf("no object for identifier %q at %s:%d:%d",
+					found.Name, file, line, col)
+			}
+			return &resolvedSymbol{Object: obj, Pkg: pkg, Fset: pkg.Fset}, nil
+		}
+	}
+
+	return nil, errors.Errorf("file %s is not part of the loaded packages", file)
+}
+
+func sameFile(a, b string) bool {
+	if a == b {
+		return true
+	}
+	ra, err1 := filepath.EvalSymlinks(a)
+	rb, err2 := filepath.EvalSymlinks(b)
+	return err1 == nil && err2 == nil && ra == rb
+}
+
+// resolveQualifiedName resolves "pkgpath.Name", "pkgpath.Type.Method" or
+// "pkgpath.Type.Field". The package may be given by its full import path,
+// a path relative to the module root, or its package name when unambiguous.
+func resolveQualifiedName(pkgs []*packages.Package, query string) (*resolvedSymbol, error) {
+	slash := strings.LastIndex(query, "/")
+	var lastErr error
+	for i := slash + 1; i < len(query); i++ {
+		if query[i] != '.' {
+			continue
+		}
+		pkgPart, member := query[:i], query[i+1:]
+		pkg, tpkg := findPackage(pkgs, pkgPart)
+		if tpkg == nil {
+			continue
+		}
+		obj, err := lookupMember(tpkg, member)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		fset := token.NewFileSet()
+		if len(pkgs) > 0 {
+			fset = pkgs[0].Fset
+		}
+		return &resolvedSymbol{Object: obj, Pkg: pkg, Fset: fset}, nil
+	}
+
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, errors.Errorf("cannot resolve %q: package not found", query)
+}
+
+// findPackage looks up a package by import path, module-relative path or name.
+// The returned *packages.Package is nil when only type information is available.
+func findPackage(pkgs []*packages.Package, pkgPath string) (*packages.Package, *types.Package) {
+	var byName []*packages.Package
+	var match *packages.Package
+	packages.Visit(pkgs, nil, func(p *packages.Package) {
+		if match != nil || p.Types == nil || !p.Types.Complete() || isTestVariant(p) {
+			return
+		}
+		if p.PkgPath == pkgPath ||
+			(p.Module != nil && p.PkgPath == p.Module.Path+"/"+strings.TrimPrefix(pkgPath, "./")) {
+			match = p
+			return
+		}
+		if p.Name == pkgPath {
+			byName = append(byName, p)
+		}
+	})
+	if match == nil && len(byName) == 1 {
+		match = byName[0]
+	}
+	if match == nil {
+		return nil, nil
+	}
+	if match.TypesInfo == nil {
+		// Imported from export data: no syntax is available
+		return nil, match.Types
+	}
+	return match, match.Types
+}
+
+// isTestVariant reports whether p is a test-augmented copy of a package
+// (e.g. "p [p.test]") or a generated test main package.
+func isTestVariant(p *packages.Package) bool {
+	return strings.Contains(p.ID, " [") || strings.HasSuffix(p.PkgPath, ".test")
+}
+
+// lookupMember resolves "Name", "Type.Method" or "Type.Field" in a package scope.
+func lookupMember(tpkg *types.Package, member string) (types.Object, error) {
+	parts := strings.Split(member, ".")
+	obj := tpkg.Scope().Lookup(parts[0])
+	if obj == nil {
+		return nil, errors.Errorf("%s not found in package %s", parts[0], tpkg.Path())
+	}
+	for _, name := range parts[1:] {
+		tn, ok := obj.(*types.TypeName)
+		if !ok {
+			return nil, errors.Errorf("%s is not a type", obj.Name())
+		}
+		sel, _, _ := types.LookupFieldOrMethod(tn.Type(), true, tpkg, name)
+		if sel == nil {
+			return nil, errors.Errorf("%s has no field or method %s", tn.Name(), name)
+		}
+		obj = sel
+	}
+	return obj, nil
+}
+
+// objectKey identifies an object by its declaring position so that the same
+// declaration matches across package variants (e.g. test-augmented packages).
+func objectKey(fset *token.FileSet, obj types.Object) string {
+	if !obj.Pos().IsValid() {
+		if obj.Pkg() != nil {
+			return obj.Pkg().Path() + "." + obj.Name()
+		}
+		return obj.Name()
+	}
+	return fmt.Sprintf("%s#%s", fset.Position(obj.Pos()), obj.Name())
+}
+
+// enclosingFunction returns the name of the function declaration containing pos,
+// using the same "Recv.Method" notation as the outline.
+func enclosingFunction(file *ast.File, pos token.Pos) string {
+	for _, decl := range file.Decls {
+		if pos < decl.Pos() || pos >= decl.End() {
+			continue
+		}
+		if fd, ok := decl.(*ast.FuncDecl); ok {
+			return getFunctionSignature(fd)
+		}
+		return ""
+	}
+	return ""
+}
+
+// qualifiedObjectName formats an object as it would be written in a query,
+// e.g. "net/http.Client.Do" or "github.com/user/repo.Func".
+func qualifiedObjectName(obj types.Object) string {
+	prefix := ""
+	if obj.Pkg() != nil {
+		prefix = obj.Pkg().Path() + "."
+	}
+	if fn, ok := obj.(*types.Func); ok {
+		if recv := fn.Signature().Recv(); recv != nil {
+			t := recv.Type()
+			if p, ok := t.(*types.Pointer); ok {
+				t = p.Elem()
+			}
+			if named, ok := t.(*types.Named); ok {
+				return prefix + named.Obj().Name() + "." + obj.Name()
+			}
+		}
+	}
+	return prefix + obj.Name()
+}
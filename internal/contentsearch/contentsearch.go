@@ -3,31 +3,209 @@ package contentsearch
 import (
 	"bufio"
 	"io"
+	"regexp"
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/model"
+	"github.com/pkg/errors"
 )
 
+// Mode is how the terms of a query match lines.
+type Mode string
+
+const (
+	ModeLiteral Mode = "literal" // Terms are substrings
+	ModeRegex   Mode = "regex"   // Terms are regular expressions (RE2 syntax)
+	ModeWord    Mode = "word"    // Terms are whole words, like grep -w
+)
+
+// ParseMode parses a mode, defaulting to ModeLiteral if empty.
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case "", ModeLiteral:
+		return ModeLiteral, nil
+	case ModeRegex, ModeWord:
+		return Mode(mode), nil
+	default:
+		return "", errors.Errorf("unknown search mode %q (expected literal, regex or word)", mode)
+	}
+}
+
+// Operator is how the terms of a query are combined.
+type Operator string
+
+const (
+	OperatorAnd Operator = "and" // Lines must match all terms
+	OperatorOr  Operator = "or"  // Lines must match any term
+)
+
+// ParseOperator parses an operator, defaulting to OperatorAnd if empty.
+func ParseOperator(operator string) (Operator, error) {
+	switch Operator(strings.ToLower(operator)) {
+	case "", OperatorAnd:
+		return OperatorAnd, nil
+	case OperatorOr:
+		return OperatorOr, nil
+	default:
+		return "", errors.Errorf("unknown operator %q (expected and or or)", operator)
+	}
+}
+
+// Query describes the lines to search for.
+type Query struct {
+	Terms      []string
+	Mode       Mode // ModeLiteral if empty
+	IgnoreCase bool
+	Operator   Operator // OperatorAnd if empty
+}
+
+// Matcher matches lines against a compiled Query.
+type Matcher struct {
+	literals []string         // Case-sensitive literal terms
+	patterns []*regexp.Regexp // All other terms, with the matched text as the first group
+	any      bool
+}
+
+// wordBoundary matches the start or end of a word, with letters, digits and
+// underscores as word characters.
+const wordBoundary = `[^\pL\pN_]`
+
+// NewMatcher compiles a query. A query without terms matches every line.
+func NewMatcher(q Query) (*Matcher, error) {
+	mode := q.Mode
+	if mode == "" {
+		mode = ModeLiteral
+	}
+	m := &Matcher{any: q.Operator == OperatorOr}
+	for _, term := range q.Terms {
+		if mode == ModeLiteral && !q.IgnoreCase {
+			m.literals = append(m.literals, term)
+			continue
+		}
+
+		var expr string
+		switch mode {
+		case ModeLiteral:
+			expr = "(" + regexp.QuoteMeta(term) + ")"
+		case ModeWord:
+			expr = `(?:^|` + wordBoundary + `)(` + regexp.QuoteMeta(
+				term,
+			) + `)(?:$|` + wordBoundary + `)`
+		case ModeRegex:
+			if _, err := regexp.Compile(term); err != nil {
+				return nil, errors.Wrapf(err, "invalid pattern %q", term)
+			}
+			expr = "(" + term + ")"
+		default:
+			return nil, errors.Errorf("unknown search mode %q", mode)
+		}
+		if q.IgnoreCase {
+			expr = "(?i)" + expr
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pattern %q", term)
+		}
+		m.patterns = append(m.patterns, re)
+	}
+	return m, nil
+}
+
+// Match reports whether line matches the query.
+func (m *Matcher) Match(line string) bool {
+	_, _, ok := m.Find(line)
+	return ok
+}
+
+// Find reports whether line matches the query, and returns the byte offsets
+// of the leftmost term found in it. The offsets are 0 for queries without terms.
+func (m *Matcher) Find(line string) (start, end int, ok bool) {
+	if len(m.literals) == 0 && len(m.patterns) == 0 {
+		return 0, 0, true
+	}
+	start = -1
+	found := func(s, e int) {
+		if start < 0 || s < start {
+			start, end = s, e
+		}
+	}
+	for _, literal := range m.literals {
+		i := strings.Index(line, literal)
+		if i < 0 {
+			if m.any {
+				continue
+			}
+			return 0, 0, false
+		}
+		found(i, i+len(literal))
+	}
+	for _, re := range m.patterns {
+		loc := re.FindStringSubmatchIndex(line)
+		if loc == nil {
+			if m.any {
+				continue
+			}
+			return 0, 0, false
+		}
+		found(loc[2], loc[3])
+	}
+	if start < 0 {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// SearchInContent returns the lines of reader matching matcher, up to
+// maxMatches, with up to before and after lines of context like grep -B and -A.
+// Overlapping context is merged: lines are reported once, as the context of
+// the nearest match, and never as context if they match themselves.
 func SearchInContent(
 	reader io.Reader,
-	query string,
-	maxMatches int,
+	matcher *Matcher,
+	maxMatches, before, after int,
 ) ([]model.SearchMatch, bool, error) {
 	lineNo := 1
 	var matches []model.SearchMatch
+	var pending []string // Lines after the context of the last match
 	truncated := false
 
 	scanner := bufio.NewScanner(reader)
 	for scanner.Scan() {
 		line := scanner.Text()
-		if searchInLine(line, query) {
+		// Lines still in the after context of the last match
+		inAfter := len(matches) > 0 && len(matches[len(matches)-1].After) < after
+		start, end, ok := matcher.Find(line)
+		if ok && !truncated {
 			// Check if we've reached the maximum number of matches
 			if len(matches) >= maxMatches {
 				truncated = true
-				break
+			} else {
+				m := model.SearchMatch{LineNo: lineNo, Text: line}
+				if len(matcher.literals)+len(matcher.patterns) > 0 {
+					m.Column, m.EndColumn = start+1, max(end, start+1)
+				}
+				if len(pending) > 0 {
+					m.Before = pending
+				}
+				matches = append(matches, m)
+				pending = nil
+				lineNo++
+				continue
 			}
-			m := model.SearchMatch{LineNo: lineNo, Text: line}
-			matches = append(matches, m)
+		}
+		switch {
+		case inAfter:
+			last := &matches[len(matches)-1]
+			last.After = append(last.After, line)
+		case truncated:
+		case before > 0:
+			if len(pending) == before {
+				pending = pending[1:]
+			}
+			pending = append(pending, line)
+		}
+		if truncated && !inAfter {
+			break
 		}
 		lineNo++
 	}
@@ -37,7 +215,3 @@ func SearchInContent(
 
 	return matches, truncated, nil
 }
-
-func searchInLine(line, query string) bool {
-	return strings.Contains(line, query)
-}
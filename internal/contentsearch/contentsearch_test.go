@@ -8,6 +8,15 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
+func literal(t *testing.T, query string) *Matcher {
+	t.Helper()
+	m, err := NewMatcher(Query{Terms: []string{query}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
 func TestSearchInContent(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -24,9 +33,9 @@ func TestSearchInContent(t *testing.T) {
 			query:      "line",
 			maxMatches: 10,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "line one"},
-				{LineNo: 2, Text: "line two"},
-				{LineNo: 3, Text: "line three"},
+				{LineNo: 1, Text: "line one", Column: 1, EndColumn: 4},
+				{LineNo: 2, Text: "line two", Column: 1, EndColumn: 4},
+				{LineNo: 3, Text: "line three", Column: 1, EndColumn: 4},
 			},
 			truncated: false,
 		},
@@ -36,7 +45,7 @@ func TestSearchInContent(t *testing.T) {
 			query:      "hello",
 			maxMatches: 10,
 			expected: []model.SearchMatch{
-				{LineNo: 2, Text: "hello world"},
+				{LineNo: 2, Text: "hello world", Column: 1, EndColumn: 5},
 			},
 			truncated: false,
 		},
@@ -54,8 +63,8 @@ func TestSearchInContent(t *testing.T) {
 			query:      "test",
 			maxMatches: 2,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "test line 1"},
-				{LineNo: 2, Text: "test line 2"},
+				{LineNo: 1, Text: "test line 1", Column: 1, EndColumn: 4},
+				{LineNo: 2, Text: "test line 2", Column: 1, EndColumn: 4},
 			},
 			truncated: true,
 		},
@@ -65,7 +74,7 @@ func TestSearchInContent(t *testing.T) {
 			query:      "keyword",
 			maxMatches: 5,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "single line with keyword"},
+				{LineNo: 1, Text: "single line with keyword", Column: 18, EndColumn: 24},
 			},
 			truncated: false,
 		},
@@ -83,8 +92,8 @@ func TestSearchInContent(t *testing.T) {
 			query:      "",
 			maxMatches: 10,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "line one"},
-				{LineNo: 2, Text: "line two"},
+				{LineNo: 1, Text: "line one", Column: 1, EndColumn: 1},
+				{LineNo: 2, Text: "line two", Column: 1, EndColumn: 1},
 			},
 			truncated: false,
 		},
@@ -102,9 +111,9 @@ func TestSearchInContent(t *testing.T) {
 			query:      "test",
 			maxMatches: 10,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "testing"},
-				{LineNo: 2, Text: "tester"},
-				{LineNo: 3, Text: "test"},
+				{LineNo: 1, Text: "testing", Column: 1, EndColumn: 4},
+				{LineNo: 2, Text: "tester", Column: 1, EndColumn: 4},
+				{LineNo: 3, Text: "test", Column: 1, EndColumn: 4},
 			},
 			truncated: false,
 		},
@@ -114,9 +123,9 @@ func TestSearchInContent(t *testing.T) {
 			query:      "with",
 			maxMatches: 10,
 			expected: []model.SearchMatch{
-				{LineNo: 1, Text: "line with @#$%"},
-				{LineNo: 2, Text: "line with spaces"},
-				{LineNo: 3, Text: "line with\ttabs"},
+				{LineNo: 1, Text: "line with @#$%", Column: 6, EndColumn: 9},
+				{LineNo: 2, Text: "line with spaces", Column: 6, EndColumn: 9},
+				{LineNo: 3, Text: "line with\ttabs", Column: 6, EndColumn: 9},
 			},
 			truncated: false,
 		},
@@ -125,7 +134,13 @@ func TestSearchInContent(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			reader := strings.NewReader(tt.content)
-			matches, truncated, err := SearchInContent(reader, tt.query, tt.maxMatches)
+			matches, truncated, err := SearchInContent(
+				reader,
+				literal(t, tt.query),
+				tt.maxMatches,
+				0,
+				0,
+			)
 
 			if tt.wantError {
 				assert.Error(t, err)
@@ -139,77 +154,6 @@ func TestSearchInContent(t *testing.T) {
 	}
 }
 
-func TestSearchInLine(t *testing.T) {
-	tests := []struct {
-		name     string
-		line     string
-		query    string
-		expected bool
-	}{
-		{
-			name:     "exact match",
-			line:     "hello world",
-			query:    "hello world",
-			expected: true,
-		},
-		{
-			name:     "partial match",
-			line:     "hello world",
-			query:    "world",
-			expected: true,
-		},
-		{
-			name:     "case sensitive no match",
-			line:     "Hello World",
-			query:    "hello",
-			expected: false,
-		},
-		{
-			name:     "case sensitive match",
-			line:     "Hello World",
-			query:    "Hello",
-			expected: true,
-		},
-		{
-			name:     "no match",
-			line:     "hello world",
-			query:    "xyz",
-			expected: false,
-		},
-		{
-			name:     "empty query matches everything",
-			line:     "any line",
-			query:    "",
-			expected: true,
-		},
-		{
-			name:     "empty line with non-empty query",
-			line:     "",
-			query:    "test",
-			expected: false,
-		},
-		{
-			name:     "empty line with empty query",
-			line:     "",
-			query:    "",
-			expected: true,
-		},
-		{
-			name:     "special characters",
-			line:     "line with @#$% symbols",
-			query:    "@#$%",
-			expected: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := searchInLine(tt.line, tt.query)
-			assert.Equal(t, tt.expected, result)
-		})
-	}
-}
-
 func TestSearchInContent_LargeContent(t *testing.T) {
 	// Test with a larger content to ensure performance and correctness
 	var lines []string
@@ -223,7 +167,7 @@ func TestSearchInContent_LargeContent(t *testing.T) {
 	content := strings.Join(lines, "\n")
 
 	reader := strings.NewReader(content)
-	matches, truncated, err := SearchInContent(reader, "special", 5)
+	matches, truncated, err := SearchInContent(reader, literal(t, "special"), 5, 0, 0)
 
 	assert.NoError(t, err)
 	assert.True(t, truncated, "Should be truncated with only 5 max matches")
@@ -249,17 +193,220 @@ func main() {
 `
 
 	reader := strings.NewReader(content)
-	matches, truncated, err := SearchInContent(reader, "fmt", 10)
+	matches, truncated, err := SearchInContent(reader, literal(t, "fmt"), 10, 0, 0)
 
 	assert.NoError(t, err)
 	assert.False(t, truncated)
 	assert.Len(t, matches, 3)
 
 	expected := []model.SearchMatch{
-		{LineNo: 3, Text: `import "fmt"`},
-		{LineNo: 6, Text: `	fmt.Println("Hello World")`},
-		{LineNo: 8, Text: `	fmt.Printf("Testing %s", "format")`},
+		{LineNo: 3, Text: `import "fmt"`, Column: 9, EndColumn: 11},
+		{LineNo: 6, Text: `	fmt.Println("Hello World")`, Column: 2, EndColumn: 4},
+		{LineNo: 8, Text: `	fmt.Printf("Testing %s", "format")`, Column: 2, EndColumn: 4},
 	}
 
 	assert.Equal(t, expected, matches)
 }
+
+func TestMatcher(t *testing.T) {
+	tests := []struct {
+		name  string
+		query Query
+		lines map[string]bool
+	}{
+		{
+			name:  "ignore case",
+			query: Query{Terms: []string{"hello"}, IgnoreCase: true},
+			lines: map[string]bool{"Hello World": true, "HELLO": true, "help": false},
+		},
+		{
+			name:  "regex",
+			query: Query{Terms: []string{`func .*Walk\(`}, Mode: ModeRegex},
+			lines: map[string]bool{
+				"func (w *DirWalker) Walk(ctx context.Context) error": true,
+				"func Walker() error": false,
+			},
+		},
+		{
+			name:  "word",
+			query: Query{Terms: []string{"test"}, Mode: ModeWord},
+			lines: map[string]bool{
+				"test":            true,
+				"a test, b":       true,
+				"(test)":          true,
+				"testing":         false,
+				"my_test":         false,
+				"attest":          false,
+				"Test":            false,
+				"überprüft test2": false,
+			},
+		},
+		{
+			name:  "word ignore case",
+			query: Query{Terms: []string{"test"}, Mode: ModeWord, IgnoreCase: true},
+			lines: map[string]bool{"Run TEST now": true, "TESTS": false},
+		},
+		{
+			name:  "and",
+			query: Query{Terms: []string{"ctx", "error"}},
+			lines: map[string]bool{"ctx error": true, "ctx": false, "error": false},
+		},
+		{
+			name:  "or",
+			query: Query{Terms: []string{"ctx", "error"}, Operator: OperatorOr},
+			lines: map[string]bool{"ctx error": true, "ctx": true, "error": true, "nil": false},
+		},
+		{
+			name: "or with regex and ignore case",
+			query: Query{
+				Terms:      []string{"^package", "^import"},
+				Mode:       ModeRegex,
+				IgnoreCase: true,
+				Operator:   OperatorOr,
+			},
+			lines: map[string]bool{
+				"Package main":   true,
+				"import \"fmt\"": true,
+				"// package":     false,
+			},
+		},
+		{
+			name:  "no terms",
+			query: Query{},
+			lines: map[string]bool{"anything": true, "": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.query)
+			assert.NoError(t, err)
+			for line, want := range tt.lines {
+				assert.Equal(t, want, m.Match(line), "line %q", line)
+			}
+		})
+	}
+}
+
+func TestNewMatcher_InvalidPattern(t *testing.T) {
+	_, err := NewMatcher(Query{Terms: []string{"Walk("}, Mode: ModeRegex})
+	assert.Error(t, err)
+
+	// Literal terms are quoted
+	m, err := NewMatcher(Query{Terms: []string{"Walk("}, IgnoreCase: true})
+	assert.NoError(t, err)
+	assert.True(t, m.Match("w.walk(ctx)"))
+}
+
+func TestParseModeAndOperator(t *testing.T) {
+	mode, err := ParseMode("")
+	assert.NoError(t, err)
+	assert.Equal(t, ModeLiteral, mode)
+	_, err = ParseMode("glob")
+	assert.Error(t, err)
+
+	op, err := ParseOperator("OR")
+	assert.NoError(t, err)
+	assert.Equal(t, OperatorOr, op)
+	_, err = ParseOperator("xor")
+	assert.Error(t, err)
+}
+
+func TestSearchInContent_Context(t *testing.T) {
+	content := "a\nb\nmatch 1\nc\nd\nmatch 2\ne\nf\ng\nh\nmatch 3\ni\nmatch 4\nj"
+
+	tests := []struct {
+		name          string
+		maxMatches    int
+		before, after int
+		expected      []model.SearchMatch
+		truncated     bool
+	}{
+		{
+			name:       "overlapping windows are merged",
+			maxMatches: 10,
+			before:     2,
+			after:      1,
+			expected: []model.SearchMatch{
+				{LineNo: 3, Text: "match 1", Before: []string{"a", "b"}, After: []string{"c"}},
+				{LineNo: 6, Text: "match 2", Before: []string{"d"}, After: []string{"e"}},
+				{LineNo: 11, Text: "match 3", Before: []string{"g", "h"}, After: []string{"i"}},
+				{LineNo: 13, Text: "match 4", After: []string{"j"}},
+			},
+		},
+		{
+			name:       "after context stops at the next match",
+			maxMatches: 10,
+			after:      3,
+			expected: []model.SearchMatch{
+				{LineNo: 3, Text: "match 1", After: []string{"c", "d"}},
+				{LineNo: 6, Text: "match 2", After: []string{"e", "f", "g"}},
+				{LineNo: 11, Text: "match 3", After: []string{"i"}},
+				{LineNo: 13, Text: "match 4", After: []string{"j"}},
+			},
+		},
+		{
+			name:       "trailing context after the last match",
+			maxMatches: 3,
+			after:      2,
+			expected: []model.SearchMatch{
+				{LineNo: 3, Text: "match 1", After: []string{"c", "d"}},
+				{LineNo: 6, Text: "match 2", After: []string{"e", "f"}},
+				{LineNo: 11, Text: "match 3", After: []string{"i", "match 4"}},
+			},
+			truncated: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matches, truncated, err := SearchInContent(
+				strings.NewReader(content), literal(t, "match"), tt.maxMatches, tt.before, tt.after)
+			assert.NoError(t, err)
+			for i := range matches {
+				matches[i].Column, matches[i].EndColumn = 0, 0
+			}
+			assert.Equal(t, tt.expected, matches)
+			assert.Equal(t, tt.truncated, truncated)
+		})
+	}
+}
+
+func TestMatcher_Find(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      Query
+		line       string
+		start, end int
+	}{
+		{"literal", Query{Terms: []string{"ctx"}}, "f(ctx, ctx)", 2, 5},
+		{"leftmost term", Query{Terms: []string{"err", "ctx"}}, "f(ctx) err", 2, 5},
+		{
+			"or skips missing terms",
+			Query{Terms: []string{"nil", "err"}, Operator: OperatorOr},
+			"return err",
+			7,
+			10,
+		},
+		{"ignore case", Query{Terms: []string{"CTX"}, IgnoreCase: true}, "f(ctx)", 2, 5},
+		{"word excludes boundaries", Query{Terms: []string{"b"}, Mode: ModeWord}, "ab b c", 3, 4},
+		{"regex", Query{Terms: []string{`W\w+`}, Mode: ModeRegex}, "w.Walk()", 2, 6},
+		{"regex with groups", Query{Terms: []string{`(a)(b)`}, Mode: ModeRegex}, "xab", 1, 3},
+		{"no terms", Query{}, "anything", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewMatcher(tt.query)
+			assert.NoError(t, err)
+			start, end, ok := m.Find(tt.line)
+			assert.True(t, ok)
+			assert.Equal(t, tt.start, start)
+			assert.Equal(t, tt.end, end)
+		})
+	}
+
+	// A term that is invalid alone must not become valid once wrapped in a group
+	_, err := NewMatcher(Query{Terms: []string{"a)(b"}, Mode: ModeRegex})
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+package infra
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DiskCacheEntry is a cached document together with the HTTP validators
+// needed to revalidate it.
+type DiskCacheEntry struct {
+	Key          string          `json:"key"`
+	URL          string          `json:"url"`     // Where Data was fetched from
+	Version      int             `json:"version"` // Format of Data, set by the writer
+	FetchedAt    time.Time       `json:"fetched_at"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// DiskCacheStats summarizes the entries of a disk cache.
+type DiskCacheStats struct {
+	Entries int
+	Bytes   int64
+	Kinds   map[string]int // Entry count by key prefix (e.g. "godoc", "pydoc")
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// DiskCache stores entries as one JSON file per key in a directory.
+type DiskCache struct {
+	dir string
+}
+
+// DefaultCacheDir returns $XDG_CACHE_HOME/godevmcp, falling back to
+// the user cache directory of the platform.
+func DefaultCacheDir() string {
+	base := os.Getenv("XDG_CACHE_HOME")
+	if base == "" {
+		var err error
+		if base, err = os.UserCacheDir(); err != nil {
+			base = os.TempDir()
+		}
+	}
+	return filepath.Join(base, "godevmcp")
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, errors.Wrap(err, "failed to create cache directory")
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+// Dir returns the directory of the cache.
+func (c *DiskCache) Dir() string {
+	return c.dir
+}
+
+// Get returns the entry stored for key, or false if there is none or it is unreadable.
+func (c *DiskCache) Get(key string) (*DiskCacheEntry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry DiskCacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		return nil, false
+	}
+	return &entry, true
+}
+
+// Put stores entry, replacing any entry with the same key.
+func (c *DiskCache) Put(entry *DiskCacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode cache entry")
+	}
+
+	// Write to a temporary file first so that readers never see partial entries
+	tmp, err := os.CreateTemp(c.dir, ".tmp-*")
+	if err != nil {
+		return errors.Wrap(err, "failed to create cache file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "failed to write cache file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "failed to write cache file")
+	}
+	return errors.Wrap(os.Rename(tmp.Name(), c.path(entry.Key)), "failed to write cache file")
+}
+
+// Stats reads all entries and summarizes them.
+func (c *DiskCache) Stats() (*DiskCacheStats, error) {
+	stats := &DiskCacheStats{Kinds: make(map[string]int)}
+	err := c.each(func(path string, info os.FileInfo) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil // removed concurrently
+		}
+		var entry DiskCacheEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil // ignore corrupt entries
+		}
+
+		stats.Entries++
+		stats.Bytes += info.Size()
+		kind, _, _ := strings.Cut(entry.Key, ":")
+		stats.Kinds[kind]++
+		if stats.Oldest.IsZero() || entry.FetchedAt.Before(stats.Oldest) {
+			stats.Oldest = entry.FetchedAt
+		}
+		if entry.FetchedAt.After(stats.Newest) {
+			stats.Newest = entry.FetchedAt
+		}
+		return nil
+	})
+	return stats, err
+}
+
+// Clear removes all entries and returns how many were removed.
+func (c *DiskCache) Clear() (int, error) {
+	removed := 0
+	err := c.each(func(path string, _ os.FileInfo) error {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to remove cache file")
+		}
+		removed++
+		return nil
+	})
+	return removed, err
+}
+
+func (c *DiskCache) each(fn func(path string, info os.FileInfo) error) error {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read cache directory")
+	}
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		if err := fn(filepath.Join(c.dir, e.Name()), info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// path maps a key to a file name. Keys contain slashes and colons,
+// so they are hashed.
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,60 @@
+package infra
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDiskCache(t *testing.T) {
+	dc, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewDiskCache() error = %v", err)
+	}
+
+	if _, ok := dc.Get("godoc:net/http"); ok {
+		t.Fatal("Get() on empty cache returned an entry")
+	}
+
+	fetchedAt := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, key := range []string{"godoc:net/http", "godoc:io/fs", "pydoc:json"} {
+		entry := &DiskCacheEntry{
+			Key:       key,
+			Version:   1,
+			FetchedAt: fetchedAt,
+			ETag:      `"abc"`,
+			Data:      json.RawMessage(`{"Text":"doc"}`),
+		}
+		if err := dc.Put(entry); err != nil {
+			t.Fatalf("Put(%s) error = %v", key, err)
+		}
+	}
+
+	entry, ok := dc.Get("godoc:net/http")
+	if !ok {
+		t.Fatal("Get() did not return the stored entry")
+	}
+	if entry.ETag != `"abc"` || !entry.FetchedAt.Equal(fetchedAt) ||
+		string(entry.Data) != `{"Text":"doc"}` {
+		t.Errorf("Get() = %+v", entry)
+	}
+
+	stats, err := dc.Stats()
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	if stats.Entries != 3 || stats.Kinds["godoc"] != 2 || stats.Kinds["pydoc"] != 1 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+
+	removed, err := dc.Clear()
+	if err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("Clear() removed %d entries, want 3", removed)
+	}
+	if _, ok := dc.Get("pydoc:json"); ok {
+		t.Error("Get() returned an entry after Clear()")
+	}
+}
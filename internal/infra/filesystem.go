@@ -4,8 +4,10 @@ import (
 	"context"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/fpt/go-dev-mcp/internal/repository"
 	"github.com/pkg/errors"
 )
@@ -15,41 +17,44 @@ func IsFileExist(workdir, filename string) bool {
 	return err == nil
 }
 
-type DirWalker struct{}
+type DirWalker struct {
+	useIgnoreFiles bool
+}
 
+// NewDirWalker creates a walker skipping the files ignored by .gitignore and
+// .godevmcpignore files.
 func NewDirWalker() repository.DirWalker {
-	return &DirWalker{}
+	return NewDirWalkerWithIgnore(true)
+}
+
+// NewDirWalkerWithIgnore creates a walker skipping ignored files if useIgnoreFiles is set.
+func NewDirWalkerWithIgnore(useIgnoreFiles bool) repository.DirWalker {
+	return &DirWalker{useIgnoreFiles: useIgnoreFiles}
 }
 
 func (dw *DirWalker) Walk(
 	_ context.Context, function repository.WalkDirFunc, prefixFunc repository.WalkDirNextPrefixFunc,
 	prefix, path string, ignoreDot bool, maxDepth int,
 ) error {
-	return dw.walkWithDepth(function, prefixFunc, prefix, path, ignoreDot, maxDepth, 0)
+	var rules ignoreRules
+	if dw.useIgnoreFiles {
+		rules = loadIgnoreRules(path)
+	}
+	return dw.walkWithDepth(function, prefixFunc, prefix, path, ignoreDot, rules, maxDepth, 0)
 }
 
 func (dw *DirWalker) walkWithDepth(
 	function repository.WalkDirFunc, prefixFunc repository.WalkDirNextPrefixFunc,
-	prefix, path string, ignoreDot bool, maxDepth, currentDepth int,
+	prefix, path string, ignoreDot bool, rules ignoreRules, maxDepth, currentDepth int,
 ) error {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return errors.Wrap(err, "failed to read directory")
 	}
-
-	// Filter out .git directory and optionally other dot files/directories
-	filteredEntries := make([]os.DirEntry, 0)
-	for _, entry := range entries {
-		// Always filter out .git directory
-		if entry.IsDir() && entry.Name() == ".git" {
-			continue
-		}
-		// Optionally filter out other dot files/directories
-		if ignoreDot && strings.HasPrefix(entry.Name(), ".") {
-			continue
-		}
-		filteredEntries = append(filteredEntries, entry)
+	if dw.useIgnoreFiles {
+		rules = rules.enter(path)
 	}
+	filteredEntries := filterEntries(path, entries, ignoreDot, rules)
 
 	for i, entry := range filteredEntries {
 		isLastEntry := (i == len(filteredEntries)-1)
@@ -74,6 +79,7 @@ func (dw *DirWalker) walkWithDepth(
 				nextPrefix,
 				subpath,
 				ignoreDot,
+				rules,
 				maxDepth,
 				currentDepth+1,
 			)
@@ -86,31 +92,67 @@ func (dw *DirWalker) walkWithDepth(
 	return nil
 }
 
-type FileWalker struct{}
+type FileWalker struct {
+	useIgnoreFiles bool
+}
 
+// NewFileWalker creates a walker skipping the files ignored by .gitignore and
+// .godevmcpignore files.
 func NewFileWalker() repository.FileWalker {
-	return &FileWalker{}
+	return NewFileWalkerWithIgnore(true)
+}
+
+// NewFileWalkerWithIgnore creates a walker skipping ignored files if useIgnoreFiles is set.
+func NewFileWalkerWithIgnore(useIgnoreFiles bool) repository.FileWalker {
+	return &FileWalker{useIgnoreFiles: useIgnoreFiles}
 }
 
 func (fw *FileWalker) Walk(
-	ctx context.Context, function repository.WalkFileFunc, path, extension string, ignoreDot bool,
+	ctx context.Context,
+	function repository.WalkFileFunc,
+	path string,
+	opts repository.WalkFileOptions,
+) error {
+	filter, err := newFileFilter(opts)
+	if err != nil {
+		return err
+	}
+	var rules ignoreRules
+	if fw.useIgnoreFiles {
+		rules = loadIgnoreRules(path)
+	}
+	return fw.walk(ctx, function, path, "", opts.IgnoreDot, filter, rules)
+}
+
+func (fw *FileWalker) ReadFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+// walk visits the files of the directory at path, whose path relative to the
+// walked directory is rel.
+func (fw *FileWalker) walk(
+	ctx context.Context,
+	function repository.WalkFileFunc,
+	path, rel string,
+	ignoreDot bool,
+	filter *fileFilter,
+	rules ignoreRules,
 ) error {
 	entries, err := os.ReadDir(path)
 	if err != nil {
 		return errors.Wrap(err, "failed to read directory")
 	}
-
-	// Filter out . entry
-	filteredEntries := make([]os.DirEntry, 0)
-	for _, entry := range entries {
-		if !ignoreDot || !strings.HasPrefix(entry.Name(), ".") {
-			filteredEntries = append(filteredEntries, entry)
-		}
+	if fw.useIgnoreFiles {
+		rules = rules.enter(path)
 	}
 
-	for _, entry := range filteredEntries {
+	for _, entry := range filterEntries(path, entries, ignoreDot, rules) {
+		entryRel := entry.Name()
+		if rel != "" {
+			entryRel = rel + "/" + entry.Name()
+		}
 		if !entry.IsDir() {
-			if extension != "" && filepath.Ext(entry.Name()) != extension {
+			if filter.skipFile(entryRel, entry) {
 				continue
 			}
 
@@ -119,8 +161,11 @@ func (fw *FileWalker) Walk(
 				return errors.Wrap(err, "failed to process file: "+filePath)
 			}
 		} else {
+			if filter.skipDir(entryRel) {
+				continue
+			}
 			nextPath := filepath.Join(path, entry.Name())
-			err := fw.Walk(ctx, function, nextPath, extension, ignoreDot)
+			err := fw.walk(ctx, function, nextPath, entryRel, ignoreDot, filter, rules)
 			if err != nil {
 				return errors.Wrap(err, "failed to walk into directory: "+nextPath)
 			}
@@ -129,3 +174,97 @@ func (fw *FileWalker) Walk(
 
 	return nil
 }
+
+// fileFilter selects files by the WalkFileOptions of a walk, matching globs
+// against slash-separated paths relative to the walked directory.
+type fileFilter struct {
+	extensions  []string
+	include     []string
+	exclude     []string
+	maxFileSize int64
+}
+
+func newFileFilter(opts repository.WalkFileOptions) (*fileFilter, error) {
+	filter := &fileFilter{maxFileSize: opts.MaxFileSize}
+	for _, ext := range opts.Extensions {
+		if ext = strings.TrimPrefix(ext, "."); ext != "" {
+			filter.extensions = append(filter.extensions, "."+ext)
+		}
+	}
+	for _, glob := range opts.Include {
+		if exclude, ok := strings.CutPrefix(glob, "!"); ok {
+			filter.exclude = append(filter.exclude, exclude)
+		} else {
+			filter.include = append(filter.include, glob)
+		}
+	}
+	filter.exclude = append(filter.exclude, opts.Exclude...)
+
+	for _, glob := range slices.Concat(filter.include, filter.exclude) {
+		if !doublestar.ValidatePattern(glob) {
+			return nil, errors.Errorf("invalid glob %q", glob)
+		}
+	}
+	return filter, nil
+}
+
+// skipDir reports whether the directory at rel is excluded, with its contents.
+func (f *fileFilter) skipDir(rel string) bool {
+	return matchAnyGlob(f.exclude, rel)
+}
+
+// skipFile reports whether the file at rel is filtered out.
+func (f *fileFilter) skipFile(rel string, entry os.DirEntry) bool {
+	if len(f.extensions) > 0 && !slices.Contains(f.extensions, filepath.Ext(entry.Name())) {
+		return true
+	}
+	if len(f.include) > 0 && !matchAnyGlob(f.include, rel) {
+		return true
+	}
+	if matchAnyGlob(f.exclude, rel) {
+		return true
+	}
+	if f.maxFileSize > 0 {
+		info, err := entry.Info()
+		if err != nil || info.Size() > f.maxFileSize {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAnyGlob(globs []string, name string) bool {
+	for _, glob := range globs {
+		// Patterns are validated by newFileFilter
+		if ok, _ := doublestar.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// filterEntries filters out the .git directory, ignored entries and
+// optionally other dot files/directories.
+func filterEntries(
+	dir string,
+	entries []os.DirEntry,
+	ignoreDot bool,
+	rules ignoreRules,
+) []os.DirEntry {
+	filteredEntries := make([]os.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		// Always filter out .git directory
+		if entry.IsDir() && entry.Name() == ".git" {
+			continue
+		}
+		// Optionally filter out other dot files/directories
+		if ignoreDot && strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if rules.ignored(filepath.Join(dir, entry.Name()), entry.IsDir()) {
+			continue
+		}
+		filteredEntries = append(filteredEntries, entry)
+	}
+	return filteredEntries
+}
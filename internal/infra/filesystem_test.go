@@ -0,0 +1,177 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+)
+
+func TestFileWalker_Options(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]int{
+		"go.mod":                  10,
+		"main.go":                 10,
+		"main_test.go":            10,
+		"README.md":               10,
+		"cmd/tool/tool.go":        10,
+		"cmd/tool/big.go":         1000,
+		"internal/x/x.go":         10,
+		"internal/x/x.pb.go":      10,
+		"internal/x/testdata/a":   10,
+		"web/app.ts":              10,
+		"web/app.tsx":             10,
+		"web/testdata/fixture.ts": 10,
+	}
+	fsys := fstest.MapFS{}
+	for name, size := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(strings.Repeat("x", size))}
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(strings.Repeat("x", size)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts repository.WalkFileOptions
+		want []string
+	}{
+		{
+			name: "extensions with and without dot",
+			opts: repository.WalkFileOptions{Extensions: []string{"ts", ".tsx"}},
+			want: []string{"web/app.ts", "web/app.tsx", "web/testdata/fixture.ts"},
+		},
+		{
+			name: "include with braces and negation",
+			opts: repository.WalkFileOptions{Include: []string{"*.{go,mod}", "!**/*_test.go"}},
+			want: []string{"go.mod", "main.go"},
+		},
+		{
+			name: "exclude directories and files",
+			opts: repository.WalkFileOptions{
+				Extensions: []string{".go"},
+				Exclude:    []string{"**/*_test.go", "**/*.pb.go", "cmd"},
+			},
+			want: []string{"internal/x/x.go", "main.go"},
+		},
+		{
+			name: "include and exclude",
+			opts: repository.WalkFileOptions{
+				Include: []string{"**/*.ts"},
+				Exclude: []string{"**/testdata"},
+			},
+			want: []string{"web/app.ts"},
+		},
+		{
+			name: "max file size",
+			opts: repository.WalkFileOptions{Include: []string{"cmd/**"}, MaxFileSize: 100},
+			want: []string{"cmd/tool/tool.go"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := walkFiles(t, NewFileWalker(), root, tt.opts)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got files %v, want %v", got, tt.want)
+			}
+			got = walkFiles(
+				t,
+				NewFSFileWalker(fsys, "example.com/m@v1.0.0"),
+				"example.com/m@v1.0.0",
+				tt.opts,
+			)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got files %v in file system, want %v", got, tt.want)
+			}
+		})
+	}
+
+	err := NewFileWalker().Walk(t.Context(), func(string) error { return nil }, root,
+		repository.WalkFileOptions{Exclude: []string{"[a-"}})
+	if err == nil {
+		t.Error("expected error for an invalid glob")
+	}
+}
+
+func TestFSFileWalker(t *testing.T) {
+	fsys := fstest.MapFS{
+		"go.mod":         {Data: []byte("module example.com/m\n")},
+		".github/ci.yml": {},
+		".git/HEAD":      {},
+		"pkg/pkg.go":     {Data: []byte("package pkg\n")},
+		"pkg/sub/sub.go": {},
+		"pkg/.hidden.go": {},
+		"other/other.go": {},
+	}
+	fw := NewFSFileWalker(fsys, "example.com/m@v1.0.0")
+
+	got := walkFiles(t, fw, "example.com/m@v1.0.0", repository.WalkFileOptions{})
+	want := []string{
+		".github/ci.yml",
+		"go.mod",
+		"other/other.go",
+		"pkg/.hidden.go",
+		"pkg/pkg.go",
+		"pkg/sub/sub.go",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+	got = walkFiles(t, fw, "example.com/m@v1.0.0/pkg", repository.WalkFileOptions{IgnoreDot: true})
+	if want := []string{"pkg.go", "sub/sub.go"}; !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	content, err := fw.ReadFile("example.com/m@v1.0.0/pkg/pkg.go")
+	if err != nil || string(content) != "package pkg\n" {
+		t.Errorf("got content %q, %v", content, err)
+	}
+	for _, path := range []string{"example.com/other@v1.0.0/pkg/pkg.go", "example.com/m@v1.0.0/../x"} {
+		if _, err := fw.ReadFile(path); err == nil {
+			t.Errorf("expected error reading %s", path)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	err = fw.Walk(ctx, func(string) error { return nil }, "example.com/m@v1.0.0",
+		repository.WalkFileOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+// walkFiles returns the paths of the files fw visits, relative to root.
+func walkFiles(
+	t *testing.T,
+	fw repository.FileWalker,
+	root string,
+	opts repository.WalkFileOptions,
+) []string {
+	t.Helper()
+	var files []string
+	err := fw.Walk(t.Context(), func(path string) error {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return nil
+	}, root, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(files)
+	return files
+}
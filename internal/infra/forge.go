@@ -0,0 +1,533 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// Kinds of forges.
+const (
+	ForgeGitHub = "github"
+	ForgeGitLab = "gitlab"
+	ForgeGitea  = "gitea"
+)
+
+// Environment variables holding the tokens of GitLab and Gitea hosts.
+const (
+	ForgeAuthEnvGitLab = "GITLAB_TOKEN"
+	ForgeAuthEnvGitea  = "GITEA_TOKEN"
+)
+
+// ForgeClient is a client of a forge host together with its credentials.
+type ForgeClient interface {
+	repository.Forge
+	// Kind returns the kind of the forge (one of the Forge* constants).
+	Kind() string
+	// AuthSource returns where the credentials of the client came from
+	// (one of the GitHubAuth* constants, or an environment variable).
+	AuthSource() string
+	// Anonymous reports whether the client has no credentials.
+	Anonymous() bool
+}
+
+func (c *GitHubClient) Kind() string {
+	return ForgeGitHub
+}
+
+// ForgeHost configures a GitLab or Gitea host.
+type ForgeHost struct {
+	Kind   string // ForgeGitLab or ForgeGitea
+	Host   string // e.g. "gitlab.example.com"
+	APIURL string // Defaults to https://<host>/api/v4 (GitLab) or https://<host>/api/v1 (Gitea)
+	Token  string // Takes precedence over the credential chain if set
+}
+
+// defaultForgeHosts are the public GitLab and Gitea (Forgejo) hosts, which
+// are available without configuration.
+var defaultForgeHosts = []ForgeHost{
+	{Kind: ForgeGitLab, Host: "gitlab.com"},
+	{Kind: ForgeGitea, Host: "codeberg.org"},
+}
+
+// DefaultForgeHosts returns the hosts NewForges configures unless they are
+// configured explicitly.
+func DefaultForgeHosts() []ForgeHost {
+	return slices.Clone(defaultForgeHosts)
+}
+
+// ForgeConfig configures NewForges.
+type ForgeConfig struct {
+	GitHub GitHubConfig
+	Hosts  []ForgeHost // GitLab and Gitea hosts
+}
+
+// Forges holds the clients of all configured hosts: the GitHub clients, whose
+// methods it inherits for tools only GitHub supports, and GitLab and Gitea clients.
+type Forges struct {
+	*GitHubClients
+
+	mu     sync.Mutex
+	others map[string]*lazyForgeClient
+	hosts  []string // All hosts, GitHub hosts first
+}
+
+// lazyForgeClient creates a GitLab or Gitea client on first use, so that
+// credentials of hosts that are never used are not looked up.
+type lazyForgeClient struct {
+	host   ForgeHost
+	client ForgeClient
+}
+
+// NewForges creates the GitHub clients and prepares clients of the GitLab and
+// Gitea hosts, including gitlab.com and codeberg.org.
+func NewForges(ctx context.Context, cfg ForgeConfig) (*Forges, error) {
+	gh, err := NewGitHubClients(ctx, cfg.GitHub)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &Forges{
+		GitHubClients: gh,
+		others:        make(map[string]*lazyForgeClient),
+		hosts:         slices.Clone(gh.Hosts()),
+	}
+	hosts := cfg.Hosts
+	for _, host := range defaultForgeHosts {
+		if !slices.ContainsFunc(hosts, func(h ForgeHost) bool { return h.Host == host.Host }) {
+			hosts = append(hosts, host)
+		}
+	}
+	for _, host := range hosts {
+		switch {
+		case host.Host == "":
+			return nil, errors.New("forge host name is empty")
+		case host.Kind != ForgeGitLab && host.Kind != ForgeGitea:
+			return nil, errors.Errorf("unknown kind %q of forge host %s", host.Kind, host.Host)
+		case slices.Contains(f.hosts, host.Host):
+			return nil, errors.Errorf("forge host %s is configured twice", host.Host)
+		}
+		if _, err := forgeAPIURL(host); err != nil {
+			return nil, err
+		}
+		f.others[host.Host] = &lazyForgeClient{host: host}
+		f.hosts = append(f.hosts, host.Host)
+	}
+	return f, nil
+}
+
+// Hosts returns all configured hosts, GitHub hosts first.
+func (f *Forges) Hosts() []string {
+	return f.hosts
+}
+
+// Forge returns the client of host, which may be a GitHub, GitLab or Gitea host,
+// or of github.com if host is empty.
+func (f *Forges) Forge(ctx context.Context, host string) (ForgeClient, error) {
+	if lazy, ok := f.others[host]; ok {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if lazy.client == nil {
+			lazy.client = newForgeClient(ctx, lazy.host)
+			slog.InfoContext(ctx, "forge credentials",
+				"host", host, "kind", lazy.host.Kind, "source", lazy.client.AuthSource())
+		}
+		return lazy.client, nil
+	}
+	if gh, err := f.GitHubClients.Client(host); err == nil {
+		return gh, nil
+	}
+	return nil, errors.Errorf("host %s is not configured (configured hosts: %s)",
+		host, strings.Join(f.hosts, ", "))
+}
+
+// Client returns the GitHub client of host, or of github.com if host is empty.
+// It fails for GitLab and Gitea hosts.
+func (f *Forges) Client(host string) (*GitHubClient, error) {
+	if lazy, ok := f.others[host]; ok {
+		return nil, errors.Errorf("%s is a %s host, but only GitHub supports this",
+			host, lazy.host.Kind)
+	}
+	return f.GitHubClients.Client(host)
+}
+
+func newForgeClient(ctx context.Context, host ForgeHost) ForgeClient {
+	env := ForgeAuthEnvGitLab
+	if host.Kind == ForgeGitea {
+		env = ForgeAuthEnvGitea
+	}
+	cred := resolveForgeCredential(ctx, host.Host, env, host.Token)
+
+	apiURL, _ := forgeAPIURL(host) // Validated by NewForges
+	api := &forgeAPI{
+		httpcli:    NewHttpClient(),
+		host:       host.Host,
+		baseURL:    apiURL,
+		header:     http.Header{},
+		authSource: cred.Source,
+	}
+	if host.Kind == ForgeGitea {
+		if cred.Token != "" {
+			api.header.Set("Authorization", "token "+cred.Token)
+		}
+		return &GiteaClient{forgeAPI: api}
+	}
+	if cred.Token != "" {
+		api.header.Set("Authorization", "Bearer "+cred.Token)
+	}
+	return &GitLabClient{forgeAPI: api}
+}
+
+// forgeAPIURL returns the base URL of the REST API of a GitLab or Gitea host, without a trailing slash.
+func forgeAPIURL(host ForgeHost) (string, error) {
+	apiURL := host.APIURL
+	if apiURL == "" {
+		apiURL = "https://" + host.Host + "/api/v4"
+		if host.Kind == ForgeGitea {
+			apiURL = "https://" + host.Host + "/api/v1"
+		}
+	}
+	u, err := url.Parse(apiURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", errors.Errorf("invalid API URL of forge host %s: %q", host.Host, host.APIURL)
+	}
+	return strings.TrimSuffix(apiURL, "/"), nil
+}
+
+// resolveForgeCredential returns the first token for host found in the chain:
+// the explicit token, the environment variable env, the git credential helper.
+func resolveForgeCredential(
+	ctx context.Context,
+	host, env, explicitToken string,
+) GitHubCredential {
+	if token := strings.TrimSpace(explicitToken); token != "" {
+		return GitHubCredential{Token: token, Source: GitHubAuthExplicit}
+	}
+	if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+		return GitHubCredential{Token: token, Source: env}
+	}
+	if token := gitCredentialToken(ctx, host); token != "" {
+		return GitHubCredential{Token: token, Source: GitHubAuthGitCredential}
+	}
+	return GitHubCredential{Source: GitHubAuthAnonymous}
+}
+
+// forgeAPI makes requests to the REST API of a GitLab or Gitea host.
+type forgeAPI struct {
+	httpcli    *HttpClient
+	host       string
+	baseURL    string
+	header     http.Header // Credentials, if any
+	authSource string
+}
+
+// Host returns the host the client talks to.
+func (a *forgeAPI) Host() string {
+	return a.host
+}
+
+// AuthSource returns where the credentials of the client came from.
+func (a *forgeAPI) AuthSource() string {
+	return a.authSource
+}
+
+// Anonymous reports whether the client has no credentials.
+func (a *forgeAPI) Anonymous() bool {
+	return a.authSource == GitHubAuthAnonymous
+}
+
+// get makes a GET request of path, relative to the API base URL, and returns
+// the response body and headers.
+func (a *forgeAPI) get(
+	ctx context.Context,
+	path string,
+	query url.Values,
+) ([]byte, http.Header, error) {
+	u := a.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	body, header, err := a.httpcli.HttpGetWithHeader(ctx, u, a.header)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to read response")
+	}
+	return data, header, nil
+}
+
+// getJSON makes a GET request like get and decodes the JSON response into v.
+func (a *forgeAPI) getJSON(
+	ctx context.Context,
+	path string,
+	query url.Values,
+	v any,
+) (http.Header, error) {
+	data, header, err := a.get(ctx, path, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, errors.Wrap(err, "failed to decode response")
+	}
+	return header, nil
+}
+
+// formatDirListing formats the entries of a directory like GitHubClient.GetContent.
+func formatDirListing(path string, entries []repository.TreeEntry) string {
+	var sb strings.Builder
+	sb.WriteString("Directory: " + path + "\nContents:\n")
+	for _, entry := range entries {
+		name := entry.Path[strings.LastIndex(entry.Path, "/")+1:]
+		if entry.IsDir {
+			sb.WriteString("- " + name + "/ (" + ItemTypeDir + ")\n")
+		} else {
+			sb.WriteString("- " + name + " (file)\n")
+		}
+	}
+	return sb.String()
+}
+
+// ParseRepo splits a repository argument in "owner/repo" or "host/owner/repo"
+// format. Further parts are nested GitLab groups: "host/group/subgroup/repo"
+// has the owner "group/subgroup". The host is empty if not given.
+func ParseRepo(arg string) (host, owner, repo string, err error) {
+	parts := strings.Split(strings.TrimSuffix(arg, "/"), "/")
+	if len(parts) < 2 || slices.Contains(parts, "") {
+		return "", "", "", errors.Errorf(
+			"invalid repo format %q, expected 'owner/repo' or 'host/owner/repo'", arg)
+	}
+	if len(parts) == 2 {
+		return "", parts[0], parts[1], nil
+	}
+	last := len(parts) - 1
+	return parts[0], strings.Join(parts[1:last], "/"), parts[last], nil
+}
+
+// ForgeDirWalker implements the repository.DirWalker interface for repositories of a forge.
+type ForgeDirWalker struct {
+	forge  repository.Forge
+	owner  string
+	repo   string
+	commit string
+}
+
+// NewForgeDirWalker creates a new ForgeDirWalker instance walking the tree
+// of commit, a commit SHA as returned by repository.Forge.ResolveRef.
+func NewForgeDirWalker(
+	forge repository.Forge,
+	owner, repo, commit string,
+) repository.DirWalker {
+	return &ForgeDirWalker{
+		forge:  forge,
+		owner:  owner,
+		repo:   repo,
+		commit: commit,
+	}
+}
+
+// Walk implements the repository.DirWalker interface for forge repositories.
+// The whole tree is listed at once if the forge can. Only if the tree is too
+// large, directories are listed one call at a time.
+func (w *ForgeDirWalker) Walk(
+	ctx context.Context,
+	function repository.WalkDirFunc,
+	prefixFunc repository.WalkDirNextPrefixFunc,
+	prefix, path string,
+	ignoreDot bool,
+	maxDepth int,
+) error {
+	root, err := w.fetchTree(ctx, path)
+	if err != nil {
+		return err
+	}
+	if root == nil {
+		slog.DebugContext(ctx, "tree truncated, listing directories one by one",
+			"host", w.forge.Host(), "owner", w.owner, "repo", w.repo)
+		return w.walkWithDepth(ctx, function, prefixFunc, prefix, path, ignoreDot, maxDepth, 0)
+	}
+	return walkGitTree(root, function, prefixFunc, prefix, ignoreDot, maxDepth, 0)
+}
+
+// gitTreeNode is a file or directory of a tree built from a recursive tree listing.
+type gitTreeNode struct {
+	name     string
+	isDir    bool
+	children []*gitTreeNode // Sorted by name
+}
+
+// fetchTree lists the tree of the commit and returns the node of path,
+// or nil if the listing was truncated.
+func (w *ForgeDirWalker) fetchTree(ctx context.Context, path string) (*gitTreeNode, error) {
+	entries, truncated, err := w.forge.ListTree(ctx, w.owner, w.repo, w.commit)
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return nil, nil
+	}
+
+	root := buildGitTree(entries)
+	node := root
+	if path = strings.Trim(path, "/"); path != "" {
+		for name := range strings.SplitSeq(path, "/") {
+			node = node.child(name)
+			if node == nil {
+				return nil, errors.Errorf("path %s not found", path)
+			}
+		}
+	}
+	return node, nil
+}
+
+// buildGitTree builds the hierarchy of the entries of a recursive tree.
+func buildGitTree(entries []repository.TreeEntry) *gitTreeNode {
+	// Sorting by path puts parents first and siblings in order of their names
+	entries = slices.Clone(entries)
+	slices.SortFunc(entries, func(a, b repository.TreeEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	root := &gitTreeNode{isDir: true}
+	dirs := map[string]*gitTreeNode{"": root}
+	for _, entry := range entries {
+		parentPath, name := "", entry.Path
+		if i := strings.LastIndex(entry.Path, "/"); i >= 0 {
+			parentPath, name = entry.Path[:i], entry.Path[i+1:]
+		}
+		parent, ok := dirs[parentPath]
+		if !ok {
+			continue
+		}
+		node := &gitTreeNode{name: name, isDir: entry.IsDir}
+		parent.children = append(parent.children, node)
+		if node.isDir {
+			dirs[entry.Path] = node
+		}
+	}
+	return root
+}
+
+func (n *gitTreeNode) child(name string) *gitTreeNode {
+	for _, c := range n.children {
+		if c.name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// walkGitTree walks a tree like walkWithDepth walks directory listings.
+func walkGitTree(
+	node *gitTreeNode,
+	function repository.WalkDirFunc,
+	prefixFunc repository.WalkDirNextPrefixFunc,
+	prefix string,
+	ignoreDot bool,
+	maxDepth int,
+	currentDepth int,
+) error {
+	children := make([]*gitTreeNode, 0, len(node.children))
+	for _, c := range node.children {
+		// Always filter out .git directory, optionally other dot files/directories
+		if c.name == ".git" || (ignoreDot && strings.HasPrefix(c.name, ".")) {
+			continue
+		}
+		children = append(children, c)
+	}
+
+	for i, c := range children {
+		isLastEntry := i == len(children)-1
+		if err := function(c.name, prefix, isLastEntry); err != nil {
+			return err
+		}
+		if !c.isDir || currentDepth >= maxDepth {
+			continue
+		}
+		nextPrefix := prefixFunc(prefix, isLastEntry)
+		if err := walkGitTree(
+			c, function, prefixFunc, nextPrefix, ignoreDot, maxDepth, currentDepth+1,
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *ForgeDirWalker) walkWithDepth(
+	ctx context.Context,
+	function repository.WalkDirFunc,
+	prefixFunc repository.WalkDirNextPrefixFunc,
+	prefix, path string,
+	ignoreDot bool,
+	maxDepth int,
+	currentDepth int,
+) error {
+	// Get contents of the directory
+	entries, err := w.forge.ListDir(ctx, w.owner, w.repo, w.commit, path)
+	if err != nil {
+		return err
+	}
+
+	// Filter directory entries first
+	filtered := make([]repository.TreeEntry, 0, len(entries))
+	for _, entry := range entries {
+		name := entry.Path[strings.LastIndex(entry.Path, "/")+1:]
+
+		// Always filter out .git directory
+		if name == ".git" {
+			continue
+		}
+		// Optionally filter out other dot files/directories
+		if ignoreDot && strings.HasPrefix(name, ".") {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+	slices.SortStableFunc(filtered, func(a, b repository.TreeEntry) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	// Process filtered directory entries
+	for i, entry := range filtered {
+		isLastEntry := (i == len(filtered)-1)
+		name := entry.Path[strings.LastIndex(entry.Path, "/")+1:]
+
+		// Call the function for this entry
+		if err := function(name, prefix, isLastEntry); err != nil {
+			return err
+		}
+
+		// If it's a directory, recursively walk it
+		if entry.IsDir {
+			// Check if we've reached the max depth
+			if currentDepth >= maxDepth {
+				continue
+			}
+
+			nextPrefix := prefixFunc(prefix, isLastEntry)
+			if err := w.walkWithDepth(
+				ctx, function, prefixFunc, nextPrefix, entry.Path, ignoreDot, maxDepth,
+				currentDepth+1,
+			); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
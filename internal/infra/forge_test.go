@@ -0,0 +1,207 @@
+package infra
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestForges_GitLab(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv(ForgeAuthEnvGitLab, "")
+
+	const project = "/api/v4/projects/group%2Fsub%2Frepo"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer gl-token" {
+			http.Error(w, "bad credentials: "+got, http.StatusUnauthorized)
+			return
+		}
+		query := r.URL.Query()
+		switch r.URL.EscapedPath() {
+		case project:
+			fmt.Fprint(w, `{"default_branch":"main"}`)
+		case project + "/repository/commits/main":
+			fmt.Fprint(w, `{"id":"c1"}`)
+		case project + "/repository/files/docs%2FREADME.md/raw":
+			if query.Get("ref") != "c1" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, "# Docs\n")
+		case project + "/repository/tree":
+			switch {
+			case query.Get("recursive") == "true" && query.Get("page") == "1":
+				w.Header().Set("X-Next-Page", "2")
+				fmt.Fprint(w, `[{"name":"docs","type":"tree","path":"docs"},
+					{"name":"README.md","type":"blob","path":"docs/README.md"}]`)
+			case query.Get("recursive") == "true":
+				fmt.Fprint(w, `[{"name":"go.mod","type":"blob","path":"go.mod"}]`)
+			case query.Get("path") == "docs":
+				fmt.Fprint(w, `[{"name":"README.md","type":"blob","path":"docs/README.md"}]`)
+			default:
+				fmt.Fprint(w, `[]`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	forges, err := NewForges(ctx, ForgeConfig{Hosts: []ForgeHost{
+		{Kind: ForgeGitLab, Host: "gitlab.test", APIURL: srv.URL + "/api/v4", Token: "gl-token"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := forges.Client("gitlab.test"); err == nil {
+		t.Error("expected error for a GitHub client of a GitLab host")
+	}
+	if _, err := forges.Forge(ctx, "unknown.test"); err == nil {
+		t.Error("expected error for an unconfigured host")
+	}
+
+	gl, err := forges.Forge(ctx, "gitlab.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gl.Kind() != ForgeGitLab || gl.AuthSource() != GitHubAuthExplicit {
+		t.Errorf("got %s client with credentials from %q", gl.Kind(), gl.AuthSource())
+	}
+
+	commit, err := gl.ResolveRef(ctx, "group/sub", "repo", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit != "c1" {
+		t.Errorf("got commit %q, want c1", commit)
+	}
+
+	content, err := gl.GetContent(ctx, "group/sub", "repo", "docs/README.md", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "# Docs\n" {
+		t.Errorf("got content %q", content)
+	}
+
+	listing, err := gl.GetContent(ctx, "group/sub", "repo", "docs", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Directory: docs\nContents:\n- README.md (file)\n"; listing != want {
+		t.Errorf("got listing %q, want %q", listing, want)
+	}
+
+	var b strings.Builder
+	walker := NewForgeDirWalker(gl, "group/sub", "repo", commit)
+	err = walker.Walk(ctx,
+		func(name, prefix string, isLastEntry bool) error {
+			fmt.Fprintf(&b, "%s%s\n", prefix, name)
+			return nil
+		},
+		func(prefix string, isLastEntry bool) string { return prefix + "  " },
+		"", "", false, 2,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "docs\n  README.md\ngo.mod\n"; b.String() != want {
+		t.Errorf("got tree\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestForges_Gitea(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv(ForgeAuthEnvGitea, "gitea-token")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token gitea-token" {
+			http.Error(w, "bad credentials: "+got, http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/api/v1/repos/o/r/commits":
+			if r.URL.Query().Get("sha") != "v1.0.0" {
+				http.NotFound(w, r)
+				return
+			}
+			fmt.Fprint(w, `[{"sha":"c2"}]`)
+		case "/api/v1/repos/o/r/contents/main.go":
+			content := base64.StdEncoding.EncodeToString([]byte("package main\n"))
+			fmt.Fprintf(w, `{"name":"main.go","path":"main.go","type":"file",`+
+				`"encoding":"base64","content":%q}`, content)
+		case "/api/v1/repos/o/r/contents":
+			fmt.Fprint(w, `[{"name":"cmd","path":"cmd","type":"dir"},`+
+				`{"name":"main.go","path":"main.go","type":"file"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	forges, err := NewForges(ctx, ForgeConfig{Hosts: []ForgeHost{
+		{Kind: ForgeGitea, Host: "gitea.test", APIURL: srv.URL + "/api/v1/"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gitea, err := forges.Forge(ctx, "gitea.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gitea.AuthSource() != ForgeAuthEnvGitea {
+		t.Errorf("got credentials from %q, want %s", gitea.AuthSource(), ForgeAuthEnvGitea)
+	}
+
+	commit, err := gitea.ResolveRef(ctx, "o", "r", "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit != "c2" {
+		t.Errorf("got commit %q, want c2", commit)
+	}
+
+	content, err := gitea.GetContent(ctx, "o", "r", "main.go", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "package main\n" {
+		t.Errorf("got content %q", content)
+	}
+
+	listing, err := gitea.GetContent(ctx, "o", "r", "", commit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Directory: \nContents:\n- cmd/ (dir)\n- main.go (file)\n"; listing != want {
+		t.Errorf("got listing %q, want %q", listing, want)
+	}
+
+	if _, err := gitea.SearchCode(ctx, "main", nil); err == nil {
+		t.Error("expected error for code search on Gitea")
+	}
+}
+
+func TestForges_DefaultHosts(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	forges, err := NewForges(context.Background(), ForgeConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"gitlab.com/group/sub/repo", "codeberg.org/owner/repo"} {
+		host, _, _, err := ParseRepo(arg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := forges.Forge(context.Background(), host); err != nil {
+			t.Errorf("%s: %v", arg, err)
+		}
+	}
+}
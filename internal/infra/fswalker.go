@@ -0,0 +1,102 @@
+package infra
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// FSFileWalker implements the repository.FileWalker interface for a file
+// system such as an archive read into memory. Files are reported by their path
+// below a label naming the file system, e.g. "golang.org/x/mod@v0.20.0/semver/semver.go".
+type FSFileWalker struct {
+	fsys  fs.FS
+	label string
+}
+
+// NewFSFileWalker creates a walker of fsys, whose root is named label.
+// Ignore files are not applied.
+func NewFSFileWalker(fsys fs.FS, label string) repository.FileWalker {
+	return &FSFileWalker{fsys: fsys, label: label}
+}
+
+// name returns the name in the file system of a path below the label.
+func (w *FSFileWalker) name(p string) (string, error) {
+	p = filepath.ToSlash(p)
+	if p == w.label {
+		return ".", nil
+	}
+	name, ok := strings.CutPrefix(p, w.label+"/")
+	if !ok || !fs.ValidPath(name) {
+		return "", errors.Errorf("path %s is not in %s", p, w.label)
+	}
+	return name, nil
+}
+
+func (w *FSFileWalker) Walk(
+	ctx context.Context,
+	function repository.WalkFileFunc,
+	p string,
+	opts repository.WalkFileOptions,
+) error {
+	root, err := w.name(p)
+	if err != nil {
+		return err
+	}
+	filter, err := newFileFilter(opts)
+	if err != nil {
+		return err
+	}
+
+	return fs.WalkDir(w.fsys, root, func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return errors.Wrap(err, "failed to read directory")
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if name == root {
+			return nil
+		}
+		rel := name
+		if root != "." {
+			rel = strings.TrimPrefix(name, root+"/")
+		}
+
+		if (entry.IsDir() && entry.Name() == ".git") ||
+			(opts.IgnoreDot && strings.HasPrefix(entry.Name(), ".")) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			if filter.skipDir(rel) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if filter.skipFile(rel, entry) {
+			return nil
+		}
+
+		filePath := path.Join(w.label, name)
+		if err := function(filePath); err != nil {
+			return errors.Wrap(err, "failed to process file: "+filePath)
+		}
+		return nil
+	})
+}
+
+func (w *FSFileWalker) ReadFile(p string) ([]byte, error) {
+	name, err := w.name(p)
+	if err != nil {
+		return nil, err
+	}
+	return fs.ReadFile(w.fsys, name)
+}
@@ -0,0 +1,199 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// GiteaClient is a client of the REST API of a Gitea or Forgejo host such as codeberg.org.
+// Gitea API docs: https://docs.gitea.com/api/
+type GiteaClient struct {
+	*forgeAPI
+}
+
+func (c *GiteaClient) Kind() string {
+	return ForgeGitea
+}
+
+type giteaContent struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	Type     string `json:"type"` // "file", "dir", "symlink" or "submodule"
+	Size     int    `json:"size"`
+	Encoding string `json:"encoding"`
+	Content  string `json:"content"`
+}
+
+func giteaRepoPath(owner, repo string) string {
+	return "/repos/" + url.PathEscape(owner) + "/" + url.PathEscape(repo)
+}
+
+// escapePath escapes the segments of a file path for use in a URL path.
+func escapePath(p string) string {
+	segments := strings.Split(strings.Trim(p, "/"), "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// ResolveRef returns the commit SHA of a branch, tag or (abbreviated) commit SHA,
+// or of the default branch if ref is empty.
+func (c *GiteaClient) ResolveRef(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		var r struct {
+			DefaultBranch string `json:"default_branch"`
+		}
+		if _, err := c.getJSON(ctx, giteaRepoPath(owner, repo), nil, &r); err != nil {
+			return "", errors.Wrap(err, "failed to get repository")
+		}
+		ref = r.DefaultBranch
+	}
+
+	var commits []struct {
+		SHA string `json:"sha"`
+	}
+	query := url.Values{
+		"sha":          {ref},
+		"limit":        {"1"},
+		"stat":         {"false"},
+		"verification": {"false"},
+		"files":        {"false"},
+	}
+	if _, err := c.getJSON(ctx, giteaRepoPath(owner, repo)+"/commits", query, &commits); err != nil {
+		return "", errors.Wrapf(err, "failed to resolve ref %s", ref)
+	}
+	if len(commits) == 0 {
+		return "", errors.Errorf("failed to resolve ref %s: no commits", ref)
+	}
+	return commits[0].SHA, nil
+}
+
+// getContents returns the contents of a file, or of the entries of a directory.
+func (c *GiteaClient) getContents(
+	ctx context.Context,
+	owner, repo, filePath, ref string,
+) (*giteaContent, []giteaContent, error) {
+	p := giteaRepoPath(owner, repo) + "/contents"
+	if filePath = escapePath(filePath); filePath != "" {
+		p += "/" + filePath
+	}
+	var query url.Values
+	if ref != "" {
+		query = url.Values{"ref": {ref}}
+	}
+	data, _, err := c.get(ctx, p, query)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		var entries []giteaContent
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to decode response")
+		}
+		return nil, entries, nil
+	}
+	var file giteaContent
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to decode response")
+	}
+	return &file, nil, nil
+}
+
+// GetContent returns the content of a file, or a listing of a directory,
+// at ref (the default branch if empty).
+func (c *GiteaClient) GetContent(
+	ctx context.Context,
+	owner, repo, filePath, ref string,
+) (string, error) {
+	file, entries, err := c.getContents(ctx, owner, repo, filePath, ref)
+	if err != nil {
+		return "", err
+	}
+	if file == nil {
+		return formatDirListing(filePath, giteaTreeEntries(entries)), nil
+	}
+
+	if file.Type != "file" {
+		return "", errors.Errorf("%s is a %s, not a file", filePath, file.Type)
+	}
+	if file.Encoding != "base64" {
+		return file.Content, nil
+	}
+	content, err := base64.StdEncoding.DecodeString(file.Content)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to decode content")
+	}
+	return string(content), nil
+}
+
+// ListTree lists the tree of commit recursively with the Git Trees API,
+// which Gitea pages. Trees of more than maxTreePages pages are reported as truncated.
+func (c *GiteaClient) ListTree(
+	ctx context.Context, owner, repo, commit string,
+) ([]repository.TreeEntry, bool, error) {
+	p := giteaRepoPath(owner, repo) + "/git/trees/" + url.PathEscape(commit)
+	query := url.Values{"recursive": {"true"}, "per_page": {"1000"}}
+
+	var result []repository.TreeEntry
+	for page := 1; page <= maxTreePages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var tree struct {
+			Tree []struct {
+				Path string `json:"path"`
+				Type string `json:"type"` // "tree" or "blob"
+			} `json:"tree"`
+			Truncated  bool `json:"truncated"`
+			TotalCount int  `json:"total_count"`
+		}
+		if _, err := c.getJSON(ctx, p, query, &tree); err != nil {
+			return nil, false, errors.Wrap(err, "failed to get tree")
+		}
+		for _, entry := range tree.Tree {
+			result = append(result, repository.TreeEntry{
+				Path:  entry.Path,
+				IsDir: entry.Type == "tree",
+			})
+		}
+		if !tree.Truncated || len(tree.Tree) == 0 || len(result) >= tree.TotalCount {
+			return result, false, nil
+		}
+	}
+	return result, true, nil
+}
+
+// ListDir returns the files and directories in a directory at commit.
+func (c *GiteaClient) ListDir(
+	ctx context.Context, owner, repo, commit, dirPath string,
+) ([]repository.TreeEntry, error) {
+	_, entries, err := c.getContents(ctx, owner, repo, dirPath, commit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get directory contents")
+	}
+	return giteaTreeEntries(entries), nil
+}
+
+// SearchCode is not supported: Gitea has no code search API.
+func (c *GiteaClient) SearchCode(
+	ctx context.Context, query string, opt *repository.SearchCodeOption,
+) (repository.SearchCodeResult, error) {
+	return repository.SearchCodeResult{}, errors.Errorf(
+		"%s is a Gitea host, which has no code search API", c.host)
+}
+
+func giteaTreeEntries(entries []giteaContent) []repository.TreeEntry {
+	result := make([]repository.TreeEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = repository.TreeEntry{Path: entry.Path, IsDir: entry.Type == ItemTypeDir}
+	}
+	return result
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	"github.com/fpt/go-dev-mcp/internal/repository"
 	"github.com/google/go-github/v74/github"
@@ -12,81 +13,213 @@ import (
 
 const ItemTypeDir = "dir"
 
+// DefaultGitHubHost is the host of GitHub.com, used for repositories
+// that are not qualified with a host.
+const DefaultGitHubHost = "github.com"
+
+// GitHubHost configures a GitHub Enterprise Server host.
+type GitHubHost struct {
+	Host      string // e.g. "ghe.example.com"
+	APIURL    string // Defaults to https://<host>/api/v3/
+	UploadURL string // Defaults to APIURL
+	Token     string // Takes precedence over the credential chain if set
+}
+
+// GitHubConfig configures NewGitHubClients.
+type GitHubConfig struct {
+	Token string       // Token for github.com, takes precedence over the credential chain if set
+	Hosts []GitHubHost // GitHub Enterprise Server hosts
+}
+
 type GitHubClient struct {
 	*github.Client
+	host       string
+	authSource string
+	token      string
+	httpcli    *HttpClient // Downloads that do not go through the API, e.g. tarballs
 }
 
-func NewGitHubClient() (*GitHubClient, error) {
-	stdout, _, exitCode, err := Run(".", "gh", "auth", "token")
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to authenticate with GitHub")
+// NewGitHubClient creates a client of github.com authenticated with the first
+// credential found by ResolveGitHubCredential. It falls back to anonymous access,
+// which is rate limited and cannot search code.
+func NewGitHubClient(ctx context.Context, token string) *GitHubClient {
+	cred := ResolveGitHubCredential(ctx, DefaultGitHubHost, token)
+	return &GitHubClient{
+		Client:     withGitHubToken(github.NewClient(nil), cred.Token),
+		host:       DefaultGitHubHost,
+		authSource: cred.Source,
+		token:      cred.Token,
+		httpcli:    NewHttpClient(),
 	}
-	if exitCode < 0 {
-		return nil, errors.New("failed to run gh command")
+}
+
+// NewGitHubEnterpriseClient creates a client of a GitHub Enterprise Server host.
+func NewGitHubEnterpriseClient(ctx context.Context, host GitHubHost) (*GitHubClient, error) {
+	apiURL := host.APIURL
+	if apiURL == "" {
+		apiURL = "https://" + host.Host + "/api/v3/"
 	}
-	if exitCode != 0 {
-		return nil, errors.New("failed to authenticate with GitHub")
+	uploadURL := host.UploadURL
+	if uploadURL == "" {
+		uploadURL = apiURL
+	}
+	client, err := github.NewClient(nil).WithEnterpriseURLs(apiURL, uploadURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid API URL of GitHub host %s", host.Host)
 	}
 
-	token := strings.TrimSpace(stdout)
+	cred := ResolveGitHubCredential(ctx, host.Host, host.Token)
+	return &GitHubClient{
+		Client:     withGitHubToken(client, cred.Token),
+		host:       host.Host,
+		authSource: cred.Source,
+		token:      cred.Token,
+		httpcli:    NewHttpClient(),
+	}, nil
+}
+
+func withGitHubToken(client *github.Client, token string) *github.Client {
 	if token == "" {
-		return nil, errors.New("no token found")
+		return client
 	}
+	return client.WithAuthToken(token)
+}
+
+// Host returns the host the client talks to, e.g. "github.com".
+func (c *GitHubClient) Host() string {
+	return c.host
+}
 
-	// Create a new GitHub client with the token
-	client := github.NewClient(nil).WithAuthToken(token)
-	return &GitHubClient{Client: client}, nil
+// AuthSource returns where the credentials of the client came from
+// (one of the GitHubAuth* constants).
+func (c *GitHubClient) AuthSource() string {
+	return c.authSource
+}
+
+// Anonymous reports whether the client has no credentials.
+func (c *GitHubClient) Anonymous() bool {
+	return c.authSource == GitHubAuthAnonymous
 }
 
 // SearchCode searches for code in a GitHub repository using the GitHub API.
+// Fragments are located in the matching files to give them line numbers,
+// which takes one more request per result.
 // https://github.com/google/go-github/blob/b98b707876c8b20b0e1dbbdffb7898a5fcc2169d/github/search.go#L62
 // GitHub API docs: https://docs.github.com/rest/search/search#search-code
 func (c *GitHubClient) SearchCode(
 	ctx context.Context, query string, opt *repository.SearchCodeOption,
 ) (repository.SearchCodeResult, error) {
-	opts := &github.SearchOptions{Sort: "indexed", TextMatch: true}
+	opts := &github.SearchOptions{TextMatch: true}
 	query = strings.TrimSpace(query)
 	if opt != nil {
-		if opt.Language != nil && *opt.Language != "" {
-			query += fmt.Sprintf(" language:%s ", *opt.Language)
-		}
-		if opt.Repo != nil && *opt.Repo != "" {
-			query += fmt.Sprintf(" repo:%s ", *opt.Repo)
+		for _, q := range []struct {
+			qualifier string
+			value     *string
+		}{
+			{"language", opt.Language},
+			{"repo", opt.Repo},
+			{"org", opt.Org},
+			{"user", opt.User},
+			{"path", opt.Path},
+			{"filename", opt.Filename},
+			{"extension", opt.Extension},
+		} {
+			if q.value != nil && *q.value != "" {
+				query += fmt.Sprintf(" %s:%s", q.qualifier, *q.value)
+			}
 		}
+		opts.Page = opt.Page
+		opts.PerPage = opt.PerPage
 	}
-	res, _, err := c.Search.Code(ctx, query, opts)
+	res, resp, err := c.Search.Code(ctx, query, opts)
 	if err != nil {
 		return repository.SearchCodeResult{}, err
 	}
 
-	if *res.Total == 0 {
-		return repository.SearchCodeResult{}, errors.New("no results found")
+	result := repository.SearchCodeResult{
+		Total: res.GetTotal(),
+		Rate: repository.RateLimit{
+			Limit:     resp.Rate.Limit,
+			Remaining: resp.Rate.Remaining,
+			Reset:     resp.Rate.Reset.Time,
+		},
 	}
-
-	var result repository.SearchCodeResult
-	result.Total = *res.Total
 	result.Items = make([]repository.SearchCodeItem, len(res.CodeResults))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, 4) // Concurrent blob requests
 	for i, item := range res.CodeResults {
-		frags := make([]string, len(item.TextMatches))
+		frags := make([]repository.CodeFragment, len(item.TextMatches))
 		for j, frag := range item.TextMatches {
-			frags[j] = *frag.Fragment
+			frags[j] = repository.CodeFragment{Text: frag.GetFragment()}
 		}
 
 		result.Items[i] = repository.SearchCodeItem{
-			Name:       *item.Name,
-			Path:       *item.Path,
-			Repository: *item.Repository.FullName,
+			Name:       item.GetName(),
+			Path:       item.GetPath(),
+			Repository: item.GetRepository().GetFullName(),
 			Fragments:  frags,
 		}
+		if len(frags) == 0 || item.GetSHA() == "" {
+			continue
+		}
+		wg.Go(func() {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			c.locateFragments(ctx, item.GetRepository(), item.GetSHA(), frags)
+		})
 	}
+	wg.Wait()
 	return result, nil
 }
 
+// locateFragments sets the line numbers of fragments by finding them in the blob
+// they were matched in. Fragments stay unnumbered if the blob cannot be read.
+func (c *GitHubClient) locateFragments(
+	ctx context.Context, repo *github.Repository, sha string, frags []repository.CodeFragment,
+) {
+	blob, _, err := c.Git.GetBlobRaw(ctx, repo.GetOwner().GetLogin(), repo.GetName(), sha)
+	if err != nil {
+		return
+	}
+	content := string(blob)
+	for i := range frags {
+		if idx := strings.Index(content, frags[i].Text); idx >= 0 {
+			frags[i].Line = strings.Count(content[:idx], "\n") + 1
+		}
+	}
+}
+
+// ResolveRef returns the commit SHA of a branch, tag or (abbreviated) commit SHA,
+// or of the default branch if ref is empty.
+func (c *GitHubClient) ResolveRef(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	sha, _, err := c.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve ref %s", ref)
+	}
+	return sha, nil
+}
+
+// contentOptions returns the options reading contents at ref, the default branch if empty.
+func contentOptions(ref string) *github.RepositoryContentGetOptions {
+	if ref == "" {
+		return nil
+	}
+	return &github.RepositoryContentGetOptions{Ref: ref}
+}
+
 // GetContent retrieves the content of a file or directory in a GitHub repository using the GitHub API.
 // If path points to a file, it returns the file content.
 // If path points to a directory, it returns a formatted directory listing.
-func (c *GitHubClient) GetContent(ctx context.Context, owner, repo, path string) (string, error) {
-	fileContent, directoryContent, _, err := c.Repositories.GetContents(ctx, owner, repo, path, nil)
+// ref is a branch, tag or commit SHA; the default branch is read if it is empty.
+func (c *GitHubClient) GetContent(
+	ctx context.Context,
+	owner, repo, path, ref string,
+) (string, error) {
+	fileContent, directoryContent, _, err := c.Repositories.GetContents(
+		ctx, owner, repo, path, contentOptions(ref))
 	if err != nil {
 		return "", err
 	}
@@ -135,168 +268,100 @@ func (c *GitHubClient) GetContent(ctx context.Context, owner, repo, path string)
 	return "", errors.New("no content found (both file and directory content are nil)")
 }
 
-// WalkContentsFunc is the function called for each file or directory visited by WalkContents.
-// The path argument contains the path to the file or directory.
-// The isDir argument is true if the path is a directory and false if it is a file.
-// The depth argument indicates how deep in the tree the current item is.
-// If the function returns an error, walking is stopped.
-type WalkContentsFunc func(path string, isDir bool, depth int) error
-
-// WalkContents walks the directory tree of a GitHub repository.
-// It calls the provided WalkContentsFunc for each file or directory in the tree.
-// The function is called first with the start path, and then with each file or directory found.
-// If the start path is a file, the function is called only for that file.
-// If the start path is a directory, the function is called for that directory and all files and directories in it.
-func (c *GitHubClient) WalkContents(
-	ctx context.Context,
-	owner, repo, startPath string,
-	fn WalkContentsFunc,
-) error {
-	return c.walkContentsRecursive(ctx, owner, repo, startPath, fn, 0)
-}
-
-// walkContentsRecursive is an internal helper function for WalkContents.
-func (c *GitHubClient) walkContentsRecursive(
-	ctx context.Context, owner, repo, path string, fn WalkContentsFunc, depth int,
-) error {
-	fileContent, directoryContent, _, err := c.Repositories.GetContents(ctx, owner, repo, path, nil)
+// ListTree returns the tree of commit with a single recursive Git Trees API call.
+// GitHub truncates the trees of large repositories.
+func (c *GitHubClient) ListTree(
+	ctx context.Context, owner, repo, commit string,
+) ([]repository.TreeEntry, bool, error) {
+	tree, _, err := c.Git.GetTree(ctx, owner, repo, commit, true)
 	if err != nil {
-		return err
+		return nil, false, errors.Wrap(err, "failed to get tree")
 	}
-
-	// If it's a file, call the function with isDir = false
-	if fileContent != nil {
-		return fn(path, false, depth)
+	entries := make([]repository.TreeEntry, len(tree.Entries))
+	for i, entry := range tree.Entries {
+		entries[i] = repository.TreeEntry{Path: entry.GetPath(), IsDir: entry.GetType() == "tree"}
 	}
+	return entries, tree.GetTruncated(), nil
+}
 
-	// If it's a directory, first call the function for the directory itself with isDir = true
-	if err := fn(path, true, depth); err != nil {
-		return err
+// ListDir lists a directory with the Contents API.
+func (c *GitHubClient) ListDir(
+	ctx context.Context, owner, repo, commit, path string,
+) ([]repository.TreeEntry, error) {
+	_, directoryContent, _, err := c.Repositories.GetContents(
+		ctx, owner, repo, path, contentOptions(commit))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get directory contents")
 	}
-
-	// Then call the function for each item in the directory
-	for _, item := range directoryContent {
-		itemPath := *item.Path
-		isDir := *item.Type == ItemTypeDir
-
-		// Call the function for this item
-		if err := fn(itemPath, isDir, depth+1); err != nil {
-			return err
-		}
-
-		// If it's a directory, recursively walk it
-		if isDir {
-			if err := c.walkContentsRecursive(ctx, owner, repo, itemPath, fn, depth+1); err != nil {
-				return err
-			}
+	entries := make([]repository.TreeEntry, len(directoryContent))
+	for i, item := range directoryContent {
+		entries[i] = repository.TreeEntry{
+			Path:  item.GetPath(),
+			IsDir: item.GetType() == ItemTypeDir,
 		}
 	}
-
-	return nil
-}
-
-// GitHubDirWalker implements the repository.DirWalker interface for GitHub repositories
-type GitHubDirWalker struct {
-	client *GitHubClient
-	owner  string
-	repo   string
+	return entries, nil
 }
 
-// NewGitHubDirWalker creates a new GitHubDirWalker instance
-func NewGitHubDirWalker(
-	ctx context.Context,
-	client *GitHubClient,
-	owner, repo string,
-) repository.DirWalker {
-	return &GitHubDirWalker{
-		client: client,
-		owner:  owner,
-		repo:   repo,
-	}
+// GitHubClients holds one client per configured GitHub host.
+type GitHubClients struct {
+	clients map[string]*GitHubClient
+	hosts   []string // In configuration order, github.com first
 }
 
-// Walk implements the repository.DirWalker interface for GitHub repositories
-func (w *GitHubDirWalker) Walk(
-	ctx context.Context,
-	function repository.WalkDirFunc,
-	prefixFunc repository.WalkDirNextPrefixFunc,
-	prefix, path string,
-	ignoreDot bool,
-	maxDepth int,
-) error {
-	return w.walkWithDepth(ctx, function, prefixFunc, prefix, path, ignoreDot, maxDepth, 0)
-}
-
-func (w *GitHubDirWalker) walkWithDepth(
-	ctx context.Context,
-	function repository.WalkDirFunc,
-	prefixFunc repository.WalkDirNextPrefixFunc,
-	prefix, path string,
-	ignoreDot bool,
-	maxDepth int,
-	currentDepth int,
-) error {
-	// Get contents of the directory
-	_, directoryContent, _, err := w.client.Repositories.GetContents(
-		ctx,
-		w.owner,
-		w.repo,
-		path,
-		nil,
-	)
-	if err != nil {
-		return errors.Wrap(err, "failed to get directory contents")
+// NewGitHubClients creates the clients of github.com and the configured
+// GitHub Enterprise Server hosts, resolving the credentials of each host.
+func NewGitHubClients(ctx context.Context, cfg GitHubConfig) (*GitHubClients, error) {
+	token := cfg.Token
+	var enterprise []GitHubHost
+	for _, host := range cfg.Hosts {
+		switch {
+		case host.Host == "":
+			return nil, errors.New("GitHub host name is empty")
+		case host.Host == DefaultGitHubHost:
+			// Only the token of github.com can be configured
+			if host.Token != "" {
+				token = host.Token
+			}
+		default:
+			enterprise = append(enterprise, host)
+		}
 	}
 
-	// Filter directory entries first
-	filteredContent := make([]*github.RepositoryContent, 0)
-	for _, item := range directoryContent {
-		name := *item.Name
-
-		// Always filter out .git directory
-		if name == ".git" {
-			continue
+	c := &GitHubClients{clients: make(map[string]*GitHubClient)}
+	c.add(NewGitHubClient(ctx, token))
+	for _, host := range enterprise {
+		if _, ok := c.clients[host.Host]; ok {
+			return nil, errors.Errorf("GitHub host %s is configured twice", host.Host)
 		}
-		// Optionally filter out other dot files/directories
-		if ignoreDot && strings.HasPrefix(name, ".") {
-			continue
+		client, err := NewGitHubEnterpriseClient(ctx, host)
+		if err != nil {
+			return nil, err
 		}
-		filteredContent = append(filteredContent, item)
+		c.add(client)
 	}
+	return c, nil
+}
 
-	// Process filtered directory entries
-	for i, item := range filteredContent {
-		isLastEntry := (i == len(filteredContent)-1)
-		name := *item.Name
-		isDir := *item.Type == ItemTypeDir
-
-		// Call the function for this entry
-		if err := function(name, prefix, isLastEntry); err != nil {
-			return err
-		}
-
-		// If it's a directory, recursively walk it
-		if isDir {
-			// Check if we've reached the max depth
-			if currentDepth >= maxDepth {
-				continue
-			}
+func (c *GitHubClients) add(client *GitHubClient) {
+	c.clients[client.host] = client
+	c.hosts = append(c.hosts, client.host)
+}
 
-			nextPrefix := prefixFunc(prefix, isLastEntry)
-			subpath := path
-			if subpath == "" {
-				subpath = name
-			} else {
-				subpath = subpath + "/" + name
-			}
+// Hosts returns the configured hosts, github.com first.
+func (c *GitHubClients) Hosts() []string {
+	return c.hosts
+}
 
-			if err := w.walkWithDepth(
-				ctx, function, prefixFunc, nextPrefix, subpath, ignoreDot, maxDepth, currentDepth+1,
-			); err != nil {
-				return err
-			}
-		}
+// Client returns the client of host, or of github.com if host is empty.
+func (c *GitHubClients) Client(host string) (*GitHubClient, error) {
+	if host == "" {
+		host = DefaultGitHubHost
 	}
-
-	return nil
+	client, ok := c.clients[host]
+	if !ok {
+		return nil, errors.Errorf("GitHub host %s is not configured (configured hosts: %s)",
+			host, strings.Join(c.hosts, ", "))
+	}
+	return client, nil
 }
@@ -0,0 +1,96 @@
+package infra
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Sources of GitHub credentials, in the order they are tried.
+const (
+	GitHubAuthExplicit      = "explicit token"
+	GitHubAuthEnvGitHub     = "GITHUB_TOKEN"
+	GitHubAuthEnvGH         = "GH_TOKEN"
+	GitHubAuthEnvGHE        = "GH_ENTERPRISE_TOKEN"
+	GitHubAuthEnvGitHubE    = "GITHUB_ENTERPRISE_TOKEN"
+	GitHubAuthGhCLI         = "gh auth token"
+	GitHubAuthGitCredential = "git credential helper"
+	GitHubAuthAnonymous     = "anonymous"
+)
+
+// credentialCommandTimeout bounds the gh and git commands run to obtain a token,
+// so that a misconfigured helper cannot block the server.
+const credentialCommandTimeout = 10 * time.Second
+
+// GitHubCredential is a token together with where it was found.
+type GitHubCredential struct {
+	Token  string
+	Source string // One of the GitHubAuth* constants
+}
+
+// ResolveGitHubCredential returns the first token for host found in the chain:
+// the explicit token, the environment, `gh auth token`, the git credential helper.
+// Like gh, it reads $GITHUB_TOKEN and $GH_TOKEN for github.com, and
+// $GH_ENTERPRISE_TOKEN and $GITHUB_ENTERPRISE_TOKEN for other hosts.
+// Without any, the credential is anonymous.
+func ResolveGitHubCredential(ctx context.Context, host, explicitToken string) GitHubCredential {
+	if token := strings.TrimSpace(explicitToken); token != "" {
+		return GitHubCredential{Token: token, Source: GitHubAuthExplicit}
+	}
+	envs := []string{GitHubAuthEnvGitHub, GitHubAuthEnvGH}
+	if host != DefaultGitHubHost {
+		envs = []string{GitHubAuthEnvGHE, GitHubAuthEnvGitHubE}
+	}
+	for _, env := range envs {
+		if token := strings.TrimSpace(os.Getenv(env)); token != "" {
+			return GitHubCredential{Token: token, Source: env}
+		}
+	}
+	if token := ghAuthToken(ctx, host); token != "" {
+		return GitHubCredential{Token: token, Source: GitHubAuthGhCLI}
+	}
+	if token := gitCredentialToken(ctx, host); token != "" {
+		return GitHubCredential{Token: token, Source: GitHubAuthGitCredential}
+	}
+	return GitHubCredential{Source: GitHubAuthAnonymous}
+}
+
+// ghAuthToken returns the token of the gh CLI for host, or "" if gh is not
+// installed or not logged in.
+func ghAuthToken(ctx context.Context, host string) string {
+	ctx, cancel := context.WithTimeout(ctx, credentialCommandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "gh", "auth", "token", "--hostname", host).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitCredentialToken asks the git credential helper for the password of host
+// without prompting, or returns "" if there is none.
+func gitCredentialToken(ctx context.Context, host string) string {
+	ctx, cancel := context.WithTimeout(ctx, credentialCommandTimeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "git", "credential", "fill")
+	cmd.Stdin = strings.NewReader("protocol=https\nhost=" + host + "\n\n")
+	// Fail instead of asking on the terminal
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(string(out)))
+	for scanner.Scan() {
+		if password, ok := strings.CutPrefix(scanner.Text(), "password="); ok {
+			return strings.TrimSpace(password)
+		}
+	}
+	return ""
+}
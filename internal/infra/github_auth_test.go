@@ -0,0 +1,97 @@
+package infra
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveGitHubCredential(t *testing.T) {
+	// Neither gh nor git can be found, so only the explicit token and
+	// the environment are consulted.
+	t.Setenv("PATH", t.TempDir())
+
+	tests := []struct {
+		name       string
+		host       string
+		explicit   string
+		env        map[string]string
+		wantToken  string
+		wantSource string
+	}{
+		{
+			"explicit", DefaultGitHubHost, "explicit",
+			map[string]string{"GITHUB_TOKEN": "github", "GH_TOKEN": "gh"},
+			"explicit", GitHubAuthExplicit,
+		},
+		{
+			"GITHUB_TOKEN", DefaultGitHubHost, "",
+			map[string]string{"GITHUB_TOKEN": "github", "GH_TOKEN": "gh"},
+			"github", GitHubAuthEnvGitHub,
+		},
+		{
+			"GH_TOKEN", DefaultGitHubHost, " ",
+			map[string]string{"GH_TOKEN": "gh", "GH_ENTERPRISE_TOKEN": "ghe"},
+			"gh", GitHubAuthEnvGH,
+		},
+		{
+			"GH_ENTERPRISE_TOKEN", "ghe.example.com", "",
+			map[string]string{"GITHUB_TOKEN": "github", "GH_ENTERPRISE_TOKEN": "ghe"},
+			"ghe", GitHubAuthEnvGHE,
+		},
+		{
+			"enterprise anonymous", "ghe.example.com", "",
+			map[string]string{"GITHUB_TOKEN": "github", "GH_TOKEN": "gh"},
+			"", GitHubAuthAnonymous,
+		},
+		{"anonymous", DefaultGitHubHost, "", nil, "", GitHubAuthAnonymous},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range []string{
+				"GITHUB_TOKEN", "GH_TOKEN", "GH_ENTERPRISE_TOKEN", "GITHUB_ENTERPRISE_TOKEN",
+			} {
+				t.Setenv(env, tt.env[env])
+			}
+
+			cred := ResolveGitHubCredential(context.Background(), tt.host, tt.explicit)
+			if cred.Token != tt.wantToken || cred.Source != tt.wantSource {
+				t.Errorf("got (%q, %q), want (%q, %q)",
+					cred.Token, cred.Source, tt.wantToken, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestResolveGitHubCredential_Commands(t *testing.T) {
+	bin := t.TempDir()
+	t.Setenv("PATH", bin)
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+
+	writeScript := func(name, script string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(bin, name), []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The git credential helper is used when gh is not logged in
+	writeScript("gh", "exit 1\n")
+	writeScript(
+		"git",
+		"cat >/dev/null\nprintf 'protocol=https\\nhost=github.com\\nusername=x\\npassword=from-git\\n'\n",
+	)
+	cred := ResolveGitHubCredential(context.Background(), DefaultGitHubHost, "")
+	if cred.Token != "from-git" || cred.Source != GitHubAuthGitCredential {
+		t.Errorf("got (%q, %q), want git credential helper token", cred.Token, cred.Source)
+	}
+
+	writeScript("gh", "test \"$4\" = github.com && echo from-gh\n")
+	cred = ResolveGitHubCredential(context.Background(), DefaultGitHubHost, "")
+	if cred.Token != "from-gh" || cred.Source != GitHubAuthGhCLI {
+		t.Errorf("got (%q, %q), want gh token", cred.Token, cred.Source)
+	}
+}
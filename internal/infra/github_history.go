@@ -0,0 +1,221 @@
+package infra
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/google/go-github/v74/github"
+	"github.com/pkg/errors"
+)
+
+// maxPerPage is the largest page size of the GitHub REST API.
+const maxPerPage = 100
+
+// ListCommits returns the most recent commits of a ref, optionally only those touching a path.
+// Commits are fetched page by page until the limit is reached.
+func (c *GitHubClient) ListCommits(
+	ctx context.Context, owner, repo string, opt *repository.ListCommitsOption,
+) ([]repository.CommitSummary, error) {
+	opts := &github.CommitsListOptions{}
+	limit := 0
+	if opt != nil {
+		opts.SHA = opt.Ref
+		opts.Path = opt.Path
+		limit = max(opt.Limit, 0)
+		// The page size must stay the same across pages
+		opts.PerPage = min(limit, maxPerPage)
+	}
+
+	var result []repository.CommitSummary
+	for {
+		commits, resp, err := c.Repositories.ListCommits(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, commit := range commits {
+			result = append(result, commitSummary(commit))
+		}
+		if limit > 0 && len(result) >= limit {
+			return result[:limit], nil
+		}
+		if limit == 0 || resp.NextPage == 0 {
+			return result, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// Compare compares two refs. Commits and files are limited to what
+// a single response of the GitHub API contains (250 commits, 300 files).
+func (c *GitHubClient) Compare(
+	ctx context.Context, owner, repo, base, head string,
+) (repository.Comparison, error) {
+	comparison, _, err := c.Repositories.CompareCommits(ctx, owner, repo, base, head, nil)
+	if err != nil {
+		return repository.Comparison{}, err
+	}
+	diff, _, err := c.Repositories.CompareCommitsRaw(
+		ctx, owner, repo, base, head, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return repository.Comparison{}, errors.Wrap(err, "failed to get diff")
+	}
+
+	result := repository.Comparison{
+		Status:   comparison.GetStatus(),
+		AheadBy:  comparison.GetAheadBy(),
+		BehindBy: comparison.GetBehindBy(),
+		Diff:     diff,
+	}
+	for _, commit := range comparison.Commits {
+		result.Commits = append(result.Commits, commitSummary(commit))
+	}
+	for _, file := range comparison.Files {
+		result.Files = append(result.Files, repository.PullRequestFile{
+			Filename:  file.GetFilename(),
+			Status:    file.GetStatus(),
+			Additions: file.GetAdditions(),
+			Deletions: file.GetDeletions(),
+		})
+	}
+	return result, nil
+}
+
+func commitSummary(commit *github.RepositoryCommit) repository.CommitSummary {
+	author := commit.GetAuthor().GetLogin()
+	if author == "" {
+		author = commit.GetCommit().GetAuthor().GetName()
+	}
+	message, _, _ := strings.Cut(commit.GetCommit().GetMessage(), "\n")
+	return repository.CommitSummary{
+		SHA:     commit.GetSHA(),
+		Author:  author,
+		Date:    commit.GetCommit().GetAuthor().GetDate().Time,
+		Message: message,
+	}
+}
+
+// blameQuery blames a file at a commit expression. Blame is only available
+// through the GraphQL API.
+const blameQuery = `query($owner: String!, $repo: String!, $ref: String!, $path: String!) {
+  repository(owner: $owner, name: $repo) {
+    object(expression: $ref) {
+      ... on Commit {
+        oid
+        blame(path: $path) {
+          ranges {
+            startingLine
+            endingLine
+            commit {
+              oid
+              committedDate
+              messageHeadline
+              author { name user { login } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type blameResponse struct {
+	Data struct {
+		Repository *struct {
+			Object *struct {
+				OID   string `json:"oid"`
+				Blame *struct {
+					Ranges []struct {
+						StartingLine int `json:"startingLine"`
+						EndingLine   int `json:"endingLine"`
+						Commit       struct {
+							OID             string    `json:"oid"`
+							CommittedDate   time.Time `json:"committedDate"`
+							MessageHeadline string    `json:"messageHeadline"`
+							Author          struct {
+								Name string `json:"name"`
+								User *struct {
+									Login string `json:"login"`
+								} `json:"user"`
+							} `json:"author"`
+						} `json:"commit"`
+					} `json:"ranges"`
+				} `json:"blame"`
+			} `json:"object"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// Blame maps the lines of a file at ref (the default branch if empty) to the
+// commits that last changed them. The GraphQL API requires authentication.
+func (c *GitHubClient) Blame(
+	ctx context.Context, owner, repo, ref, path string,
+) (repository.BlameResult, error) {
+	if c.Anonymous() {
+		return repository.BlameResult{}, errors.New("blame requires GitHub credentials")
+	}
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	req, err := c.NewRequest("POST", c.graphQLURL(), map[string]any{
+		"query": blameQuery,
+		"variables": map[string]string{
+			"owner": owner,
+			"repo":  repo,
+			"ref":   ref,
+			"path":  strings.Trim(path, "/"),
+		},
+	})
+	if err != nil {
+		return repository.BlameResult{}, errors.Wrap(err, "failed to create GraphQL request")
+	}
+	var resp blameResponse
+	if _, err := c.Do(ctx, req, &resp); err != nil {
+		return repository.BlameResult{}, err
+	}
+	if len(resp.Errors) > 0 {
+		return repository.BlameResult{}, errors.Errorf("GraphQL error: %s", resp.Errors[0].Message)
+	}
+
+	if resp.Data.Repository == nil {
+		return repository.BlameResult{}, errors.Errorf("repository %s/%s not found", owner, repo)
+	}
+	object := resp.Data.Repository.Object
+	if object == nil || object.Blame == nil {
+		return repository.BlameResult{}, errors.Errorf("ref %s not found", ref)
+	}
+
+	result := repository.BlameResult{Commit: object.OID}
+	for _, r := range object.Blame.Ranges {
+		author := r.Commit.Author.Name
+		if r.Commit.Author.User != nil && r.Commit.Author.User.Login != "" {
+			author = r.Commit.Author.User.Login
+		}
+		result.Ranges = append(result.Ranges, repository.BlameRange{
+			StartLine: r.StartingLine,
+			EndLine:   r.EndingLine,
+			Commit: repository.CommitSummary{
+				SHA:     r.Commit.OID,
+				Author:  author,
+				Date:    r.Commit.CommittedDate,
+				Message: r.Commit.MessageHeadline,
+			},
+		})
+	}
+	return result, nil
+}
+
+// graphQLURL returns the GraphQL endpoint next to the REST API of the client:
+// https://api.github.com/graphql, or https://<host>/api/graphql on GitHub Enterprise Server.
+func (c *GitHubClient) graphQLURL() string {
+	base := c.BaseURL.String()
+	if api, ok := strings.CutSuffix(base, "/api/v3/"); ok {
+		return api + "/api/graphql"
+	}
+	return base + "graphql"
+}
@@ -0,0 +1,239 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/google/go-github/v74/github"
+	"github.com/pkg/errors"
+)
+
+// maxListPages bounds the pages fetched when listing all files or review
+// comments of a pull request (GitHub returns at most 3000 files anyway).
+const maxListPages = 30
+
+// SearchIssues searches issues and pull requests using the GitHub API.
+// GitHub API docs: https://docs.github.com/rest/search/search#search-issues-and-pull-requests
+func (c *GitHubClient) SearchIssues(
+	ctx context.Context, query string, opt *repository.SearchIssuesOption,
+) (repository.SearchIssuesResult, error) {
+	opts := &github.SearchOptions{Sort: "updated"}
+	query = strings.TrimSpace(query)
+	if opt != nil {
+		if opt.Repo != nil && *opt.Repo != "" {
+			query += fmt.Sprintf(" repo:%s", *opt.Repo)
+		}
+		if opt.State != nil && *opt.State != "" {
+			query += fmt.Sprintf(" state:%s", *opt.State)
+		}
+		if opt.Kind != nil && *opt.Kind != "" {
+			query += fmt.Sprintf(" is:%s", *opt.Kind)
+		}
+		opts.Page = opt.Page
+	}
+	res, _, err := c.Search.Issues(ctx, query, opts)
+	if err != nil {
+		return repository.SearchIssuesResult{}, err
+	}
+
+	result := repository.SearchIssuesResult{Total: res.GetTotal()}
+	result.Items = make([]repository.IssueSummary, len(res.Issues))
+	for i, issue := range res.Issues {
+		result.Items[i] = issueSummary(issue)
+	}
+	return result, nil
+}
+
+// GetIssue retrieves an issue or the issue part of a pull request.
+func (c *GitHubClient) GetIssue(
+	ctx context.Context, owner, repo string, number int,
+) (repository.Issue, error) {
+	issue, _, err := c.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return repository.Issue{}, err
+	}
+
+	labels := make([]string, len(issue.Labels))
+	for i, label := range issue.Labels {
+		labels[i] = label.GetName()
+	}
+	result := repository.Issue{
+		IssueSummary: issueSummary(issue),
+		Body:         issue.GetBody(),
+		Labels:       labels,
+		CreatedAt:    issue.GetCreatedAt().Time,
+		URL:          issue.GetHTMLURL(),
+	}
+	if result.Repository == "" {
+		result.Repository = owner + "/" + repo
+	}
+	return result, nil
+}
+
+// ListIssueComments returns a page of the comments of an issue or pull request,
+// oldest first, and whether there are more pages.
+func (c *GitHubClient) ListIssueComments(
+	ctx context.Context, owner, repo string, number, page, perPage int,
+) ([]repository.IssueComment, bool, error) {
+	opts := &github.IssueListCommentsOptions{
+		ListOptions: github.ListOptions{Page: page, PerPage: perPage},
+	}
+	comments, resp, err := c.Issues.ListComments(ctx, owner, repo, number, opts)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := make([]repository.IssueComment, len(comments))
+	for i, comment := range comments {
+		result[i] = repository.IssueComment{
+			Author:    comment.GetUser().GetLogin(),
+			CreatedAt: comment.GetCreatedAt().Time,
+			Body:      comment.GetBody(),
+		}
+	}
+	return result, resp.NextPage != 0, nil
+}
+
+// GetPullRequest retrieves a pull request.
+func (c *GitHubClient) GetPullRequest(
+	ctx context.Context, owner, repo string, number int,
+) (repository.PullRequest, error) {
+	pr, _, err := c.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return repository.PullRequest{}, err
+	}
+
+	labels := make([]string, len(pr.Labels))
+	for i, label := range pr.Labels {
+		labels[i] = label.GetName()
+	}
+	state := pr.GetState()
+	if pr.GetMerged() {
+		state = "merged"
+	}
+	return repository.PullRequest{
+		Issue: repository.Issue{
+			IssueSummary: repository.IssueSummary{
+				Repository:    owner + "/" + repo,
+				Number:        pr.GetNumber(),
+				Title:         pr.GetTitle(),
+				State:         state,
+				IsPullRequest: true,
+				Author:        pr.GetUser().GetLogin(),
+				Comments:      pr.GetComments(),
+				UpdatedAt:     pr.GetUpdatedAt().Time,
+			},
+			Body:      pr.GetBody(),
+			Labels:    labels,
+			CreatedAt: pr.GetCreatedAt().Time,
+			URL:       pr.GetHTMLURL(),
+		},
+		Base:         pr.GetBase().GetRef(),
+		Head:         pr.GetHead().GetLabel(),
+		Draft:        pr.GetDraft(),
+		Merged:       pr.GetMerged(),
+		Additions:    pr.GetAdditions(),
+		Deletions:    pr.GetDeletions(),
+		ChangedFiles: pr.GetChangedFiles(),
+	}, nil
+}
+
+// ListPullRequestFiles returns the files changed by a pull request.
+func (c *GitHubClient) ListPullRequestFiles(
+	ctx context.Context, owner, repo string, number int,
+) ([]repository.PullRequestFile, error) {
+	var result []repository.PullRequestFile
+	opts := &github.ListOptions{PerPage: 100}
+	for range maxListPages {
+		files, resp, err := c.PullRequests.ListFiles(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			result = append(result, repository.PullRequestFile{
+				Filename:  file.GetFilename(),
+				Status:    file.GetStatus(),
+				Additions: file.GetAdditions(),
+				Deletions: file.GetDeletions(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// ListReviewComments returns the review comments of a pull request, oldest first.
+func (c *GitHubClient) ListReviewComments(
+	ctx context.Context, owner, repo string, number int,
+) ([]repository.ReviewComment, error) {
+	var result []repository.ReviewComment
+	opts := &github.PullRequestListCommentsOptions{
+		Sort:        "created",
+		Direction:   "asc",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for range maxListPages {
+		comments, resp, err := c.PullRequests.ListComments(ctx, owner, repo, number, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, comment := range comments {
+			line := comment.GetLine()
+			if line == 0 {
+				line = comment.GetOriginalLine() // outdated comment
+			}
+			result = append(result, repository.ReviewComment{
+				Author:    comment.GetUser().GetLogin(),
+				Path:      comment.GetPath(),
+				Line:      line,
+				CreatedAt: comment.GetCreatedAt().Time,
+				Body:      comment.GetBody(),
+			})
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// GetPullRequestDiff returns the unified diff of a pull request.
+func (c *GitHubClient) GetPullRequestDiff(
+	ctx context.Context, owner, repo string, number int,
+) (string, error) {
+	diff, _, err := c.PullRequests.GetRaw(
+		ctx, owner, repo, number, github.RawOptions{Type: github.Diff})
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get diff")
+	}
+	return diff, nil
+}
+
+func issueSummary(issue *github.Issue) repository.IssueSummary {
+	return repository.IssueSummary{
+		Repository:    repositoryFromURL(issue.GetRepositoryURL()),
+		Number:        issue.GetNumber(),
+		Title:         issue.GetTitle(),
+		State:         issue.GetState(),
+		IsPullRequest: issue.IsPullRequest(),
+		Author:        issue.GetUser().GetLogin(),
+		Comments:      issue.GetComments(),
+		UpdatedAt:     issue.GetUpdatedAt().Time,
+	}
+}
+
+// repositoryFromURL returns "owner/repo" of an API repository URL
+// such as "https://api.github.com/repos/owner/repo".
+func repositoryFromURL(url string) string {
+	_, fullName, ok := strings.Cut(url, "/repos/")
+	if !ok {
+		return ""
+	}
+	return fullName
+}
@@ -0,0 +1,169 @@
+package infra
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/google/go-github/v74/github"
+	"github.com/pkg/errors"
+)
+
+// SearchRepositories searches repositories using the GitHub API.
+// GitHub API docs: https://docs.github.com/rest/search/search#search-repositories
+func (c *GitHubClient) SearchRepositories(
+	ctx context.Context, query string, opt *repository.SearchReposOption,
+) (repository.SearchReposResult, error) {
+	opts := &github.SearchOptions{}
+	query = strings.TrimSpace(query)
+	if opt != nil {
+		if opt.Language != nil && *opt.Language != "" {
+			query += fmt.Sprintf(" language:%s", *opt.Language)
+		}
+		if opt.MinStars > 0 {
+			query += fmt.Sprintf(" stars:>=%d", opt.MinStars)
+		}
+		opts.Sort = opt.Sort
+		opts.Page = opt.Page
+		opts.PerPage = opt.PerPage
+	}
+	res, _, err := c.Search.Repositories(ctx, query, opts)
+	if err != nil {
+		return repository.SearchReposResult{}, err
+	}
+
+	result := repository.SearchReposResult{Total: res.GetTotal()}
+	result.Items = make([]repository.RepositorySummary, len(res.Repositories))
+	for i, repo := range res.Repositories {
+		result.Items[i] = repository.RepositorySummary{
+			FullName:    repo.GetFullName(),
+			Description: repo.GetDescription(),
+			Language:    repo.GetLanguage(),
+			Stars:       repo.GetStargazersCount(),
+			Archived:    repo.GetArchived(),
+			PushedAt:    repo.GetPushedAt().Time,
+		}
+	}
+	return result, nil
+}
+
+// GetRepositoryInfo returns the metadata of a repository together with its
+// latest release and languages.
+func (c *GitHubClient) GetRepositoryInfo(
+	ctx context.Context, owner, repo string,
+) (repository.RepositoryInfo, error) {
+	r, _, err := c.Repositories.Get(ctx, owner, repo)
+	if err != nil {
+		return repository.RepositoryInfo{}, err
+	}
+
+	info := repository.RepositoryInfo{
+		FullName:      r.GetFullName(),
+		Description:   r.GetDescription(),
+		Homepage:      r.GetHomepage(),
+		DefaultBranch: r.GetDefaultBranch(),
+		Stars:         r.GetStargazersCount(),
+		Forks:         r.GetForksCount(),
+		OpenIssues:    r.GetOpenIssuesCount(),
+		Topics:        r.Topics,
+		Archived:      r.GetArchived(),
+		PushedAt:      r.GetPushedAt().Time,
+	}
+	if license := r.GetLicense(); license != nil {
+		info.License = license.GetSPDXID()
+		if info.License == "" || info.License == "NOASSERTION" {
+			info.License = license.GetName()
+		}
+	}
+
+	release, resp, err := c.Repositories.GetLatestRelease(ctx, owner, repo)
+	switch {
+	case err == nil:
+		info.LatestRelease = &repository.Release{
+			Tag:         release.GetTagName(),
+			Name:        release.GetName(),
+			PublishedAt: release.GetPublishedAt().Time,
+		}
+	case resp == nil || resp.StatusCode != http.StatusNotFound:
+		return repository.RepositoryInfo{}, errors.Wrap(err, "failed to get latest release")
+	}
+
+	languages, _, err := c.Repositories.ListLanguages(ctx, owner, repo)
+	if err != nil {
+		return repository.RepositoryInfo{}, errors.Wrap(err, "failed to list languages")
+	}
+	for language, bytes := range languages {
+		info.Languages = append(info.Languages, repository.LanguageBytes{
+			Language: language,
+			Bytes:    bytes,
+		})
+	}
+	slices.SortFunc(info.Languages, func(a, b repository.LanguageBytes) int {
+		return cmp.Or(cmp.Compare(b.Bytes, a.Bytes), strings.Compare(a.Language, b.Language))
+	})
+	return info, nil
+}
+
+// ListReleases returns up to limit published releases with their notes, newest first.
+// Drafts are only visible with push access and are skipped.
+func (c *GitHubClient) ListReleases(
+	ctx context.Context, owner, repo string, limit int,
+) ([]repository.Release, error) {
+	var result []repository.Release
+	opts := &github.ListOptions{PerPage: min(limit, 100)}
+	for range maxListPages {
+		releases, resp, err := c.Repositories.ListReleases(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, release := range releases {
+			if release.GetDraft() {
+				continue
+			}
+			result = append(result, repository.Release{
+				Tag:         release.GetTagName(),
+				Name:        release.GetName(),
+				PublishedAt: release.GetPublishedAt().Time,
+				Prerelease:  release.GetPrerelease(),
+				Body:        release.GetBody(),
+			})
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
+
+// ListTags returns up to limit tag names in the order of the GitHub API.
+func (c *GitHubClient) ListTags(
+	ctx context.Context, owner, repo string, limit int,
+) ([]string, error) {
+	var result []string
+	opts := &github.ListOptions{PerPage: min(limit, 100)}
+	for range maxListPages {
+		tags, resp, err := c.Repositories.ListTags(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, tag := range tags {
+			result = append(result, tag.GetName())
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return result, nil
+}
@@ -0,0 +1,486 @@
+package infra
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+)
+
+func TestParseRepo(t *testing.T) {
+	tests := []struct {
+		arg               string
+		host, owner, repo string
+		expectError       bool
+	}{
+		{arg: "fpt/go-dev-mcp", owner: "fpt", repo: "go-dev-mcp"},
+		{arg: "ghe.example.com/org/repo", host: "ghe.example.com", owner: "org", repo: "repo"},
+		{arg: "github.com/fpt/go-dev-mcp/", host: "github.com", owner: "fpt", repo: "go-dev-mcp"},
+		{arg: "fpt", expectError: true},
+		{arg: "fpt//go-dev-mcp", expectError: true},
+		{arg: "gitlab.com/group/sub/repo", host: "gitlab.com", owner: "group/sub", repo: "repo"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			host, owner, repo, err := ParseRepo(tt.arg)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("expected error, got (%q, %q, %q)", host, owner, repo)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if host != tt.host || owner != tt.owner || repo != tt.repo {
+				t.Errorf("got (%q, %q, %q), want (%q, %q, %q)",
+					host, owner, repo, tt.host, tt.owner, tt.repo)
+			}
+		})
+	}
+}
+
+func TestGitHubClients_Enterprise(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	t.Setenv("GH_ENTERPRISE_TOKEN", "")
+	t.Setenv("GITHUB_ENTERPRISE_TOKEN", "")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/org/repo/contents/README.md" {
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer ghe-token" {
+			http.Error(w, "bad credentials: "+got, http.StatusUnauthorized)
+			return
+		}
+		content := base64.StdEncoding.EncodeToString([]byte("# Enterprise\n"))
+		fmt.Fprintf(w, `{"type":"file","encoding":"base64","content":%q}`, content)
+	}))
+	defer srv.Close()
+
+	clients, err := NewGitHubClients(context.Background(), GitHubConfig{
+		Hosts: []GitHubHost{{Host: "ghe.example.com", APIURL: srv.URL, Token: "ghe-token"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := clients.Client("unknown.example.com"); err == nil {
+		t.Error("expected error for an unconfigured host")
+	}
+
+	gh, err := clients.Client("ghe.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gh.Host() != "ghe.example.com" || gh.AuthSource() != GitHubAuthExplicit {
+		t.Errorf("got host %q with credentials from %q", gh.Host(), gh.AuthSource())
+	}
+
+	content, err := gh.GetContent(context.Background(), "org", "repo", "README.md", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if content != "# Enterprise\n" {
+		t.Errorf("got content %q", content)
+	}
+
+	_, err = NewGitHubClients(context.Background(), GitHubConfig{
+		Hosts: []GitHubHost{{Host: "ghe.example.com"}, {Host: "ghe.example.com"}},
+	})
+	if err == nil {
+		t.Error("expected error for a host configured twice")
+	}
+}
+
+func TestForgeDirWalker_GitHubTree(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	tree := `{"sha":"t1","truncated":%t,"tree":[
+		{"path":".github","type":"tree"},
+		{"path":".github/ci.yml","type":"blob"},
+		{"path":"cmd","type":"tree"},
+		{"path":"cmd/main.go","type":"blob"},
+		{"path":"cmd/sub","type":"tree"},
+		{"path":"cmd/sub/sub.go","type":"blob"},
+		{"path":"go.mod","type":"blob"}
+	]}`
+	contents := map[string]string{
+		"":        `[{"name":"cmd","path":"cmd","type":"dir"},{"name":"go.mod","path":"go.mod","type":"file"}]`,
+		"cmd":     `[{"name":"main.go","path":"cmd/main.go","type":"file"},{"name":"sub","path":"cmd/sub","type":"dir"}]`,
+		"cmd/sub": `[{"name":"sub.go","path":"cmd/sub/sub.go","type":"file"}]`,
+	}
+
+	for _, truncated := range []bool{false, true} {
+		t.Run(fmt.Sprintf("truncated=%t", truncated), func(t *testing.T) {
+			var calls []string
+			srv := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					calls = append(calls, r.URL.Path)
+					switch path := strings.TrimPrefix(r.URL.Path, "/api/v3/repos/o/r/"); {
+					case path == "git/trees/c1" && r.URL.Query().Get("recursive") == "1":
+						fmt.Fprintf(w, tree, truncated)
+					case strings.HasPrefix(path, "contents") && r.URL.Query().Get("ref") == "c1":
+						dir := strings.Trim(strings.TrimPrefix(path, "contents"), "/")
+						fmt.Fprint(w, contents[dir])
+					default:
+						http.NotFound(w, r)
+					}
+				}),
+			)
+			defer srv.Close()
+
+			gh, err := NewGitHubEnterpriseClient(
+				context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var b strings.Builder
+			walker := NewForgeDirWalker(gh, "o", "r", "c1")
+			err = walker.Walk(
+				context.Background(),
+				func(name, prefix string, isLastEntry bool) error {
+					fmt.Fprintf(&b, "%s%s %t\n", prefix, name, isLastEntry)
+					return nil
+				},
+				func(prefix string, isLastEntry bool) string { return prefix + "  " },
+				"", "", true, 1,
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := "cmd false\n  main.go false\n  sub true\ngo.mod true\n"
+			if b.String() != want {
+				t.Errorf("got tree\n%s\nwant\n%s", b.String(), want)
+			}
+			wantCalls := 1
+			if truncated {
+				wantCalls = 3 // tree, contents of the root and of cmd
+			}
+			if len(calls) != wantCalls {
+				t.Errorf("got %d API calls %v, want %d", len(calls), calls, wantCalls)
+			}
+		})
+	}
+}
+
+func TestGitHubClient_ResolveRef(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/commits/HEAD":
+			fmt.Fprint(w, "head-sha")
+		case "/api/v3/repos/o/r/commits/v1.0":
+			fmt.Fprint(w, "tag-sha")
+		default:
+			http.Error(w, `{"message":"No commit found"}`, http.StatusUnprocessableEntity)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ref, want := range map[string]string{"": "head-sha", "v1.0": "tag-sha"} {
+		got, err := gh.ResolveRef(context.Background(), "o", "r", ref)
+		if err != nil || got != want {
+			t.Errorf("ResolveRef(%q) = %q, %v, want %q", ref, got, err, want)
+		}
+	}
+	if _, err := gh.ResolveRef(context.Background(), "o", "r", "missing"); err == nil {
+		t.Error("expected error for an unknown ref")
+	}
+}
+
+func TestGitHubClient_ListCommitsPaging(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	const total = 250
+	var pages []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		page = max(page, 1)
+		pages = append(pages, r.URL.RawQuery)
+		first := (page - 1) * perPage
+		if first+perPage < total {
+			w.Header().Set("Link", fmt.Sprintf(
+				`<%s%s?page=%d&per_page=%d>; rel="next"`,
+				"http://"+r.Host,
+				r.URL.Path,
+				page+1,
+				perPage,
+			))
+		}
+		var commits []string
+		for i := first; i < min(first+perPage, total); i++ {
+			commits = append(commits, fmt.Sprintf(`{"sha":"%d"}`, i))
+		}
+		fmt.Fprint(w, "["+strings.Join(commits, ",")+"]")
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct{ limit, want, calls int }{
+		{limit: 30, want: 30, calls: 1},
+		{limit: 150, want: 150, calls: 2},
+		{limit: 1000, want: total, calls: 3},
+	} {
+		pages = nil
+		commits, err := gh.ListCommits(context.Background(), "o", "r",
+			&repository.ListCommitsOption{Limit: tt.limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(commits) != tt.want || len(pages) != tt.calls {
+			t.Errorf("limit %d: got %d commits in %d calls %v, want %d in %d",
+				tt.limit, len(commits), len(pages), pages, tt.want, tt.calls)
+		}
+		if len(commits) > 0 && commits[len(commits)-1].SHA != strconv.Itoa(tt.want-1) {
+			t.Errorf("limit %d: got last commit %q", tt.limit, commits[len(commits)-1].SHA)
+		}
+	}
+}
+
+func TestGitHubClient_SearchIssues(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query().Get("q")
+		fmt.Fprint(w, `{"total_count":1,"items":[{"number":7,"title":"Fix","state":"open",
+			"repository_url":"https://ghe.test/api/v3/repos/org/repo",
+			"pull_request":{"url":"x"},"user":{"login":"bob"},"comments":2}]}`)
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	repo, state, kind := "org/repo", "open", "pr"
+	result, err := gh.SearchIssues(context.Background(), "crash", &repository.SearchIssuesOption{
+		Repo: &repo, State: &state, Kind: &kind,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "crash repo:org/repo state:open is:pr" {
+		t.Errorf("got query %q", query)
+	}
+	if result.Total != 1 || len(result.Items) != 1 {
+		t.Fatalf("got result %+v", result)
+	}
+	item := result.Items[0]
+	if item.Repository != "org/repo" || item.Number != 7 || !item.IsPullRequest ||
+		item.Author != "bob" || item.Comments != 2 {
+		t.Errorf("got item %+v", item)
+	}
+}
+
+func TestGitHubClient_Blame(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	var variables map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/graphql" {
+			http.NotFound(w, r)
+			return
+		}
+		var body struct {
+			Variables map[string]string `json:"variables"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		variables = body.Variables
+		fmt.Fprint(w, `{"data":{"repository":{"object":{"oid":"abc123","blame":{"ranges":[
+			{"startingLine":1,"endingLine":4,"commit":{"oid":"def456",
+			"committedDate":"2025-03-01T10:00:00Z","messageHeadline":"Initial commit",
+			"author":{"name":"Alice","user":{"login":"alice"}}}}]}}}}}`)
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := gh.Blame(context.Background(), "org", "repo", "", "/main.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variables["ref"] != "HEAD" || variables["path"] != "main.go" {
+		t.Errorf("got variables %v", variables)
+	}
+	if result.Commit != "abc123" || len(result.Ranges) != 1 {
+		t.Fatalf("got result %+v", result)
+	}
+	r := result.Ranges[0]
+	if r.StartLine != 1 || r.EndLine != 4 || r.Commit.SHA != "def456" ||
+		r.Commit.Author != "alice" || r.Commit.Message != "Initial commit" {
+		t.Errorf("got range %+v", r)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GH_TOKEN", "")
+	anonymous := NewGitHubClient(context.Background(), "")
+	if _, err := anonymous.Blame(context.Background(), "org", "repo", "", "main.go"); err == nil {
+		t.Error("expected error for anonymous blame")
+	}
+}
+
+func TestGitHubClient_SearchCode(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	var query, perPage string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/search/code":
+			query = r.URL.Query().Get("q")
+			perPage = r.URL.Query().Get("per_page")
+			w.Header().Set("X-RateLimit-Limit", "30")
+			w.Header().Set("X-RateLimit-Remaining", "29")
+			w.Header().Set("X-RateLimit-Reset", "1735734600")
+			fmt.Fprint(w, `{"total_count":2,"items":[
+				{"name":"main.go","path":"main.go","sha":"b1",
+				"repository":{"name":"repo","full_name":"org/repo","owner":{"login":"org"}},
+				"text_matches":[{"fragment":"\trun()\n}"},{"fragment":"missing"}]},
+				{"name":"gone.go","path":"gone.go","sha":"b2",
+				"repository":{"name":"repo","full_name":"org/repo","owner":{"login":"org"}},
+				"text_matches":[{"fragment":"run()"}]}]}`)
+		case "/api/v3/repos/org/repo/git/blobs/b1":
+			fmt.Fprint(w, "package main\n\nfunc main() {\n\trun()\n}\n")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	org, ext := "org", "go"
+	result, err := gh.SearchCode(context.Background(), "run", &repository.SearchCodeOption{
+		Org: &org, Extension: &ext, PerPage: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query != "run org:org extension:go" || perPage != "5" {
+		t.Errorf("got query %q with per_page %q", query, perPage)
+	}
+	if result.Total != 2 || result.Rate.Remaining != 29 || result.Rate.Limit != 30 {
+		t.Errorf("got total %d and rate %+v", result.Total, result.Rate)
+	}
+	if len(result.Items) != 2 {
+		t.Fatalf("got items %+v", result.Items)
+	}
+	frags := result.Items[0].Fragments
+	if len(frags) != 2 || frags[0].Line != 4 || frags[1].Line != 0 {
+		t.Errorf("got fragments %+v", frags)
+	}
+	// The blob of the second item is not found, so its fragment stays unnumbered
+	if frags := result.Items[1].Fragments; len(frags) != 1 || frags[0].Line != 0 {
+		t.Errorf("got fragments %+v", frags)
+	}
+}
+
+func TestGitHubClient_GetRepositoryInfo(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/org/repo":
+			fmt.Fprint(w, `{"full_name":"org/repo","default_branch":"main",
+				"stargazers_count":5,"topics":["cli"],"archived":true,
+				"license":{"spdx_id":"NOASSERTION","name":"Other"}}`)
+		case "/api/v3/repos/org/repo/languages":
+			fmt.Fprint(w, `{"Shell":10,"Go":90,"C":10}`)
+		default:
+			// No releases
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := gh.GetRepositoryInfo(context.Background(), "org", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.FullName != "org/repo" || info.DefaultBranch != "main" || info.Stars != 5 ||
+		!info.Archived || info.License != "Other" || info.LatestRelease != nil {
+		t.Errorf("got info %+v", info)
+	}
+	languages := fmt.Sprint(info.Languages)
+	if languages != "[{Go 90} {C 10} {Shell 10}]" {
+		t.Errorf("got languages %s", languages)
+	}
+}
+
+func TestGitHubClient_ListReleases(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v3/repos/org/repo/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `[{"tag_name":"v1.0.0"},{"tag_name":"v0.9.0"}]`)
+			return
+		}
+		w.Header().
+			Set("Link", fmt.Sprintf(`<%s%s?page=2>; rel="next"`, "http://"+r.Host, r.URL.Path))
+		fmt.Fprint(w, `[{"tag_name":"v2.0.0","draft":true},{"tag_name":"v1.1.0","body":"Notes"}]`)
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := gh.ListReleases(context.Background(), "org", "repo", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[0].Tag != "v1.1.0" || releases[0].Body != "Notes" ||
+		releases[1].Tag != "v1.0.0" {
+		t.Errorf("got releases %+v", releases)
+	}
+}
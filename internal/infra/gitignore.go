@@ -0,0 +1,265 @@
+package infra
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// IgnoreFileName is the name of the files listing paths the tools skip in
+// addition to those ignored by git, in .gitignore syntax. Like .gitignore
+// files, they apply to their directory and its subdirectories.
+const IgnoreFileName = ".godevmcpignore"
+
+// ignorePattern is a pattern of an ignore file, split into path segments.
+// Patterns without a slash match at any depth and start with "**".
+type ignorePattern struct {
+	segments []string
+	negate   bool // Re-include paths matched by earlier patterns
+	dirOnly  bool // Only match directories
+}
+
+// ignoreFile holds the patterns of an ignore file, which match paths relative to base.
+type ignoreFile struct {
+	base     string
+	patterns []ignorePattern
+}
+
+// ignoreRules are the ignore files applying to a directory, by increasing
+// precedence: the global excludes file, .git/info/exclude, then the
+// .gitignore and .godevmcpignore files from the top directory down.
+type ignoreRules []ignoreFile
+
+// loadIgnoreRules returns the rules applying to the files of root, except those
+// of the ignore files in root itself, which the walkers add when entering it.
+// Outside of git repositories, only .gitignore and .godevmcpignore files below
+// root apply.
+func loadIgnoreRules(root string) ignoreRules {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil
+	}
+	repoRoot, gitDir, ok := findGitRepo(root)
+	if !ok {
+		return nil
+	}
+
+	var rules ignoreRules
+	if excludesFile := globalExcludesFile(repoRoot); excludesFile != "" {
+		rules = rules.add(repoRoot, excludesFile)
+	}
+	rules = rules.add(repoRoot, filepath.Join(gitDir, "info", "exclude"))
+
+	// Ignore files of the directories between the repository root and root
+	var parents []string
+	for dir := root; dir != repoRoot; {
+		dir = filepath.Dir(dir)
+		parents = append(parents, dir)
+	}
+	for i := len(parents) - 1; i >= 0; i-- {
+		rules = rules.enter(parents[i])
+	}
+	return rules
+}
+
+// findGitRepo returns the root and the git directory of the repository containing dir.
+func findGitRepo(dir string) (root, gitDir string, ok bool) {
+	for {
+		dotGit := filepath.Join(dir, ".git")
+		if info, err := os.Stat(dotGit); err == nil {
+			if info.IsDir() {
+				return dir, dotGit, true
+			}
+			// Worktrees and submodules have a file pointing to the git directory
+			if data, err := os.ReadFile(dotGit); err == nil {
+				if target, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+					if !filepath.IsAbs(target) {
+						target = filepath.Join(dir, target)
+					}
+					return dir, target, true
+				}
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", false
+		}
+		dir = parent
+	}
+}
+
+// excludesFiles caches the excludes file of each repository root, saving a
+// git process per walk.
+var excludesFiles sync.Map
+
+// globalExcludesFile returns the path of the excludes file of the git
+// configuration (core.excludesFile), or of its default location.
+func globalExcludesFile(repoRoot string) string {
+	if excludesFile, ok := excludesFiles.Load(repoRoot); ok {
+		return excludesFile.(string)
+	}
+	excludesFile := lookupExcludesFile(repoRoot)
+	excludesFiles.Store(repoRoot, excludesFile)
+	return excludesFile
+}
+
+func lookupExcludesFile(repoRoot string) string {
+	stdout, _, exitCode, err := Run(
+		repoRoot,
+		"git",
+		"config",
+		"--path",
+		"--get",
+		"core.excludesFile",
+	)
+	if err == nil && exitCode == 0 && stdout != "" {
+		return stdout
+	}
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, "git", "ignore")
+}
+
+// enter returns the rules applying to the files of dir, a subdirectory of the
+// directory of r, adding the ignore files found in dir.
+func (r ignoreRules) enter(dir string) ignoreRules {
+	for _, name := range []string{".gitignore", IgnoreFileName} {
+		r = r.add(dir, filepath.Join(dir, name))
+	}
+	return r
+}
+
+// add returns r with the patterns of filename, if it exists, matching paths relative to base.
+func (r ignoreRules) add(base, filename string) ignoreRules {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return r
+	}
+	patterns := parseIgnorePatterns(string(data))
+	if len(patterns) == 0 {
+		return r
+	}
+	// Copy so that sibling directories don't share the appended files
+	return append(r[:len(r):len(r)], ignoreFile{base: base, patterns: patterns})
+}
+
+// ignored reports whether the file or directory name is ignored. The last
+// matching pattern of the file with the highest precedence decides.
+func (r ignoreRules) ignored(name string, isDir bool) bool {
+	if len(r) == 0 {
+		return false
+	}
+	name, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	for i := len(r) - 1; i >= 0; i-- {
+		rel, err := filepath.Rel(r[i].base, name)
+		if err != nil || !filepath.IsLocal(rel) {
+			continue
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		patterns := r[i].patterns
+		for j := len(patterns) - 1; j >= 0; j-- {
+			if patterns[j].match(parts, isDir) {
+				return !patterns[j].negate
+			}
+		}
+	}
+	return false
+}
+
+// parseIgnorePatterns parses the lines of an ignore file in .gitignore syntax.
+// Docs: https://git-scm.com/docs/gitignore#_pattern_format
+func parseIgnorePatterns(data string) []ignorePattern {
+	var patterns []ignorePattern
+	for line := range strings.Lines(data) {
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// Trailing spaces are ignored unless escaped
+		for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+			line = line[:len(line)-1]
+		}
+
+		var p ignorePattern
+		if rest, ok := strings.CutPrefix(line, "!"); ok {
+			p.negate, line = true, rest
+		} else if strings.HasPrefix(line, `\!`) || strings.HasPrefix(line, `\#`) {
+			line = line[1:]
+		}
+		if rest, ok := strings.CutSuffix(line, "/"); ok {
+			p.dirOnly, line = true, rest
+		}
+		if line == "" {
+			continue
+		}
+
+		// Patterns with a slash other than a trailing one are relative to the ignore file
+		if strings.Contains(line, "/") {
+			line = strings.TrimPrefix(line, "/")
+		} else {
+			line = "**/" + line
+		}
+		valid := true
+		for segment := range strings.SplitSeq(line, "/") {
+			if segment == "**" && len(p.segments) > 0 && p.segments[len(p.segments)-1] == "**" {
+				continue
+			}
+			// Bracket expressions are negated with "!" in git and "^" in Go
+			segment = strings.ReplaceAll(segment, "[!", "[^")
+			if _, err := path.Match(segment, ""); err != nil {
+				valid = false
+				break
+			}
+			p.segments = append(p.segments, segment)
+		}
+		if valid {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// match reports whether the pattern matches a path split into segments.
+func (p ignorePattern) match(parts []string, isDir bool) bool {
+	if p.dirOnly && !isDir {
+		return false
+	}
+	return matchSegments(p.segments, parts)
+}
+
+func matchSegments(segments, parts []string) bool {
+	if len(segments) == 0 {
+		return len(parts) == 0
+	}
+	if segments[0] == "**" {
+		// A trailing "**" matches everything inside, a leading or inner one
+		// zero or more directories
+		if len(segments) == 1 {
+			return len(parts) > 0
+		}
+		for i := range parts {
+			if matchSegments(segments[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(segments[0], parts[0]); !ok {
+		return false
+	}
+	return matchSegments(segments[1:], parts[1:])
+}
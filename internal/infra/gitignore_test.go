@@ -0,0 +1,149 @@
+package infra
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+)
+
+func TestIgnorePatterns(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "app.log", false, true},
+		{"*.log", "logs/app.log", false, true},
+		{"*.log", "app.go", false, false},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "src/build", true, true},
+		{"/build", "build", false, true},
+		{"/build", "src/build", false, false},
+		{"doc/*.txt", "doc/a.txt", false, true},
+		{"doc/*.txt", "doc/sub/a.txt", false, false},
+		{"doc/*.txt", "src/doc/a.txt", false, false},
+		{"**/fixtures", "a/b/fixtures", true, true},
+		{"a/**/b", "a/b", false, true},
+		{"a/**/b", "a/x/y/b", false, true},
+		{"out/**", "out", true, false},
+		{"out/**", "out/x/y", false, true},
+		{"file[!0-9].txt", "fileA.txt", false, true},
+		{"file[!0-9].txt", "file1.txt", false, false},
+		{`\#notes`, "#notes", false, true},
+		{`\!important`, "!important", false, true},
+		{"trailing   ", "trailing", false, true},
+	}
+
+	for _, tt := range tests {
+		patterns := parseIgnorePatterns(tt.pattern + "\n")
+		if len(patterns) != 1 {
+			t.Fatalf("%q: got %d patterns, want 1", tt.pattern, len(patterns))
+		}
+		got := patterns[0].match(strings.Split(tt.path, "/"), tt.isDir)
+		if got != tt.want {
+			t.Errorf("%q matching %q (dir: %v): got %v, want %v",
+				tt.pattern, tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	if patterns := parseIgnorePatterns("# comment\n\n!\n[z-a\n"); len(patterns) != 0 {
+		t.Errorf("got %d patterns for comments, empty and invalid lines", len(patterns))
+	}
+}
+
+// writeIgnoreTestRepo creates a git repository with ignore files of every
+// kind and returns its root.
+func writeIgnoreTestRepo(t *testing.T) string {
+	t.Helper()
+	t.Setenv("PATH", t.TempDir()) // No git, use the default excludes file
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	root := t.TempDir()
+	files := map[string]string{
+		filepath.Join(configHome, "git", "ignore"): "*.tmp\n",
+		".git/info/exclude":                        "*.log\n",
+		".git/HEAD":                                "ref: refs/heads/main\n",
+		".gitignore":                               "node_modules/\n/build\n*.gen.go\n!keep.gen.go\n",
+		IgnoreFileName:                             "vendor/\n",
+		"main.go":                                  "",
+		"main.gen.go":                              "",
+		"keep.gen.go":                              "",
+		"scratch.tmp":                              "",
+		"debug.log":                                "",
+		"build/out.go":                             "",
+		"node_modules/pkg/index.js":                "",
+		"vendor/mod/mod.go":                        "",
+		"sub/.gitignore":                           "!*.log\nlocal.txt\n",
+		"sub/sub.go":                               "",
+		"sub/trace.log":                            "",
+		"sub/local.txt":                            "",
+		"sub/build/keep.go":                        "",
+	}
+	for name, content := range files {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(root, filepath.FromSlash(name))
+		}
+		if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestFileWalker_IgnoreFiles(t *testing.T) {
+	root := writeIgnoreTestRepo(t)
+
+	got := walkFiles(t, NewFileWalker(), root, repository.WalkFileOptions{IgnoreDot: true})
+	want := []string{"keep.gen.go", "main.go", "sub/build/keep.go", "sub/sub.go", "sub/trace.log"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	// The ignore files of parent directories apply to subdirectories
+	got = walkFiles(t, NewFileWalker(), filepath.Join(root, "sub"),
+		repository.WalkFileOptions{IgnoreDot: true})
+	want = []string{"build/keep.go", "sub.go", "trace.log"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got files %v, want %v", got, want)
+	}
+
+	got = walkFiles(t, NewFileWalkerWithIgnore(false), root,
+		repository.WalkFileOptions{IgnoreDot: true})
+	if len(got) != 12 {
+		t.Errorf("got %d files without ignore files, want 12: %v", len(got), got)
+	}
+}
+
+func TestDirWalker_IgnoreFiles(t *testing.T) {
+	root := writeIgnoreTestRepo(t)
+
+	var names []string
+	err := NewDirWalker().Walk(context.Background(),
+		func(name, prefix string, isLastEntry bool) error {
+			names = append(names, prefix+name)
+			return nil
+		},
+		func(prefix string, isLastEntry bool) string { return prefix + "  " },
+		"", root, true, 3,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"keep.gen.go", "main.go", "sub", "  build", "    keep.go", "  sub.go", "  trace.log",
+	}
+	if !slices.Equal(names, want) {
+		t.Errorf("got entries %v, want %v", names, want)
+	}
+}
@@ -0,0 +1,295 @@
+package infra
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/pkg/errors"
+)
+
+// maxTreePages bounds the pages of 100 entries fetched when listing a whole tree.
+// Larger trees are reported as truncated and listed one directory at a time.
+const maxTreePages = 50
+
+// GitLabClient is a client of the REST API of gitlab.com or a self-hosted GitLab.
+// Owners can be nested groups, e.g. "group/subgroup".
+// GitLab API docs: https://docs.gitlab.com/api/rest/
+type GitLabClient struct {
+	*forgeAPI
+}
+
+func (c *GitLabClient) Kind() string {
+	return ForgeGitLab
+}
+
+type gitLabTreeEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // "tree" or "blob"
+	Path string `json:"path"`
+}
+
+type gitLabSearchBlob struct {
+	Path      string `json:"path"`
+	Data      string `json:"data"`
+	StartLine int    `json:"startline"`
+	ProjectID int    `json:"project_id"`
+}
+
+// projectPath returns the API path of a project, whose full path is URL-encoded.
+func projectPath(owner, repo string) string {
+	return "/projects/" + url.PathEscape(owner+"/"+repo)
+}
+
+func (c *GitLabClient) defaultBranch(ctx context.Context, owner, repo string) (string, error) {
+	var project struct {
+		DefaultBranch string `json:"default_branch"`
+	}
+	if _, err := c.getJSON(ctx, projectPath(owner, repo), nil, &project); err != nil {
+		return "", errors.Wrap(err, "failed to get project")
+	}
+	if project.DefaultBranch == "" {
+		return "", errors.Errorf("project %s/%s has no default branch", owner, repo)
+	}
+	return project.DefaultBranch, nil
+}
+
+// ResolveRef returns the commit SHA of a branch, tag or (abbreviated) commit SHA,
+// or of the default branch if ref is empty.
+func (c *GitLabClient) ResolveRef(ctx context.Context, owner, repo, ref string) (string, error) {
+	if ref == "" {
+		var err error
+		if ref, err = c.defaultBranch(ctx, owner, repo); err != nil {
+			return "", err
+		}
+	}
+	var commit struct {
+		ID string `json:"id"`
+	}
+	p := projectPath(owner, repo) + "/repository/commits/" + url.PathEscape(ref)
+	if _, err := c.getJSON(ctx, p, nil, &commit); err != nil {
+		return "", errors.Wrapf(err, "failed to resolve ref %s", ref)
+	}
+	return commit.ID, nil
+}
+
+// GetContent returns the content of a file, or a listing of a directory,
+// at ref (the default branch if empty).
+func (c *GitLabClient) GetContent(
+	ctx context.Context,
+	owner, repo, filePath, ref string,
+) (string, error) {
+	if ref == "" {
+		var err error
+		if ref, err = c.defaultBranch(ctx, owner, repo); err != nil {
+			return "", err
+		}
+	}
+
+	filePath = strings.Trim(filePath, "/")
+	var fileErr error
+	if filePath != "" {
+		p := projectPath(owner, repo) + "/repository/files/" + url.PathEscape(filePath) + "/raw"
+		data, _, err := c.get(ctx, p, url.Values{"ref": {ref}})
+		if err == nil {
+			return string(data), nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			return "", err
+		}
+		fileErr = err
+	}
+
+	// Not a file, so list it as a directory
+	entries, err := c.ListDir(ctx, owner, repo, ref, filePath)
+	if err != nil || (len(entries) == 0 && fileErr != nil) {
+		if fileErr != nil {
+			return "", fileErr
+		}
+		return "", err
+	}
+	return formatDirListing(filePath, entries), nil
+}
+
+// ListTree lists the tree of commit recursively, 100 entries per request.
+// Trees of more than maxTreePages pages are reported as truncated.
+func (c *GitLabClient) ListTree(
+	ctx context.Context, owner, repo, commit string,
+) ([]repository.TreeEntry, bool, error) {
+	query := url.Values{"ref": {commit}, "recursive": {"true"}}
+	return c.listTree(ctx, owner, repo, query, maxTreePages)
+}
+
+// ListDir returns the files and directories in a directory at commit.
+func (c *GitLabClient) ListDir(
+	ctx context.Context, owner, repo, commit, dirPath string,
+) ([]repository.TreeEntry, error) {
+	query := url.Values{"ref": {commit}}
+	if dirPath = strings.Trim(dirPath, "/"); dirPath != "" {
+		query.Set("path", dirPath)
+	}
+	entries, _, err := c.listTree(ctx, owner, repo, query, maxListPages)
+	return entries, err
+}
+
+func (c *GitLabClient) listTree(
+	ctx context.Context,
+	owner, repo string,
+	query url.Values,
+	maxPages int,
+) ([]repository.TreeEntry, bool, error) {
+	query.Set("per_page", "100")
+	var result []repository.TreeEntry
+	for page := 1; page <= maxPages; page++ {
+		query.Set("page", strconv.Itoa(page))
+		var entries []gitLabTreeEntry
+		header, err := c.getJSON(ctx, projectPath(owner, repo)+"/repository/tree", query, &entries)
+		if err != nil {
+			return nil, false, errors.Wrap(err, "failed to get tree")
+		}
+		for _, entry := range entries {
+			result = append(result, repository.TreeEntry{
+				Path:  entry.Path,
+				IsDir: entry.Type == "tree",
+			})
+		}
+		if header.Get("X-Next-Page") == "" {
+			return result, false, nil
+		}
+	}
+	return result, true, nil
+}
+
+// SearchCode searches code with the blob search of GitLab: in a project if
+// opt.Repo is set, in a group if opt.Org is set, otherwise in all projects
+// (which requires advanced search). Each match becomes a fragment with its line number.
+// GitLab API docs: https://docs.gitlab.com/api/search/
+func (c *GitLabClient) SearchCode(
+	ctx context.Context, query string, opt *repository.SearchCodeOption,
+) (repository.SearchCodeResult, error) {
+	if opt == nil {
+		opt = &repository.SearchCodeOption{}
+	}
+	if value(opt.Language) != "" {
+		return repository.SearchCodeResult{}, errors.New(
+			"GitLab cannot filter by language, filter by extension instead")
+	}
+	if value(opt.User) != "" {
+		return repository.SearchCodeResult{}, errors.New(
+			"GitLab cannot search the projects of a user, search a group or project instead")
+	}
+
+	query = strings.TrimSpace(query)
+	for _, q := range []struct {
+		qualifier string
+		value     *string
+	}{
+		{"filename", opt.Filename},
+		{"extension", opt.Extension},
+		{"path", opt.Path},
+	} {
+		if v := value(q.value); v != "" {
+			query += fmt.Sprintf(" %s:%s", q.qualifier, v)
+		}
+	}
+
+	searchPath := "/search"
+	repoName := ""
+	switch {
+	case value(opt.Repo) != "":
+		repoName = *opt.Repo
+		searchPath = "/projects/" + url.PathEscape(repoName) + "/search"
+	case value(opt.Org) != "":
+		searchPath = "/groups/" + url.PathEscape(*opt.Org) + "/search"
+	}
+
+	page, perPage := max(opt.Page, 1), opt.PerPage
+	if perPage <= 0 {
+		perPage = 20
+	}
+	params := url.Values{
+		"scope":    {"blobs"},
+		"search":   {query},
+		"page":     {strconv.Itoa(page)},
+		"per_page": {strconv.Itoa(perPage)},
+	}
+	var blobs []gitLabSearchBlob
+	header, err := c.getJSON(ctx, searchPath, params, &blobs)
+	if err != nil {
+		return repository.SearchCodeResult{}, err
+	}
+
+	// Search responses have no total count, so count the results so far,
+	// plus one if there is a next page
+	result := repository.SearchCodeResult{
+		Total: (page-1)*perPage + len(blobs),
+		Rate:  gitLabRateLimit(header),
+	}
+	if total, err := strconv.Atoi(header.Get("X-Total")); err == nil {
+		result.Total = total
+	} else if header.Get("X-Next-Page") != "" {
+		result.Total++
+	}
+
+	projects := make(map[int]string)
+	for _, blob := range blobs {
+		name := repoName
+		if name == "" {
+			name = c.projectName(ctx, blob.ProjectID, projects)
+		}
+		fragment := repository.CodeFragment{Text: blob.Data, Line: blob.StartLine}
+
+		// Consecutive matches in the same file are fragments of one item
+		if n := len(result.Items); n > 0 && result.Items[n-1].Repository == name &&
+			result.Items[n-1].Path == blob.Path {
+			result.Items[n-1].Fragments = append(result.Items[n-1].Fragments, fragment)
+			continue
+		}
+		result.Items = append(result.Items, repository.SearchCodeItem{
+			Name:       path.Base(blob.Path),
+			Path:       blob.Path,
+			Repository: name,
+			Fragments:  []repository.CodeFragment{fragment},
+		})
+	}
+	return result, nil
+}
+
+// projectName returns the full path of a project, looked up once per search.
+func (c *GitLabClient) projectName(ctx context.Context, id int, cache map[int]string) string {
+	if name, ok := cache[id]; ok {
+		return name
+	}
+	var project struct {
+		PathWithNamespace string `json:"path_with_namespace"`
+	}
+	name := fmt.Sprintf("project-%d", id)
+	if _, err := c.getJSON(ctx, fmt.Sprintf("/projects/%d", id), nil, &project); err == nil {
+		name = project.PathWithNamespace
+	}
+	cache[id] = name
+	return name
+}
+
+func gitLabRateLimit(header http.Header) repository.RateLimit {
+	limit, _ := strconv.Atoi(header.Get("RateLimit-Limit"))
+	remaining, _ := strconv.Atoi(header.Get("RateLimit-Remaining"))
+	var reset time.Time
+	if sec, err := strconv.ParseInt(header.Get("RateLimit-Reset"), 10, 64); err == nil {
+		reset = time.Unix(sec, 0)
+	}
+	return repository.RateLimit{Limit: limit, Remaining: remaining, Reset: reset}
+}
+
+func value(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -1,31 +1,303 @@
 package infra
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/pkg/errors"
 )
 
-type HttpClient struct{}
+// DefaultUserAgent identifies requests made by this server.
+const DefaultUserAgent = "go-dev-mcp/1.0 (+https://github.com/fpt/go-dev-mcp)"
+
+var (
+	// ErrNotFound is returned for 404 and 410 responses.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is returned for 429 responses that could not be retried.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrUpstream is returned for 5xx and other unexpected responses.
+	ErrUpstream = errors.New("upstream failure")
+)
+
+// HttpStatusError is returned for unsuccessful responses. It matches
+// ErrNotFound, ErrRateLimited or ErrUpstream with errors.Is.
+type HttpStatusError struct {
+	URL        string
+	StatusCode int
+	RetryAfter time.Duration // Requested by the server, zero if not given
+}
+
+func (e *HttpStatusError) Error() string {
+	msg := fmt.Sprintf("GET %s: %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.RetryAfter > 0 {
+		msg += fmt.Sprintf(" (retry after %s)", e.RetryAfter)
+	}
+	return msg
+}
+
+func (e *HttpStatusError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound || e.StatusCode == http.StatusGone:
+		return ErrNotFound
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return ErrUpstream
+	}
+}
+
+// HttpClientConfig configures HttpClient.
+type HttpClientConfig struct {
+	Timeout    time.Duration // Per attempt, including reading the response body
+	MaxRetries int           // Retries of 429, 5xx and network errors
+	BaseDelay  time.Duration // Backoff before the first retry, doubled for each further one
+	MaxDelay   time.Duration // Upper bound of backoff; longer Retry-After values are not waited for
+	UserAgent  string
+}
+
+// DefaultHttpClientConfig returns the configuration used by NewHttpClient
+// unless changed with ConfigureHttpClient.
+func DefaultHttpClientConfig() HttpClientConfig {
+	return HttpClientConfig{
+		Timeout:    30 * time.Second,
+		MaxRetries: 3,
+		BaseDelay:  500 * time.Millisecond,
+		MaxDelay:   10 * time.Second,
+		UserAgent:  DefaultUserAgent,
+	}
+}
+
+var (
+	httpClientConfig = DefaultHttpClientConfig()
+
+	// Shared so that connections are reused across clients.
+	// Proxies are taken from HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	httpTransport = func() *http.Transport {
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyFromEnvironment
+		return t
+	}()
+)
+
+// ConfigureHttpClient sets the configuration of clients created by NewHttpClient.
+// It must be called before any client is created.
+func ConfigureHttpClient(cfg HttpClientConfig) {
+	httpClientConfig = cfg
+}
+
+type HttpClient struct {
+	client *http.Client
+	cfg    HttpClientConfig
+}
+
+// HttpResponse is the result of a conditional GET request.
+type HttpResponse struct {
+	StatusCode   int           // http.StatusOK or http.StatusNotModified
+	Body         io.ReadCloser // Only set for http.StatusOK
+	ETag         string
+	LastModified string
+}
 
 func NewHttpClient() *HttpClient {
-	return &HttpClient{}
+	return NewHttpClientWithConfig(httpClientConfig)
+}
+
+func NewHttpClientWithConfig(cfg HttpClientConfig) *HttpClient {
+	return &HttpClient{
+		client: &http.Client{Transport: httpTransport},
+		cfg:    cfg,
+	}
 }
 
-func (c *HttpClient) HttpGet(url string) (io.ReadCloser, error) {
-	req, err := http.NewRequest(http.MethodGet, url, nil)
+// HttpGet returns the body of a successful response. Unsuccessful responses
+// are returned as *HttpStatusError.
+func (c *HttpClient) HttpGet(ctx context.Context, url string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, url, nil)
 	if err != nil {
 		return nil, err
 	}
-	resp, err := (&http.Client{}).Do(req)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to make HTTP request")
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, &HttpStatusError{URL: url, StatusCode: resp.StatusCode}
 	}
+	return resp.Body, nil
+}
 
+// HttpGetWithHeader is HttpGet with additional request headers, e.g. credentials.
+// It also returns the headers of the response.
+func (c *HttpClient) HttpGetWithHeader(
+	ctx context.Context,
+	url string,
+	header http.Header,
+) (io.ReadCloser, http.Header, error) {
+	resp, err := c.do(ctx, url, header)
+	if err != nil {
+		return nil, nil, err
+	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, errors.Wrapf(err, "non-200 response status: %d", resp.StatusCode)
+		resp.Body.Close()
+		return nil, nil, &HttpStatusError{URL: url, StatusCode: resp.StatusCode}
 	}
+	return resp.Body, resp.Header, nil
+}
 
-	return resp.Body, nil
+// HttpGetConditional makes a GET request revalidating a previous response
+// with its ETag and Last-Modified validators (either may be empty).
+// Responses other than 200 and 304 are returned as *HttpStatusError.
+func (c *HttpClient) HttpGetConditional(
+	ctx context.Context,
+	url, etag, lastModified string,
+) (*HttpResponse, error) {
+	header := http.Header{}
+	if etag != "" {
+		header.Set("If-None-Match", etag)
+	}
+	if lastModified != "" {
+		header.Set("If-Modified-Since", lastModified)
+	}
+
+	resp, err := c.do(ctx, url, header)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &HttpResponse{
+		StatusCode:   resp.StatusCode,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		result.Body = resp.Body
+		return result, nil
+	case http.StatusNotModified:
+		resp.Body.Close()
+		return result, nil
+	default:
+		resp.Body.Close()
+		return nil, &HttpStatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+}
+
+// do sends a GET request, retrying network errors, 429 and 5xx responses
+// with exponential backoff. The last 429 or 5xx response is returned as an error.
+func (c *HttpClient) do(
+	ctx context.Context,
+	url string,
+	header http.Header,
+) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		resp, err := c.attempt(ctx, url, header)
+
+		var delay time.Duration
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return nil, errors.Wrap(ctx.Err(), "failed to make HTTP request")
+			}
+			if attempt >= c.cfg.MaxRetries {
+				return nil, errors.Wrap(err, "failed to make HTTP request")
+			}
+			delay = c.backoff(attempt)
+		case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+			resp.Body.Close()
+			statusErr := &HttpStatusError{
+				URL:        url,
+				StatusCode: resp.StatusCode,
+				RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+			}
+			if attempt >= c.cfg.MaxRetries || statusErr.RetryAfter > c.cfg.MaxDelay {
+				return nil, statusErr
+			}
+			delay = max(statusErr.RetryAfter, c.backoff(attempt))
+		default:
+			return resp, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Wrap(ctx.Err(), "failed to make HTTP request")
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *HttpClient) attempt(
+	ctx context.Context,
+	url string,
+	header http.Header,
+) (*http.Response, error) {
+	if c.cfg.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.cfg.Timeout)
+		resp, err := c.send(ctx, url, header)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		// The timeout keeps applying while the body is read
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+	return c.send(ctx, url, header)
+}
+
+func (c *HttpClient) send(
+	ctx context.Context,
+	url string,
+	header http.Header,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create HTTP request")
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	if c.cfg.UserAgent != "" {
+		req.Header.Set("User-Agent", c.cfg.UserAgent)
+	}
+	return c.client.Do(req)
+}
+
+// backoff returns the delay before retry attempt+1: the base delay doubled
+// per attempt, capped at the maximum delay, with jitter of up to a half.
+func (c *HttpClient) backoff(attempt int) time.Duration {
+	delay := c.cfg.BaseDelay << attempt
+	if delay <= 0 || delay > c.cfg.MaxDelay {
+		delay = c.cfg.MaxDelay
+	}
+	if half := int64(delay / 2); half > 0 {
+		delay = time.Duration(half + rand.Int64N(half+1))
+	}
+	return delay
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0)
+	}
+	return 0
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (r *cancelOnClose) Close() error {
+	defer r.cancel()
+	return r.ReadCloser.Close()
 }
@@ -0,0 +1,130 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testHttpClient() *HttpClient {
+	cfg := DefaultHttpClientConfig()
+	cfg.BaseDelay = time.Millisecond
+	cfg.MaxDelay = 50 * time.Millisecond
+	return NewHttpClientWithConfig(cfg)
+}
+
+func TestHttpGet_RetriesServerErrors(t *testing.T) {
+	attempts := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if got := r.Header.Get("User-Agent"); got != DefaultUserAgent {
+			t.Errorf("User-Agent = %q, want %q", got, DefaultUserAgent)
+		}
+		switch attempts {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = io.WriteString(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	body, err := testHttpClient().HttpGet(context.Background(), srv.URL)
+	if err != nil {
+		t.Fatalf("HttpGet() error = %v", err)
+	}
+	defer body.Close()
+
+	data, _ := io.ReadAll(body)
+	if string(data) != "ok" || attempts != 3 {
+		t.Errorf("HttpGet() = %q after %d attempts, want \"ok\" after 3", data, attempts)
+	}
+}
+
+func TestHttpGet_TypedErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		header   map[string]string
+		want     error
+		attempts int
+	}{
+		{"not found", http.StatusNotFound, nil, ErrNotFound, 1},
+		{"upstream failure", http.StatusBadGateway, nil, ErrUpstream, 4},
+		{
+			"rate limited beyond max delay",
+			http.StatusTooManyRequests,
+			map[string]string{"Retry-After": "120"},
+			ErrRateLimited,
+			1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			handler := func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				for k, v := range tt.header {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}
+			srv := httptest.NewServer(http.HandlerFunc(handler))
+			defer srv.Close()
+
+			_, err := testHttpClient().HttpGet(context.Background(), srv.URL)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("HttpGet() error = %v, want %v", err, tt.want)
+			}
+			var statusErr *HttpStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tt.status {
+				t.Errorf("HttpGet() error = %#v, want *HttpStatusError %d", err, tt.status)
+			}
+			if attempts != tt.attempts {
+				t.Errorf("attempts = %d, want %d", attempts, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestHttpGet_ContextCanceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := testHttpClient().HttpGet(ctx, srv.URL)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("HttpGet() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"Wed, 01 Jan 2025 00:00:30 GMT", 30 * time.Second},
+		{"Tue, 31 Dec 2024 23:59:00 GMT", 0},
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package infra
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/google/go-github/v74/github"
+	"github.com/pkg/errors"
+	"golang.org/x/mod/module"
+)
+
+// maxArchiveSize bounds the size of downloaded source archives and the total
+// size of their files. The Go module proxy serves modules of up to 500 MB, but
+// packages worth outlining are much smaller.
+const maxArchiveSize = 200 << 20
+
+// DefaultGoProxy is the Go module proxy used if GOPROXY names none.
+const DefaultGoProxy = "https://proxy.golang.org"
+
+// DefaultSourceCacheSize is the total size of the archives a SourceCache
+// created for a server keeps.
+const DefaultSourceCacheSize = 256 << 20
+
+// SourceCache keeps the source archives read last in memory, one per key, up
+// to a total size. Archives are never updated, so keys must name immutable
+// versions, e.g. a commit SHA or a module version.
+type SourceCache struct {
+	mu       sync.Mutex
+	maxSize  int64
+	size     int64
+	archives []cachedArchive // Least recently used first
+}
+
+type cachedArchive struct {
+	key  string
+	size int64
+	zr   *zip.Reader
+}
+
+// NewSourceCache creates a cache of archives of up to maxSize bytes in total.
+func NewSourceCache(maxSize int64) *SourceCache {
+	return &SourceCache{maxSize: maxSize}
+}
+
+// Get returns the zip archive stored for key, calling fetch to download it if
+// there is none. Archives too large to keep are returned without being stored.
+func (c *SourceCache) Get(key string, fetch func() ([]byte, error)) (*zip.Reader, error) {
+	c.mu.Lock()
+	if i := slices.IndexFunc(c.archives, func(a cachedArchive) bool { return a.key == key }); i >= 0 {
+		archive := c.archives[i]
+		c.archives = append(slices.Delete(c.archives, i, i+1), archive)
+		c.mu.Unlock()
+		return archive.zr, nil
+	}
+	c.mu.Unlock()
+
+	data, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	zr, err := openZip(data)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	size := int64(len(data))
+	if size > c.maxSize ||
+		slices.ContainsFunc(c.archives, func(a cachedArchive) bool { return a.key == key }) {
+		return zr, nil
+	}
+	for c.size+size > c.maxSize {
+		c.size -= c.archives[0].size
+		c.archives = slices.Delete(c.archives, 0, 1)
+	}
+	c.archives = append(c.archives, cachedArchive{key: key, size: size, zr: zr})
+	c.size += size
+	return zr, nil
+}
+
+// GoModuleProxy is a client of a Go module proxy.
+// Protocol docs: https://go.dev/ref/mod#goproxy-protocol
+type GoModuleProxy struct {
+	httpcli *HttpClient
+	baseURL string
+}
+
+// NewGoModuleProxy creates a client of the first proxy URL in GOPROXY,
+// or of DefaultGoProxy.
+func NewGoModuleProxy() *GoModuleProxy {
+	return NewGoModuleProxyWithURL(goProxyURL(os.Getenv("GOPROXY")))
+}
+
+// NewGoModuleProxyWithURL creates a client of the proxy at baseURL.
+func NewGoModuleProxyWithURL(baseURL string) *GoModuleProxy {
+	return &GoModuleProxy{httpcli: NewHttpClient(), baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// goProxyURL returns the first URL of a GOPROXY list, skipping "direct" and "off".
+func goProxyURL(goproxy string) string {
+	for entry := range strings.FieldsFuncSeq(goproxy, func(r rune) bool {
+		return r == ',' || r == '|'
+	}) {
+		if strings.HasPrefix(entry, "https://") || strings.HasPrefix(entry, "http://") {
+			return entry
+		}
+	}
+	return DefaultGoProxy
+}
+
+// Resolve returns the canonical version of a module that query names: a
+// version, a branch or a commit, or the latest version if query is empty.
+func (p *GoModuleProxy) Resolve(ctx context.Context, modulePath, query string) (string, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid module path %q", modulePath)
+	}
+	u := p.baseURL + "/" + escaped + "/@latest"
+	if query != "" && query != "latest" {
+		escapedQuery, err := module.EscapeVersion(query)
+		if err != nil {
+			return "", errors.Wrapf(err, "invalid version %q", query)
+		}
+		u = p.baseURL + "/" + escaped + "/@v/" + escapedQuery + ".info"
+	}
+	body, err := p.httpcli.HttpGet(ctx, u)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve %s@%s", modulePath, cmp.Or(query, "latest"))
+	}
+	defer body.Close()
+
+	var info struct {
+		Version string `json:"Version"`
+	}
+	if err := json.NewDecoder(body).Decode(&info); err != nil {
+		return "", errors.Wrap(err, "failed to decode version info")
+	}
+	return info.Version, nil
+}
+
+// DownloadZip downloads the zip of a module version. Its files are prefixed
+// with "module@version/".
+func (p *GoModuleProxy) DownloadZip(
+	ctx context.Context,
+	modulePath, version string,
+) ([]byte, error) {
+	escaped, err := module.EscapePath(modulePath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid module path %q", modulePath)
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid version %q", version)
+	}
+	body, err := p.httpcli.HttpGet(ctx, p.baseURL+"/"+escaped+"/@v/"+escapedVersion+".zip")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to download %s@%s", modulePath, version)
+	}
+	defer body.Close()
+
+	return readLimited(body)
+}
+
+// DownloadTarball downloads the tarball of a commit of a repository and
+// repacks it into a zip archive, so that it is read like module zips. The
+// top-level directory GitHub puts all files in is stripped.
+func (c *GitHubClient) DownloadTarball(
+	ctx context.Context,
+	owner, repo, commit string,
+) ([]byte, error) {
+	link, resp, err := c.Repositories.GetArchiveLink(
+		ctx, owner, repo, github.Tarball, contentOptions(commit), 3)
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return nil, errors.Wrapf(ErrNotFound, "tarball of %s/%s@%s", owner, repo, commit)
+		}
+		return nil, errors.Wrap(err, "failed to get tarball link")
+	}
+
+	// GitHub Enterprise serves the tarball from the API host, which requires
+	// the credentials of the client. Other hosts get a link with a token.
+	var header http.Header
+	if c.token != "" && link.Host == c.BaseURL.Host {
+		header = http.Header{"Authorization": {"Bearer " + c.token}}
+	}
+	body, _, err := c.httpcli.HttpGetWithHeader(ctx, link.String(), header)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download tarball")
+	}
+	defer body.Close()
+
+	data, err := readLimited(body)
+	if err != nil {
+		return nil, err
+	}
+	return tarGzToZip(data)
+}
+
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to download archive")
+	}
+	if len(data) > maxArchiveSize {
+		return nil, errors.Errorf("archive is larger than %d MB", maxArchiveSize>>20)
+	}
+	return data, nil
+}
+
+// openZip reads a zip archive, refusing archives whose files are larger than
+// maxArchiveSize in total.
+func openZip(data []byte) (*zip.Reader, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read zip archive")
+	}
+	var total uint64
+	for _, f := range zr.File {
+		if total += f.UncompressedSize64; total > maxArchiveSize {
+			return nil, errors.Errorf("archive is larger than %d MB", maxArchiveSize>>20)
+		}
+	}
+	return zr, nil
+}
+
+// tarGzToZip repacks the regular files of a gzipped tarball into a zip archive,
+// stripping the top-level directory.
+func tarGzToZip(data []byte) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read tarball")
+	}
+	defer gz.Close()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	tr := tar.NewReader(gz)
+	var total int64
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read tarball")
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		_, name, ok := strings.Cut(hdr.Name, "/")
+		if !ok {
+			continue
+		}
+		if !fs.ValidPath(name) {
+			return nil, errors.Errorf("invalid file name %q in archive", name)
+		}
+		if total += hdr.Size; total > maxArchiveSize {
+			return nil, errors.Errorf("archive is larger than %d MB", maxArchiveSize>>20)
+		}
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to repack tarball")
+		}
+		if _, err := io.Copy(w, tr); err != nil {
+			return nil, errors.Wrapf(err, "failed to repack %s", name)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to repack tarball")
+	}
+	return buf.Bytes(), nil
+}
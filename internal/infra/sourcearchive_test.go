@@ -0,0 +1,163 @@
+package infra
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func tarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		hdr := &tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))}
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestGitHubClient_DownloadTarball(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+
+	archives := map[string][]byte{
+		"c1": tarGz(t, map[string]string{
+			"o-r-c1/go.mod":     "module example.com/r\n",
+			"o-r-c1/pkg/pkg.go": "package pkg\n",
+		}),
+		"c2": tarGz(t, map[string]string{"o-r-c2/../../evil.go": "package evil\n"}),
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/repos/o/r/tarball/c1", "/api/v3/repos/o/r/tarball/c2",
+			"/api/v3/repos/o/r/tarball/gone":
+			commit := filepath.Base(r.URL.Path)
+			http.Redirect(w, r, "http://"+r.Host+"/download/"+commit, http.StatusFound)
+		case "/download/c1", "/download/c2":
+			if got := r.Header.Get("Authorization"); got != "Bearer x" {
+				http.Error(w, "bad credentials: "+got, http.StatusUnauthorized)
+				return
+			}
+			w.Write(archives[filepath.Base(r.URL.Path)])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	gh, err := NewGitHubEnterpriseClient(
+		context.Background(), GitHubHost{Host: "ghe.test", APIURL: srv.URL, Token: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := gh.DownloadTarball(context.Background(), "o", "r", "c1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := openZip(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := fs.ReadFile(zr, "pkg/pkg.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "package pkg\n" {
+		t.Errorf("got content %q", content)
+	}
+
+	if _, err := gh.DownloadTarball(context.Background(), "o", "r", "c2"); err == nil {
+		t.Error("expected error for a file outside of the archive root")
+	}
+
+	// Unknown commits and tarballs that are gone after the redirect
+	for _, commit := range []string{"missing", "gone"} {
+		_, err := gh.DownloadTarball(context.Background(), "o", "r", commit)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("%s: expected ErrNotFound, got %v", commit, err)
+		}
+	}
+}
+
+func TestSourceCache(t *testing.T) {
+	archive := func(name string) []byte {
+		var buf bytes.Buffer
+		zw := zip.NewWriter(&buf)
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store})
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write(bytes.Repeat([]byte("x"), 100))
+		if err := zw.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return buf.Bytes()
+	}
+	size := int64(len(archive("a")))
+
+	var fetched []string
+	cache := NewSourceCache(2 * size)
+	get := func(key string) {
+		t.Helper()
+		zr, err := cache.Get(key, func() ([]byte, error) {
+			fetched = append(fetched, key)
+			return archive(key), nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fs.Stat(zr, key); err != nil {
+			t.Errorf("archive of %s: %v", key, err)
+		}
+	}
+
+	// "b" is evicted as the least recently used archive when "c" is added
+	for _, key := range []string{"a", "b", "a", "c", "a", "b"} {
+		get(key)
+	}
+	if want := []string{"a", "b", "c", "b"}; !slices.Equal(fetched, want) {
+		t.Errorf("fetched %v, want %v", fetched, want)
+	}
+
+	_, err := cache.Get("d", func() ([]byte, error) { return []byte("not a zip"), nil })
+	if err == nil {
+		t.Error("expected error for an invalid archive")
+	}
+}
+
+func TestGoProxyURL(t *testing.T) {
+	tests := map[string]string{
+		"":                                   DefaultGoProxy,
+		"direct":                             DefaultGoProxy,
+		"off":                                DefaultGoProxy,
+		"https://goproxy.example.com,direct": "https://goproxy.example.com",
+		"direct|http://localhost:3000":       "http://localhost:3000",
+	}
+	for goproxy, want := range tests {
+		if got := goProxyURL(goproxy); got != want {
+			t.Errorf("goProxyURL(%q) = %q, want %q", goproxy, got, want)
+		}
+	}
+}
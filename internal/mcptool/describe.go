@@ -0,0 +1,43 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// DescribeSymbolArgs represents arguments for the describe_symbol tool.
+type DescribeSymbolArgs struct {
+	Directory      string `json:"directory"`
+	Symbol         string `json:"symbol"`
+	MaxSourceLines int    `json:"max_source_lines,omitempty"`
+}
+
+func describeSymbol(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	args DescribeSymbolArgs,
+) (*mcp.CallToolResult, error) {
+	if args.Directory == "" {
+		return mcp.NewToolResultError("Missing directory path"), nil
+	}
+	if args.Symbol == "" {
+		return mcp.NewToolResultError("Missing symbol"), nil
+	}
+
+	maxLines := args.MaxSourceLines
+	if maxLines == 0 {
+		maxLines = app.DefaultMaxSourceLines
+	}
+
+	desc, err := app.DescribeSymbol(ctx, args.Directory, args.Symbol)
+	if err != nil {
+		slog.ErrorContext(ctx, "describeSymbol", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Error describing symbol: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(app.FormatSymbolDescription(desc, maxLines)), nil
+}
@@ -0,0 +1,77 @@
+package tool
+
+import (
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/fpt/go-dev-mcp/internal/repository"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FileFilterArgs represents the file selection options shared by the tools
+// walking local directories.
+type FileFilterArgs struct {
+	Include     []string `json:"include,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"`
+	MaxFileSize int64    `json:"max_file_size,omitempty"`
+}
+
+// walkOptions returns the options of the walk selecting the files.
+func (a FileFilterArgs) walkOptions() repository.WalkFileOptions {
+	return repository.WalkFileOptions{
+		Include:     a.Include,
+		Exclude:     a.Exclude,
+		MaxFileSize: a.MaxFileSize,
+	}
+}
+
+// addFileFilterOptions adds the parameters of FileFilterArgs to a tool.
+func addFileFilterOptions(tool *mcp.Tool) {
+	for _, opt := range []mcp.ToolOption{
+		mcp.WithArray("include",
+			mcp.WithStringItems(),
+			mcp.Description(
+				"Only visit files whose path relative to the directory matches one of these"+
+					" doublestar globs (e.g., '**/*.{go,mod}', 'cmd/**'); globs prefixed with '!'"+
+					" exclude files (e.g., '!**/*_test.go')",
+			),
+		),
+		mcp.WithArray("exclude",
+			mcp.WithStringItems(),
+			mcp.Description(
+				"Skip files and directories whose path relative to the directory matches one of"+
+					" these doublestar globs (e.g., '**/testdata', '**/*.pb.go')",
+			),
+		),
+		mcp.WithNumber("max_file_size",
+			mcp.Description("Skip files larger than this many bytes (default: no limit)"),
+		),
+	} {
+		opt(tool)
+	}
+}
+
+// IgnoreArgs represents the option of the tools walking local directories to
+// visit the files ignored by git and .godevmcpignore files.
+type IgnoreArgs struct {
+	NoIgnore bool `json:"no_ignore,omitempty"`
+}
+
+// fileWalker returns a file walker honoring the ignore option.
+func (a IgnoreArgs) fileWalker() repository.FileWalker {
+	return infra.NewFileWalkerWithIgnore(!a.NoIgnore)
+}
+
+// dirWalker returns a directory walker honoring the ignore option.
+func (a IgnoreArgs) dirWalker() repository.DirWalker {
+	return infra.NewDirWalkerWithIgnore(!a.NoIgnore)
+}
+
+// addIgnoreOption adds the parameter of IgnoreArgs to a tool.
+func addIgnoreOption(tool *mcp.Tool) {
+	mcp.WithBoolean("no_ignore",
+		mcp.DefaultBool(false),
+		mcp.Description(
+			"Include files ignored by .gitignore, .git/info/exclude, the global git excludes file"+
+				" and .godevmcpignore (default: false)",
+		),
+	)(tool)
+}
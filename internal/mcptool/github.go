@@ -4,24 +4,35 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
 	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/fpt/go-dev-mcp/internal/repository"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // SearchCodeGitHubArgs represents arguments for GitHub code search
 type SearchCodeGitHubArgs struct {
-	Query    string `json:"query"`
-	Language string `json:"language"`
-	Repo     string `json:"repo"`
+	Query     string `json:"query"`
+	Language  string `json:"language,omitempty"`
+	Repo      string `json:"repo,omitempty"`
+	Org       string `json:"org,omitempty"`
+	User      string `json:"user,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Filename  string `json:"filename,omitempty"`
+	Extension string `json:"extension,omitempty"`
+	Host      string `json:"host,omitempty"`
+	Page      int    `json:"page,omitempty"`
+	PerPage   int    `json:"per_page,omitempty"`
 }
 
 // GitHubContentArgs represents arguments for GitHub content retrieval
 type GitHubContentArgs struct {
 	Repo   string `json:"repo"`
 	Path   string `json:"path"`
+	Ref    string `json:"ref,omitempty"`
 	Offset int    `json:"offset,omitempty"`
 	Limit  int    `json:"limit,omitempty"`
 }
@@ -30,121 +41,586 @@ type GitHubContentArgs struct {
 type GitHubTreeArgs struct {
 	Repo      string `json:"repo"`
 	Path      string `json:"path"`
+	Ref       string `json:"ref,omitempty"`
 	IgnoreDot bool   `json:"ignore_dot"`
 	MaxDepth  int    `json:"max_depth,omitempty"`
 }
 
 func searchCodeGitHub(
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	args SearchCodeGitHubArgs,
-) (*mcp.CallToolResult, error) {
-	if args.Query == "" {
-		return mcp.NewToolResultError("Missing search query"), nil
-	}
-	if args.Language == "" {
-		return mcp.NewToolResultError("Missing language"), nil
-	}
-	if args.Repo != "" {
-		// Validate the repo format
-		parts := strings.Split(args.Repo, "/")
-		if len(parts) != 2 {
-			return mcp.NewToolResultError("Invalid repo format, expected 'owner/repo'"), nil
+	clients *infra.Forges,
+) mcp.TypedToolHandlerFunc[SearchCodeGitHubArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args SearchCodeGitHubArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Query == "" {
+			return mcp.NewToolResultError("Missing search query"), nil
+		}
+		if args.PerPage > 100 {
+			return mcp.NewToolResultError("per_page must not exceed 100"), nil
 		}
+		host, repo := args.Host, ""
+		if args.Repo != "" {
+			repoHost, owner, name, err := infra.ParseRepo(args.Repo)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if repoHost != "" {
+				host = repoHost
+			}
+			repo = owner + "/" + name
+		}
+		forge, err := clients.Forge(ctx, host)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.ForgeSearchCode(ctx, forge, args.Query, &repository.SearchCodeOption{
+			Language:  &args.Language,
+			Repo:      &repo,
+			Org:       &args.Org,
+			User:      &args.User,
+			Path:      &args.Path,
+			Filename:  &args.Filename,
+			Extension: &args.Extension,
+			Page:      args.Page,
+			PerPage:   args.PerPage,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "searchCodeGitHub", "error", err)
+			return forgeErrorResult(forge, "searching code", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	gh, err := infra.NewGitHubClient()
-	if err != nil {
-		slog.ErrorContext(ctx, "searchCodeGitHub", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error creating GitHub client: %v", err)), nil
+func getGitHubContent(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubContentArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubContentArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+
+		forge, owner, repo, err := resolveForgeRepo(ctx, clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if args.Path == "" {
+			return mcp.NewToolResultError("Missing path"), nil
+		}
+
+		// Read at the resolved commit so that the echoed SHA matches the content
+		commit, err := forge.ResolveRef(ctx, owner, repo, args.Ref)
+		if err != nil {
+			slog.ErrorContext(ctx, "getGitHubContent", "error", err)
+			return forgeErrorResult(forge, "resolving ref", err), nil
+		}
+
+		content, err := forge.GetContent(ctx, owner, repo, args.Path, commit)
+		if err != nil {
+			slog.ErrorContext(ctx, "getGitHubContent", "error", err)
+			return forgeErrorResult(forge, "getting content", err), nil
+		}
+
+		// Apply offset/limit if specified
+		if args.Offset > 0 || args.Limit > 0 {
+			content = paginateContent(content, args.Offset, args.Limit)
+		}
+
+		return mcp.NewToolResultText(commitLine(args.Ref, commit) + content), nil
 	}
+}
 
-	result, err := app.GitHubSearchCode(ctx, gh, args.Query, &args.Language, &args.Repo)
-	if err != nil {
-		slog.ErrorContext(ctx, "searchCodeGitHub", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error searching code: %v", err)), nil
+func getGitHubTree(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubTreeArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubTreeArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+
+		forge, owner, repo, err := resolveForgeRepo(ctx, clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		commit, err := forge.ResolveRef(ctx, owner, repo, args.Ref)
+		if err != nil {
+			return forgeErrorResult(forge, "resolving ref", err), nil
+		}
+
+		// Create a string builder for the tree output
+		b := strings.Builder{}
+		b.WriteString(commitLine(args.Ref, commit))
+		b.WriteString(fmt.Sprintf("%s:%s\n", args.Repo, args.Path))
+
+		// Set default max depth if not specified
+		maxDepth := args.MaxDepth
+		if maxDepth == 0 {
+			maxDepth = 3 // More conservative default for GitHub trees due to network overhead
+		}
+
+		// Generate the tree using our PrintForgeTree function
+		if err := app.PrintForgeTree(
+			ctx, &b, forge, owner, repo, commit, args.Path, args.IgnoreDot, maxDepth,
+		); err != nil {
+			return forgeErrorResult(forge, "generating tree", err), nil
+		}
+
+		return mcp.NewToolResultText(b.String()), nil
 	}
+}
 
-	return mcp.NewToolResultText(result), nil
+// SearchGitHubIssuesArgs represents arguments for GitHub issue and pull request search
+type SearchGitHubIssuesArgs struct {
+	Query string `json:"query"`
+	Repo  string `json:"repo,omitempty"`
+	Host  string `json:"host,omitempty"`
+	State string `json:"state,omitempty"`
+	Kind  string `json:"kind,omitempty"`
+	Page  int    `json:"page,omitempty"`
 }
 
-func getGitHubContent(
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	args GitHubContentArgs,
-) (*mcp.CallToolResult, error) {
-	if args.Repo == "" {
-		return mcp.NewToolResultError("Missing repo"), nil
-	}
+// ReadGitHubIssueArgs represents arguments for reading a GitHub issue
+type ReadGitHubIssueArgs struct {
+	Repo            string `json:"repo"`
+	Number          int    `json:"number"`
+	CommentPage     int    `json:"comment_page,omitempty"`
+	CommentsPerPage int    `json:"comments_per_page,omitempty"`
+}
+
+// ReadGitHubPullRequestArgs represents arguments for reading a GitHub pull request
+type ReadGitHubPullRequestArgs struct {
+	Repo       string `json:"repo"`
+	Number     int    `json:"number"`
+	DiffOffset int    `json:"diff_offset,omitempty"`
+	DiffLimit  int    `json:"diff_limit,omitempty"`
+}
+
+// GitHubLogArgs represents arguments for listing GitHub commit history
+type GitHubLogArgs struct {
+	Repo  string `json:"repo"`
+	Path  string `json:"path,omitempty"`
+	Ref   string `json:"ref,omitempty"`
+	Limit int    `json:"limit,omitempty"`
+}
+
+// GitHubBlameArgs represents arguments for blaming a file on GitHub
+type GitHubBlameArgs struct {
+	Repo      string `json:"repo"`
+	Path      string `json:"path"`
+	Ref       string `json:"ref,omitempty"`
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// GitHubCompareArgs represents arguments for comparing two GitHub refs
+type GitHubCompareArgs struct {
+	Repo   string `json:"repo"`
+	Base   string `json:"base"`
+	Head   string `json:"head"`
+	Path   string `json:"path,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// SearchGitHubReposArgs represents arguments for GitHub repository search
+type SearchGitHubReposArgs struct {
+	Query    string `json:"query"`
+	Language string `json:"language,omitempty"`
+	MinStars int    `json:"min_stars,omitempty"`
+	Sort     string `json:"sort,omitempty"`
+	Host     string `json:"host,omitempty"`
+	Page     int    `json:"page,omitempty"`
+}
+
+// GitHubRepoInfoArgs represents arguments for GitHub repository metadata
+type GitHubRepoInfoArgs struct {
+	Repo string `json:"repo"`
+}
 
-	parts := strings.Split(args.Repo, "/")
-	if len(parts) != 2 {
-		return mcp.NewToolResultError("Invalid repo format, expected 'owner/repo'"), nil
+// GitHubReleasesArgs represents arguments for reading GitHub releases
+type GitHubReleasesArgs struct {
+	Repo   string `json:"repo"`
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+func searchGitHubIssues(
+	clients *infra.Forges,
+) mcp.TypedToolHandlerFunc[SearchGitHubIssuesArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args SearchGitHubIssuesArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Query == "" {
+			return mcp.NewToolResultError("Missing search query"), nil
+		}
+		if args.State != "" && args.State != "open" && args.State != "closed" {
+			return mcp.NewToolResultError("Invalid state, expected 'open' or 'closed'"), nil
+		}
+		if args.Kind != "" && args.Kind != "issue" && args.Kind != "pr" {
+			return mcp.NewToolResultError("Invalid kind, expected 'issue' or 'pr'"), nil
+		}
+
+		host, repo := args.Host, ""
+		if args.Repo != "" {
+			repoHost, owner, name, err := infra.ParseRepo(args.Repo)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if repoHost != "" {
+				host = repoHost
+			}
+			repo = owner + "/" + name
+		}
+		gh, err := clients.Client(host)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubSearchIssues(ctx, gh, args.Query, &repository.SearchIssuesOption{
+			Repo:  &repo,
+			State: &args.State,
+			Kind:  &args.Kind,
+			Page:  args.Page,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "searchGitHubIssues", "error", err)
+			return forgeErrorResult(gh, "searching issues", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
-	owner := parts[0]
-	repo := parts[1]
+}
 
-	if args.Path == "" {
-		return mcp.NewToolResultError("Missing path"), nil
+func readGitHubIssue(clients *infra.Forges) mcp.TypedToolHandlerFunc[ReadGitHubIssueArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args ReadGitHubIssueArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+		if args.Number <= 0 {
+			return mcp.NewToolResultError("Missing issue number"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubReadIssue(
+			ctx, gh, owner, repo, args.Number, args.CommentPage, args.CommentsPerPage,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "readGitHubIssue", "error", err)
+			return forgeErrorResult(gh, "reading issue", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	gh, err := infra.NewGitHubClient()
-	if err != nil {
-		slog.ErrorContext(ctx, "getGitHubContent", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error creating GitHub client: %v", err)), nil
+func readGitHubPullRequest(
+	clients *infra.Forges,
+) mcp.TypedToolHandlerFunc[ReadGitHubPullRequestArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args ReadGitHubPullRequestArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+		if args.Number <= 0 {
+			return mcp.NewToolResultError("Missing pull request number"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubReadPullRequest(
+			ctx, gh, owner, repo, args.Number, args.DiffOffset, args.DiffLimit,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "readGitHubPullRequest", "error", err)
+			return forgeErrorResult(gh, "reading pull request", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	content, err := gh.GetContent(ctx, owner, repo, args.Path)
-	if err != nil {
-		slog.ErrorContext(ctx, "getGitHubContent", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error getting content: %v", err)), nil
+func gitHubLog(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubLogArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubLogArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubLog(ctx, gh, owner, repo, &repository.ListCommitsOption{
+			Ref:   args.Ref,
+			Path:  args.Path,
+			Limit: args.Limit,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "gitHubLog", "error", err)
+			return forgeErrorResult(gh, "listing commits", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
+
+func gitHubBlame(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubBlameArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubBlameArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+		if args.Path == "" {
+			return mcp.NewToolResultError("Missing path"), nil
+		}
+		if args.EndLine > 0 && args.EndLine < args.StartLine {
+			return mcp.NewToolResultError("end_line must not be before start_line"), nil
+		}
 
-	// Apply offset/limit if specified
-	if args.Offset > 0 || args.Limit > 0 {
-		content = paginateContent(content, args.Offset, args.Limit)
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubBlame(
+			ctx, gh, owner, repo, args.Ref, args.Path, args.StartLine, args.EndLine,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "gitHubBlame", "error", err)
+			return forgeErrorResult(gh, "blaming file", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	return mcp.NewToolResultText(content), nil
+func gitHubCompare(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubCompareArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubCompareArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+		if args.Base == "" || args.Head == "" {
+			return mcp.NewToolResultError("Missing base or head"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		summary, diff, err := app.GitHubCompare(
+			ctx, gh, owner, repo, args.Base, args.Head, args.Path,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "gitHubCompare", "error", err)
+			return forgeErrorResult(gh, "comparing refs", err), nil
+		}
+
+		limit := args.Limit
+		if limit <= 0 {
+			limit = app.DefaultLinesPerPage
+		}
+		// Like read_github_pull_request, later pages only contain the diff
+		var b strings.Builder
+		if args.Offset == 0 {
+			b.WriteString(summary)
+			b.WriteString("\n")
+		}
+		b.WriteString(app.FormatDiffPage(diff, "offset", args.Offset, limit))
+		return mcp.NewToolResultText(b.String()), nil
+	}
 }
 
-func getGitHubTree(
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	args GitHubTreeArgs,
-) (*mcp.CallToolResult, error) {
-	if args.Repo == "" {
-		return mcp.NewToolResultError("Missing repo"), nil
+func searchGitHubRepos(
+	clients *infra.Forges,
+) mcp.TypedToolHandlerFunc[SearchGitHubReposArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args SearchGitHubReposArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Query == "" {
+			return mcp.NewToolResultError("Missing search query"), nil
+		}
+		if args.Sort != "" && !slices.Contains(app.RepoSortOrders, args.Sort) {
+			return mcp.NewToolResultError(fmt.Sprintf(
+				"Invalid sort, expected one of %s", strings.Join(app.RepoSortOrders, ", "),
+			)), nil
+		}
+
+		gh, err := clients.Client(args.Host)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubSearchRepos(ctx, gh, args.Query, &repository.SearchReposOption{
+			Language: &args.Language,
+			MinStars: args.MinStars,
+			Sort:     args.Sort,
+			Page:     args.Page,
+		})
+		if err != nil {
+			slog.ErrorContext(ctx, "searchGitHubRepos", "error", err)
+			return forgeErrorResult(gh, "searching repositories", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	parts := strings.Split(args.Repo, "/")
-	if len(parts) != 2 {
-		return mcp.NewToolResultError("Invalid repo format, expected 'owner/repo'"), nil
+func gitHubRepoInfo(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubRepoInfoArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubRepoInfoArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubRepoInfo(ctx, gh, owner, repo)
+		if err != nil {
+			slog.ErrorContext(ctx, "gitHubRepoInfo", "error", err)
+			return forgeErrorResult(gh, "reading repository", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
-	owner := parts[0]
-	repo := parts[1]
+}
 
-	gh, err := infra.NewGitHubClient()
-	if err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Error creating GitHub client: %v", err)), nil
+func gitHubReleases(clients *infra.Forges) mcp.TypedToolHandlerFunc[GitHubReleasesArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args GitHubReleasesArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Repo == "" {
+			return mcp.NewToolResultError("Missing repo"), nil
+		}
+
+		gh, owner, repo, err := resolveGitHubRepo(clients, args.Repo)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, err := app.GitHubReleases(
+			ctx, gh, owner, repo, args.From, args.To, args.Offset, args.Limit,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "gitHubReleases", "error", err)
+			return forgeErrorResult(gh, "reading releases", err), nil
+		}
+
+		return mcp.NewToolResultText(result), nil
 	}
+}
 
-	// Create a string builder for the tree output
-	b := strings.Builder{}
-	b.WriteString(fmt.Sprintf("%s/%s:%s\n", owner, repo, args.Path))
+// commitLine reports the commit a ref was resolved to.
+func commitLine(ref, commit string) string {
+	if ref == "" {
+		ref = "default branch"
+	}
+	return fmt.Sprintf("Commit: %s (%s)\n", commit, ref)
+}
 
-	// Set default max depth if not specified
-	maxDepth := args.MaxDepth
-	if maxDepth == 0 {
-		maxDepth = 3 // More conservative default for GitHub trees due to network overhead
+// resolveGitHubRepo returns the client of the host of a repository argument
+// in "owner/repo" or "host/owner/repo" format, and the owner and repository name.
+func resolveGitHubRepo(
+	clients *infra.Forges,
+	arg string,
+) (*infra.GitHubClient, string, string, error) {
+	host, owner, repo, err := infra.ParseRepo(arg)
+	if err != nil {
+		return nil, "", "", err
 	}
+	gh, err := clients.Client(host)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return gh, owner, repo, nil
+}
 
-	// Generate the tree using our PrintGitHubTree function
-	if err := app.PrintGitHubTree(ctx, &b, gh, owner, repo, args.Path, args.IgnoreDot, maxDepth); err != nil {
-		return mcp.NewToolResultError(fmt.Sprintf("Error generating tree: %v", err)), nil
+// resolveForgeRepo is like resolveGitHubRepo, but the host may also be a GitLab
+// or Gitea host, and the owner a nested GitLab group ("host/group/subgroup/repo").
+func resolveForgeRepo(
+	ctx context.Context,
+	clients *infra.Forges,
+	arg string,
+) (infra.ForgeClient, string, string, error) {
+	host, owner, repo, err := infra.ParseRepo(arg)
+	if err != nil {
+		return nil, "", "", err
 	}
+	forge, err := clients.Forge(ctx, host)
+	if err != nil {
+		return nil, "", "", err
+	}
+	return forge, owner, repo, nil
+}
 
-	return mcp.NewToolResultText(b.String()), nil
+// forgeErrorResult reports a failed forge request together with the credentials
+// used, since most failures of anonymous requests are due to missing authentication.
+func forgeErrorResult(forge infra.ForgeClient, action string, err error) *mcp.CallToolResult {
+	if forge.Anonymous() {
+		hint := "set GITHUB_TOKEN or GH_TOKEN (GH_ENTERPRISE_TOKEN for GitHub Enterprise)," +
+			" or log in with gh auth login"
+		switch forge.Kind() {
+		case infra.ForgeGitLab:
+			hint = "set " + infra.ForgeAuthEnvGitLab + " or configure a git credential helper"
+		case infra.ForgeGitea:
+			hint = "set " + infra.ForgeAuthEnvGitea + " or configure a git credential helper"
+		}
+		return mcp.NewToolResultError(fmt.Sprintf(
+			"Error %s: %v (no credentials found for %s; %s)", action, err, forge.Host(), hint,
+		))
+	}
+	return mcp.NewToolResultError(fmt.Sprintf(
+		"Error %s: %v (credentials for %s from %s)", action, err, forge.Host(), forge.AuthSource(),
+	))
 }
 
 // paginateContent applies offset/limit to content by lines, similar to how read_godoc works
@@ -21,6 +21,9 @@ type ReadGoDocArgs struct {
 	PackageURL string `json:"package_url"`
 	Offset     int    `json:"offset,omitempty"`
 	Limit      int    `json:"limit,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Symbol     string `json:"symbol,omitempty"`
 }
 
 // SearchWithinGoDocArgs represents arguments for searching within Go documentation
@@ -28,6 +31,9 @@ type SearchWithinGoDocArgs struct {
 	PackageURL string `json:"package_url"`
 	Keyword    string `json:"keyword"`
 	MaxMatches int    `json:"max_matches,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Version    string `json:"version,omitempty"`
+	SearchModeArgs
 }
 
 func searchGoDoc(
@@ -40,7 +46,7 @@ func searchGoDoc(
 	}
 
 	httpcli := infra.NewHttpClient()
-	results, err := app.SearchGoDoc(httpcli, args.Query)
+	results, err := app.SearchGoDoc(ctx, httpcli, args.Query)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchGoDoc", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Error searching Go documentation: %v", err)), nil
@@ -55,95 +61,161 @@ func searchGoDoc(
 	), nil
 }
 
-func readGoDoc(
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	args ReadGoDocArgs,
-) (*mcp.CallToolResult, error) {
-	if args.PackageURL == "" {
-		return mcp.NewToolResultError("Missing package URL"), nil
-	}
-
-	// Set default limit if not specified
-	limit := args.Limit
-	if limit == 0 {
-		limit = app.DefaultLinesPerPage
-	}
-
-	httpcli := infra.NewHttpClient()
-	result, totalLines, hasMore, err := app.ReadGoDocPaged(
-		httpcli,
-		args.PackageURL,
-		args.Offset,
-		limit,
-	)
-	if err != nil {
-		slog.ErrorContext(ctx, "readGoDoc", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error reading Go documentation: %v", err)), nil
+// readGoDoc returns the read_godoc handler. Local documentation is
+// resolved through the go.mod in workdir.
+func readGoDoc(workdir string) mcp.TypedToolHandlerFunc[ReadGoDocArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args ReadGoDocArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.PackageURL == "" {
+			return mcp.NewToolResultError("Missing package URL"), nil
+		}
+		source, err := app.ParseDocSource(args.Source)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		pkg, version, err := app.ResolveGoDocVersion(workdir, args.PackageURL, args.Version)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error resolving version: %v", err)), nil
+		}
+
+		// Set default limit if not specified
+		limit := args.Limit
+		if limit == 0 {
+			limit = app.DefaultLinesPerPage
+		}
+
+		httpcli := infra.NewHttpClient()
+		result, totalLines, hasMore, err := app.ReadGoDocPaged(
+			ctx,
+			httpcli,
+			workdir,
+			pkg,
+			version,
+			args.Symbol,
+			source,
+			args.Offset,
+			limit,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "readGoDoc", "error", err)
+			return mcp.NewToolResultError(
+				fmt.Sprintf("Error reading Go documentation: %v", err),
+			), nil
+		}
+
+		// Calculate line range for display
+		startLine := args.Offset + 1
+		endLine := args.Offset + len(strings.Split(strings.TrimSpace(result), "\n"))
+		if strings.TrimSpace(result) == "" {
+			endLine = args.Offset
+		}
+
+		title := packageRef(pkg, version)
+		if args.Symbol != "" {
+			title = fmt.Sprintf("%s in %s", args.Symbol, title)
+		}
+		response := fmt.Sprintf("Documentation for '%s' (Lines %d-%d of %d):\n%s",
+			title, startLine, endLine, totalLines, result)
+
+		if hasMore {
+			nextOffset := args.Offset + limit
+			response += fmt.Sprintf("\n... (use offset=%d to see more)", nextOffset)
+		}
+
+		return mcp.NewToolResultText(response), nil
 	}
-
-	// Calculate line range for display
-	startLine := args.Offset + 1
-	endLine := args.Offset + len(strings.Split(strings.TrimSpace(result), "\n"))
-	if strings.TrimSpace(result) == "" {
-		endLine = args.Offset
-	}
-
-	response := fmt.Sprintf("Documentation for '%s' (Lines %d-%d of %d):\n%s",
-		args.PackageURL, startLine, endLine, totalLines, result)
-
-	if hasMore {
-		nextOffset := args.Offset + limit
-		response += fmt.Sprintf("\n... (use offset=%d to see more)", nextOffset)
-	}
-
-	return mcp.NewToolResultText(response), nil
 }
 
-func searchWithinGoDoc(
-	ctx context.Context,
-	request mcp.CallToolRequest,
-	args SearchWithinGoDocArgs,
-) (*mcp.CallToolResult, error) {
-	if args.PackageURL == "" {
-		return mcp.NewToolResultError("Missing package URL"), nil
-	}
-	if args.Keyword == "" {
-		return mcp.NewToolResultError("Missing search keyword"), nil
-	}
-
-	// Set default max matches if not specified
-	maxMatches := args.MaxMatches
-	if maxMatches == 0 {
-		maxMatches = 10 // Default to 10 matches like search_local_files
-	}
-
-	httpcli := infra.NewHttpClient()
-	result, err := app.SearchWithinGoDoc(httpcli, args.PackageURL, args.Keyword, maxMatches)
-	if err != nil {
-		slog.ErrorContext(ctx, "searchWithinGoDoc", "error", err)
-		return mcp.NewToolResultError(fmt.Sprintf("Error searching Go documentation: %v", err)), nil
-	}
-
-	if len(result.Matches) == 0 {
-		return mcp.NewToolResultText(
-			fmt.Sprintf("No matches found for '%s' in package '%s'", args.Keyword, args.PackageURL),
-		), nil
-	}
-
-	builder := strings.Builder{}
-	builder.WriteString(
-		fmt.Sprintf("Search results for '%s' in package '%s':\n\n", args.Keyword, args.PackageURL),
-	)
-
-	for _, match := range result.Matches {
-		builder.WriteString(fmt.Sprintf("- Line %d\n```\n%s\n```\n", match.LineNo, match.Text))
+// searchWithinGoDoc returns the search_within_godoc handler. Local documentation
+// is resolved through the go.mod in workdir.
+func searchWithinGoDoc(workdir string) mcp.TypedToolHandlerFunc[SearchWithinGoDocArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args SearchWithinGoDocArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.PackageURL == "" {
+			return mcp.NewToolResultError("Missing package URL"), nil
+		}
+		if args.Keyword == "" {
+			return mcp.NewToolResultError("Missing search keyword"), nil
+		}
+		source, err := app.ParseDocSource(args.Source)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		query, err := args.query(args.Keyword)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		pkg, version, err := app.ResolveGoDocVersion(workdir, args.PackageURL, args.Version)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("Error resolving version: %v", err)), nil
+		}
+
+		// Set default max matches if not specified
+		maxMatches := args.MaxMatches
+		if maxMatches == 0 {
+			maxMatches = 10 // Default to 10 matches like search_local_files
+		}
+
+		httpcli := infra.NewHttpClient()
+		result, err := app.SearchWithinGoDoc(
+			ctx,
+			httpcli,
+			workdir,
+			pkg,
+			version,
+			source,
+			query,
+			maxMatches, args.Before, args.After,
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "searchWithinGoDoc", "error", err)
+			return mcp.NewToolResultError(
+				fmt.Sprintf("Error searching Go documentation: %v", err),
+			), nil
+		}
+
+		if len(result.Matches) == 0 {
+			return mcp.NewToolResultText(
+				fmt.Sprintf(
+					"No matches found for %s in package '%s'",
+					args.describe(args.Keyword),
+					packageRef(pkg, version),
+				),
+			), nil
+		}
+
+		builder := strings.Builder{}
+		builder.WriteString(
+			fmt.Sprintf(
+				"Search results for %s in package '%s':\n\n",
+				args.describe(args.Keyword),
+				packageRef(pkg, version),
+			),
+		)
+
+		writeMatches(&builder, result.Matches, args.Before > 0 || args.After > 0)
+
+		// Add truncation indicator if matches were truncated
+		if result.Truncated {
+			builder.WriteString("... (additional matches truncated)\n")
+		}
+
+		return mcp.NewToolResultText(builder.String()), nil
 	}
+}
 
-	// Add truncation indicator if matches were truncated
-	if result.Truncated {
-		builder.WriteString("... (additional matches truncated)\n")
+// packageRef formats a package path with an optional version ("pkg@v1.2.3").
+func packageRef(pkg, version string) string {
+	if version == "" {
+		return pkg
 	}
-
-	return mcp.NewToolResultText(builder.String()), nil
+	return pkg + "@" + version
 }
@@ -0,0 +1,43 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// FindImplementationsArgs represents arguments for the find_implementations tool.
+type FindImplementationsArgs struct {
+	Directory           string `json:"directory"`
+	Target              string `json:"target"`
+	IncludeDependencies bool   `json:"include_dependencies,omitempty"`
+}
+
+func findImplementations(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	args FindImplementationsArgs,
+) (*mcp.CallToolResult, error) {
+	if args.Directory == "" {
+		return mcp.NewToolResultError("Missing directory path"), nil
+	}
+	if args.Target == "" {
+		return mcp.NewToolResultError("Missing target"), nil
+	}
+
+	result, err := app.FindImplementations(
+		ctx,
+		args.Directory,
+		args.Target,
+		args.IncludeDependencies,
+	)
+	if err != nil {
+		slog.ErrorContext(ctx, "findImplementations", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Error finding implementations: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(app.FormatImplementations(result, args.Directory)), nil
+}
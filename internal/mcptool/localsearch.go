@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
-	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -19,6 +18,9 @@ type SearchLocalFilesArgs struct {
 	Query      string `json:"query"`
 	Extension  string `json:"extension"`
 	MaxMatches int    `json:"max_matches,omitempty"`
+	SearchModeArgs
+	FileFilterArgs
+	IgnoreArgs
 }
 
 func searchLocalFiles(
@@ -32,10 +34,17 @@ func searchLocalFiles(
 	if args.Query == "" {
 		return mcp.NewToolResultError("Missing search query"), nil
 	}
+	query, err := args.query(args.Query)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
-	// Ensure the extension starts with a dot
-	if args.Extension != "" && !strings.HasPrefix(args.Extension, ".") {
-		args.Extension = "." + args.Extension
+	// Extensions are comma-separated, with or without dots
+	opts := args.walkOptions()
+	for ext := range strings.SplitSeq(args.Extension, ",") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			opts.Extensions = append(opts.Extensions, ext)
+		}
 	}
 
 	// Set default max matches if not specified
@@ -44,14 +53,14 @@ func searchLocalFiles(
 		maxMatches = defaultMaxMatchesPerFile
 	}
 
-	fw := infra.NewFileWalker()
+	fw := args.fileWalker()
 	localFiles, err := app.SearchLocalFiles(
 		ctx,
 		fw,
 		args.Path,
-		args.Extension,
-		args.Query,
-		maxMatches,
+		opts,
+		query,
+		maxMatches, args.Before, args.After,
 	)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchLocalFiles", "error", err)
@@ -60,15 +69,12 @@ func searchLocalFiles(
 
 	builder := strings.Builder{}
 	for _, file := range localFiles {
-		fileMatches := fmt.Sprintf("File: %s\n", file.Filename)
-		for _, match := range file.Matches {
-			fileMatches += fmt.Sprintf("- Line %d\n```\n%s\n```\n", match.LineNo, match.Text)
-		}
+		builder.WriteString(fmt.Sprintf("File: %s\n", file.Filename))
+		writeMatches(&builder, file.Matches, args.Before > 0 || args.After > 0)
 		// Add truncation indicator if file had matches truncated
 		if file.Truncated {
-			fileMatches += "... (additional matches truncated)\n"
+			builder.WriteString("... (additional matches truncated)\n")
 		}
-		builder.WriteString(fileMatches)
 	}
 
 	return mcp.NewToolResultText(builder.String()), nil
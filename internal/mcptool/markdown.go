@@ -6,13 +6,14 @@ import (
 	"log/slog"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
-	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // ScanMarkdownArgs represents arguments for markdown file scanning
 type ScanMarkdownArgs struct {
 	Path string `json:"path"`
+	FileFilterArgs
+	IgnoreArgs
 }
 
 func scanMarkdown(
@@ -24,8 +25,8 @@ func scanMarkdown(
 		return mcp.NewToolResultError("path is required"), nil
 	}
 
-	fw := infra.NewFileWalker()
-	results, err := app.ScanMarkdownFiles(ctx, fw, args.Path)
+	fw := args.fileWalker()
+	results, err := app.ScanMarkdownFiles(ctx, fw, args.Path, args.walkOptions())
 	if err != nil {
 		slog.ErrorContext(ctx, "scanMarkdown", "error", err)
 		return mcp.NewToolResultError(fmt.Sprintf("Error scanning markdown files: %v", err)), nil
@@ -16,6 +16,9 @@ type OutlineGoPackageArgs struct {
 	SkipDependencies bool   `json:"skip_dependencies,omitempty"`
 	SkipDeclarations bool   `json:"skip_declarations,omitempty"`
 	SkipCallGraph    bool   `json:"skip_call_graph,omitempty"`
+	Mode             string `json:"mode,omitempty"`
+	FileFilterArgs
+	IgnoreArgs
 }
 
 func outlineGoPackage(
@@ -27,11 +30,18 @@ func outlineGoPackage(
 		return mcp.NewToolResultError("Missing directory path"), nil
 	}
 
-	fw := infra.NewFileWalker()
+	mode, err := app.ParseOutlineMode(args.Mode)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
+
+	fw := args.fileWalker()
 	output, err := app.OutlineGoPackage(ctx, fw, args.Directory, app.OutlineGoPackageOptions{
 		SkipDependencies: args.SkipDependencies,
 		SkipDeclarations: args.SkipDeclarations,
 		SkipCallGraph:    args.SkipCallGraph,
+		Mode:             mode,
+		Files:            args.walkOptions(),
 	})
 	if err != nil {
 		slog.ErrorContext(ctx, "outlineGoPackage", "error", err)
@@ -42,3 +52,43 @@ func outlineGoPackage(
 
 	return mcp.NewToolResultText(output), nil
 }
+
+// OutlineRemoteGoPackageArgs represents arguments for the outline_remote_go_package tool.
+type OutlineRemoteGoPackageArgs struct {
+	Package          string `json:"package"`
+	SkipDependencies bool   `json:"skip_dependencies,omitempty"`
+	SkipDeclarations bool   `json:"skip_declarations,omitempty"`
+	SkipCallGraph    bool   `json:"skip_call_graph,omitempty"`
+}
+
+func outlineRemoteGoPackage(
+	clients *infra.Forges,
+	cache *infra.SourceCache,
+) mcp.TypedToolHandlerFunc[OutlineRemoteGoPackageArgs] {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+		args OutlineRemoteGoPackageArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.Package == "" {
+			return mcp.NewToolResultError("Missing package"), nil
+		}
+
+		output, err := app.OutlineRemoteGoPackage(
+			ctx, clients, infra.NewGoModuleProxy(), cache, args.Package,
+			app.OutlineGoPackageOptions{
+				SkipDependencies: args.SkipDependencies,
+				SkipDeclarations: args.SkipDeclarations,
+				SkipCallGraph:    args.SkipCallGraph,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(ctx, "outlineRemoteGoPackage", "error", err)
+			return mcp.NewToolResultError(
+				fmt.Sprintf("Error generating package outline: %v", err),
+			), nil
+		}
+
+		return mcp.NewToolResultText(output), nil
+	}
+}
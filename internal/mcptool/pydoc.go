@@ -19,12 +19,14 @@ type ReadPyDocArgs struct {
 	ModuleName string `json:"module_name"`
 	Offset     int    `json:"offset,omitempty"`
 	Limit      int    `json:"limit,omitempty"`
+	Symbol     string `json:"symbol,omitempty"`
 }
 
 type SearchWithinPyDocArgs struct {
 	ModuleName string `json:"module_name"`
 	Keyword    string `json:"keyword"`
 	MaxMatches int    `json:"max_matches,omitempty"`
+	SearchModeArgs
 }
 
 func searchPyDoc(
@@ -37,7 +39,7 @@ func searchPyDoc(
 	}
 
 	httpcli := infra.NewHttpClient()
-	results, err := app.SearchPyDoc(httpcli, args.Query)
+	results, err := app.SearchPyDoc(ctx, httpcli, args.Query)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchPyDoc", "error", err)
 		return mcp.NewToolResultError(
@@ -70,8 +72,10 @@ func readPyDoc(
 
 	httpcli := infra.NewHttpClient()
 	result, totalLines, hasMore, err := app.ReadPyDocPaged(
+		ctx,
 		httpcli,
 		args.ModuleName,
+		args.Symbol,
 		args.Offset,
 		limit,
 	)
@@ -88,8 +92,12 @@ func readPyDoc(
 		endLine = args.Offset
 	}
 
+	title := args.ModuleName
+	if args.Symbol != "" {
+		title = fmt.Sprintf("%s in %s", args.Symbol, args.ModuleName)
+	}
 	response := fmt.Sprintf("Documentation for '%s' (Lines %d-%d of %d):\n%s",
-		args.ModuleName, startLine, endLine, totalLines, result)
+		title, startLine, endLine, totalLines, result)
 
 	if hasMore {
 		nextOffset := args.Offset + limit
@@ -110,6 +118,10 @@ func searchWithinPyDoc(
 	if args.Keyword == "" {
 		return mcp.NewToolResultError("Missing search keyword"), nil
 	}
+	query, err := args.query(args.Keyword)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	maxMatches := args.MaxMatches
 	if maxMatches == 0 {
@@ -117,7 +129,15 @@ func searchWithinPyDoc(
 	}
 
 	httpcli := infra.NewHttpClient()
-	result, err := app.SearchWithinPyDoc(httpcli, args.ModuleName, args.Keyword, maxMatches)
+	result, err := app.SearchWithinPyDoc(
+		ctx,
+		httpcli,
+		args.ModuleName,
+		query,
+		maxMatches,
+		args.Before,
+		args.After,
+	)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchWithinPyDoc", "error", err)
 		return mcp.NewToolResultError(
@@ -127,18 +147,24 @@ func searchWithinPyDoc(
 
 	if len(result.Matches) == 0 {
 		return mcp.NewToolResultText(
-			fmt.Sprintf("No matches found for '%s' in module '%s'", args.Keyword, args.ModuleName),
+			fmt.Sprintf(
+				"No matches found for %s in module '%s'",
+				args.describe(args.Keyword),
+				args.ModuleName,
+			),
 		), nil
 	}
 
 	builder := strings.Builder{}
 	builder.WriteString(
-		fmt.Sprintf("Search results for '%s' in module '%s':\n\n", args.Keyword, args.ModuleName),
+		fmt.Sprintf(
+			"Search results for %s in module '%s':\n\n",
+			args.describe(args.Keyword),
+			args.ModuleName,
+		),
 	)
 
-	for _, match := range result.Matches {
-		builder.WriteString(fmt.Sprintf("- Line %d\n```\n%s\n```\n", match.LineNo, match.Text))
-	}
+	writeMatches(&builder, result.Matches, args.Before > 0 || args.After > 0)
 
 	if result.Truncated {
 		builder.WriteString("... (additional matches truncated)\n")
@@ -0,0 +1,45 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const defaultMaxReferences = 100
+
+// FindReferencesArgs represents arguments for the find_references tool.
+type FindReferencesArgs struct {
+	Directory     string `json:"directory"`
+	Target        string `json:"target"`
+	MaxReferences int    `json:"max_references,omitempty"`
+}
+
+func findReferences(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+	args FindReferencesArgs,
+) (*mcp.CallToolResult, error) {
+	if args.Directory == "" {
+		return mcp.NewToolResultError("Missing directory path"), nil
+	}
+	if args.Target == "" {
+		return mcp.NewToolResultError("Missing target"), nil
+	}
+
+	maxRefs := args.MaxReferences
+	if maxRefs == 0 {
+		maxRefs = defaultMaxReferences
+	}
+
+	result, err := app.FindReferences(ctx, args.Directory, args.Target)
+	if err != nil {
+		slog.ErrorContext(ctx, "findReferences", "error", err)
+		return mcp.NewToolResultError(fmt.Sprintf("Error finding references: %v", err)), nil
+	}
+
+	return mcp.NewToolResultText(app.FormatReferences(result, args.Directory, maxRefs)), nil
+}
@@ -4,11 +4,12 @@ import (
 	"fmt"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
 )
 
-func Register(s *server.MCPServer, workdir string) error {
+func Register(s *server.MCPServer, workdir string, forges *infra.Forges) error {
 	// Add Tree Directory tool
 	tool := mcp.NewTool(
 		"tree_dir",
@@ -33,6 +34,7 @@ func Register(s *server.MCPServer, workdir string) error {
 			mcp.Description("Maximum directory depth to traverse (default: 4 levels)"),
 		),
 	)
+	addIgnoreOption(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(treeDir))
 
 	// Add GoDoc search tool
@@ -53,14 +55,18 @@ func Register(s *server.MCPServer, workdir string) error {
 		"read_godoc",
 		mcp.WithDescription(
 			"Read Go documentation with line-based paging and caching."+
-				" Supports offset/limit for efficient exploration of large docs."+
-				" Cached for 30min to speed up subsequent requests.",
+				" Supports offset/limit for efficient exploration of large docs,"+
+				" or symbol to read a single declaration."+
+				" Works offline for the standard library and modules in the module cache;"+
+				" pages fetched from pkg.go.dev are cached and revalidated after the"+
+				" server's -cache-ttl (default: 30m).",
 		),
 		mcp.WithString(
 			"package_url",
 			mcp.Required(),
 			mcp.Description(
-				"Go package URL (e.g., 'golang.org/x/net/html', 'github.com/user/repo')",
+				"Go package URL, optionally with a version"+
+					" (e.g., 'golang.org/x/net/html', 'github.com/user/repo@v1.2.3')",
 			),
 		),
 		mcp.WithNumber("offset",
@@ -74,8 +80,39 @@ func Register(s *server.MCPServer, workdir string) error {
 				fmt.Sprintf("Number of lines to read (default: %d)", app.DefaultLinesPerPage),
 			),
 		),
+		mcp.WithString(
+			"source",
+			mcp.DefaultString(string(app.DocSourceAuto)),
+			mcp.Enum(
+				string(app.DocSourceAuto),
+				string(app.DocSourceLocal),
+				string(app.DocSourceRemote),
+			),
+			mcp.Description(
+				"Documentation source: 'local' renders from GOROOT or the module cache"+
+					" (by default at the version pinned in the server workdir's go.mod),"+
+					" 'remote' reads pkg.go.dev, 'auto' tries local first (default: auto)",
+			),
+		),
+		mcp.WithString(
+			"version",
+			mcp.Description(
+				"Package version (e.g., 'v1.2.3', 'go1.22.0'), or 'module' for the version"+
+					" pinned by the go.mod/go.sum in the server workdir."+
+					" Overrides a version given as 'package_url@version'."+
+					" Default: the version pinned by the workdir's go.mod when the package is"+
+					" found locally, otherwise latest",
+			),
+		),
+		mcp.WithString(
+			"symbol",
+			mcp.Description(
+				"Only read the documentation of this declaration"+
+					" (e.g., 'Client.Do', 'NewRequest'). Offset/limit apply within it",
+			),
+		),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(readGoDoc))
+	s.AddTool(tool, mcp.NewTypedToolHandler(readGoDoc(workdir)))
 
 	// Add GoDoc search within documentation tool
 	tool = mcp.NewTool(
@@ -88,57 +125,223 @@ func Register(s *server.MCPServer, workdir string) error {
 			"package_url",
 			mcp.Required(),
 			mcp.Description(
-				"Go package URL (e.g., 'golang.org/x/net/html', 'github.com/user/repo')",
+				"Go package URL, optionally with a version"+
+					" (e.g., 'golang.org/x/net/html', 'github.com/user/repo@v1.2.3')",
 			),
 		),
 		mcp.WithString(
 			"keyword",
 			mcp.Required(),
-			mcp.Description("Keyword to search for within the documentation"),
+			mcp.Description("Keyword to search for within the documentation (see mode)"),
 		),
 		mcp.WithNumber("max_matches",
 			mcp.DefaultNumber(10),
 			mcp.Description("Maximum number of matches to return (default: 10)"),
 		),
+		mcp.WithString(
+			"source",
+			mcp.DefaultString(string(app.DocSourceAuto)),
+			mcp.Enum(
+				string(app.DocSourceAuto),
+				string(app.DocSourceLocal),
+				string(app.DocSourceRemote),
+			),
+			mcp.Description(
+				"Documentation source: 'local' renders from GOROOT or the module cache"+
+					" (by default at the version pinned in the server workdir's go.mod),"+
+					" 'remote' reads pkg.go.dev, 'auto' tries local first (default: auto)",
+			),
+		),
+		mcp.WithString(
+			"version",
+			mcp.Description(
+				"Package version (e.g., 'v1.2.3', 'go1.22.0'), or 'module' for the version"+
+					" pinned by the go.mod/go.sum in the server workdir."+
+					" Overrides a version given as 'package_url@version'."+
+					" Default: the version pinned by the workdir's go.mod when the package is"+
+					" found locally, otherwise latest",
+			),
+		),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(searchWithinGoDoc))
+	addSearchModeOptions(&tool)
+	s.AddTool(tool, mcp.NewTypedToolHandler(searchWithinGoDoc(workdir)))
 
 	// Add GitHub search code tool
 	tool = mcp.NewTool(
 		"search_github_code",
 		mcp.WithDescription(
-			"Search code in GitHub repositories with compact formatting"+
+			"Search code in GitHub or GitLab repositories with compact formatting"+
 				" (30-40% token reduction)."+
-				" Returns repository/path format instead of verbose labels for efficient scanning.",
+				" Returns repository/path format instead of verbose labels for efficient scanning,"+
+				" matching fragments with line numbers, the total count and the remaining rate limit.",
 		),
 		mcp.WithString("query",
 			mcp.Required(),
 			mcp.Description("Code search query (e.g., 'function main', 'import context')"),
 		),
 		mcp.WithString("language",
-			mcp.Required(),
 			mcp.Description(
 				"Programming language to filter results"+
 					" (e.g., 'go', 'python', 'rust', 'typescript', 'c++', 'arduino', 'yaml', 'makefile')",
 			),
 		),
 		mcp.WithString("repo",
-			mcp.Description("GitHub repository in 'owner/repo' format to limit search scope"),
+			mcp.Description(
+				"Repository in 'owner/repo' or 'host/owner/repo' format"+
+					" (GitHub Enterprise, GitLab, e.g. 'gitlab.com/group/subgroup/repo')"+
+					" to limit search scope",
+			),
+		),
+		mcp.WithString("org",
+			mcp.Description("Organization (GitLab: group) to limit search scope to"),
+		),
+		mcp.WithString("user",
+			mcp.Description("User whose repositories to limit search scope to (GitHub only)"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Directory to limit search scope to (e.g., 'internal/app')"),
+		),
+		mcp.WithString("filename",
+			mcp.Description("File name to match (e.g., 'go.mod')"),
+		),
+		mcp.WithString("extension",
+			mcp.Description("File extension to match, without the dot (e.g., 'yaml')"),
+		),
+		mcp.WithString("host",
+			mcp.Description(
+				"GitHub Enterprise or GitLab host to search when repo is not given"+
+					" (default: github.com)",
+			),
+		),
+		mcp.WithNumber("page",
+			mcp.DefaultNumber(1),
+			mcp.Description("Page of results (1-based)"),
+		),
+		mcp.WithNumber("per_page",
+			mcp.DefaultNumber(app.DefaultSearchCodePerPage),
+			mcp.Description(
+				fmt.Sprintf(
+					"Results per page, at most 100 (default: %d)",
+					app.DefaultSearchCodePerPage,
+				),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(searchCodeGitHub(forges)))
+
+	// Add GitHub repository search tool
+	tool = mcp.NewTool(
+		"search_github_repos",
+		mcp.WithDescription(
+			"Search GitHub repositories, e.g. to choose between libraries."+
+				" Returns one compact line per repository with stars, language, last push and description.",
+		),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Repository search query (e.g., 'yaml parser', 'http router')"),
+		),
+		mcp.WithString("language",
+			mcp.Description("Primary language to filter results (e.g., 'go', 'rust', 'python')"),
+		),
+		mcp.WithNumber("min_stars",
+			mcp.Description("Minimum number of stars"),
+		),
+		mcp.WithString("sort",
+			mcp.Description("Sort order: 'stars', 'forks' or 'updated' (default: best match)"),
+			mcp.Enum(app.RepoSortOrders...),
+		),
+		mcp.WithString("host",
+			mcp.Description("GitHub Enterprise host to search (default: github.com)"),
+		),
+		mcp.WithNumber("page",
+			mcp.DefaultNumber(1),
+			mcp.Description(
+				fmt.Sprintf(
+					"Page of results (1-based, %d results per page)",
+					app.DefaultSearchReposPerPage,
+				),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(searchGitHubRepos(forges)))
+
+	// Add GitHub repository metadata tool
+	tool = mcp.NewTool(
+		"github_repo_info",
+		mcp.WithDescription(
+			"Describe a GitHub repository: description, default branch, stars, license, topics,"+
+				" latest release, last push time, archived flag and primary languages.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
 		),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(searchCodeGitHub))
+	s.AddTool(tool, mcp.NewTypedToolHandler(gitHubRepoInfo(forges)))
+
+	// Add GitHub releases tool
+	tool = mcp.NewTool(
+		"github_releases",
+		mcp.WithDescription(
+			"List the releases and tags of a GitHub repository, or read the release notes"+
+				" between two versions, e.g. when upgrading a dependency. If the repository has no"+
+				" releases or the releases in range have no notes, the matching section of its"+
+				" CHANGELOG.md is shown instead, with a note saying so.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithString("from",
+			mcp.Description(
+				"Version in use; release notes of later versions are returned (e.g., 'v1.2.0')",
+			),
+		),
+		mcp.WithString("to",
+			mcp.Description(
+				"Last version to include (default: the latest release if from is given)."+
+					" Omit both from and to to list releases and tags",
+			),
+		),
+		mcp.WithNumber("offset",
+			mcp.DefaultNumber(0),
+			mcp.Description("Line to start reading from (0-based)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(app.DefaultLinesPerPage),
+			mcp.Description(
+				fmt.Sprintf("Number of lines to read (default: %d)", app.DefaultLinesPerPage),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(gitHubReleases(forges)))
 
 	// Add GitHub get content tool
 	tool = mcp.NewTool("get_github_content",
-		mcp.WithDescription("Get content from GitHub with line-based paging for large files"),
+		mcp.WithDescription(
+			"Get content from GitHub, GitLab or Gitea with line-based paging for large files",
+		),
 		mcp.WithString("repo",
 			mcp.Required(),
-			mcp.Description("GitHub repository in 'owner/repo' format"),
+			mcp.Description(
+				"Repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise,"+
+					" GitLab, Gitea), e.g. 'gitlab.com/group/subgroup/repo' or 'codeberg.org/owner/repo'",
+			),
 		),
 		mcp.WithString("path",
 			mcp.Required(),
 			mcp.Description("Path to the file in the repository"),
 		),
+		mcp.WithString("ref",
+			mcp.Description(
+				"Branch, tag or commit SHA to read (default: the default branch);"+
+					" the resolved commit SHA is reported",
+			),
+		),
 		mcp.WithNumber("offset",
 			mcp.DefaultNumber(0),
 			mcp.Description("Line number to start reading from (0-based)"),
@@ -148,24 +351,33 @@ func Register(s *server.MCPServer, workdir string) error {
 			mcp.Description("Number of lines to read (default: 100, 0 for all lines)"),
 		),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(getGitHubContent))
+	s.AddTool(tool, mcp.NewTypedToolHandler(getGitHubContent(forges)))
 
 	// Add GitHub tree tool
 	tool = mcp.NewTool(
 		"tree_github_repo",
 		mcp.WithDescription(
-			"Display GitHub repository tree structure with depth limiting"+
+			"Display GitHub, GitLab or Gitea repository tree structure with depth limiting"+
 				" (default: 3 levels for network efficiency)."+
 				" Conservative token usage for remote repository exploration.",
 		),
 		mcp.WithString("repo",
 			mcp.Required(),
-			mcp.Description("GitHub repository in 'owner/repo' format"),
+			mcp.Description(
+				"Repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise,"+
+					" GitLab, Gitea), e.g. 'gitlab.com/group/subgroup/repo' or 'codeberg.org/owner/repo'",
+			),
 		),
 		mcp.WithString("path",
 			mcp.DefaultString(""),
 			mcp.Description("Path in the repository (defaults to root)"),
 		),
+		mcp.WithString("ref",
+			mcp.Description(
+				"Branch, tag or commit SHA to show (default: the default branch);"+
+					" the resolved commit SHA is reported",
+			),
+		),
 		mcp.WithBoolean(
 			"ignore_dot",
 			mcp.DefaultBool(false),
@@ -181,7 +393,208 @@ func Register(s *server.MCPServer, workdir string) error {
 			),
 		),
 	)
-	s.AddTool(tool, mcp.NewTypedToolHandler(getGitHubTree))
+	s.AddTool(tool, mcp.NewTypedToolHandler(getGitHubTree(forges)))
+
+	// Add GitHub issue search tool
+	tool = mcp.NewTool(
+		"search_github_issues",
+		mcp.WithDescription(
+			"Search GitHub issues and pull requests, one compact line per result"+
+				" (repository#number, state, title, author, comments, last update).",
+		),
+		mcp.WithString("query",
+			mcp.Required(),
+			mcp.Description("Search query in GitHub issue search syntax (e.g., 'panic label:bug')"),
+		),
+		mcp.WithString("repo",
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format"+
+					" (GitHub Enterprise) to limit search scope",
+			),
+		),
+		mcp.WithString("host",
+			mcp.Description(
+				"GitHub Enterprise host to search when repo is not given (default: github.com)",
+			),
+		),
+		mcp.WithString("state",
+			mcp.Enum("open", "closed"),
+			mcp.Description("Only return open or closed issues and pull requests"),
+		),
+		mcp.WithString("kind",
+			mcp.Enum("issue", "pr"),
+			mcp.Description("Only return issues ('issue') or pull requests ('pr')"),
+		),
+		mcp.WithNumber("page",
+			mcp.DefaultNumber(1),
+			mcp.Description("Page of results to return (30 per page)"),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(searchGitHubIssues(forges)))
+
+	// Add GitHub issue read tool
+	tool = mcp.NewTool(
+		"read_github_issue",
+		mcp.WithDescription(
+			"Read a GitHub issue (or the conversation of a pull request):"+
+				" title, state, labels and body, followed by a page of comments.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithNumber("number",
+			mcp.Required(),
+			mcp.Description("Issue number"),
+		),
+		mcp.WithNumber("comment_page",
+			mcp.DefaultNumber(1),
+			mcp.Description("Page of comments to read; the body is only shown on page 1"),
+		),
+		mcp.WithNumber("comments_per_page",
+			mcp.DefaultNumber(app.DefaultCommentsPerPage),
+			mcp.Description(
+				fmt.Sprintf(
+					"Number of comments per page (default: %d)",
+					app.DefaultCommentsPerPage,
+				),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(readGitHubIssue(forges)))
+
+	// Add GitHub pull request read tool
+	tool = mcp.NewTool(
+		"read_github_pull_request",
+		mcp.WithDescription(
+			"Read a GitHub pull request: description, changed files, review comments"+
+				" and the unified diff with line-based paging."+
+				" Later diff pages omit everything but the diff.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithNumber("number",
+			mcp.Required(),
+			mcp.Description("Pull request number"),
+		),
+		mcp.WithNumber("diff_offset",
+			mcp.DefaultNumber(0),
+			mcp.Description("Line of the diff to start reading from (0-based)"),
+		),
+		mcp.WithNumber("diff_limit",
+			mcp.DefaultNumber(app.DefaultLinesPerPage),
+			mcp.Description(
+				fmt.Sprintf("Number of diff lines to read (default: %d)", app.DefaultLinesPerPage),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(readGitHubPullRequest(forges)))
+
+	// Add GitHub commit history tool
+	tool = mcp.NewTool(
+		"github_log",
+		mcp.WithDescription(
+			"List the commit history of a GitHub repository, optionally of a single file"+
+				" or directory. Shows short SHA, date, author and subject of each commit.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithString("path",
+			mcp.Description("Only list commits touching this file or directory"),
+		),
+		mcp.WithString(
+			"ref",
+			mcp.Description(
+				"Branch, tag or commit SHA to start from (default: the default branch)",
+			),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(app.DefaultLogLimit),
+			mcp.Description(
+				fmt.Sprintf("Maximum number of commits to list (default: %d)", app.DefaultLogLimit),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(gitHubLog(forges)))
+
+	// Add GitHub blame tool
+	tool = mcp.NewTool(
+		"github_blame",
+		mcp.WithDescription(
+			"Show which commit last changed each line range of a file in a GitHub repository."+
+				" Requires a GitHub token.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithString("path",
+			mcp.Required(),
+			mcp.Description("Path of the file within the repository"),
+		),
+		mcp.WithString("ref",
+			mcp.Description(
+				"Branch, tag or commit SHA to blame (default: the default branch);"+
+					" the resolved commit SHA is reported",
+			),
+		),
+		mcp.WithNumber("start_line",
+			mcp.Description("Only show ranges ending at or after this line (1-based)"),
+		),
+		mcp.WithNumber("end_line",
+			mcp.Description("Only show ranges starting at or before this line (1-based)"),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(gitHubBlame(forges)))
+
+	// Add GitHub compare tool
+	tool = mcp.NewTool(
+		"github_compare",
+		mcp.WithDescription(
+			"Compare two refs of a GitHub repository: commits, changed files and the"+
+				" unified diff with line-based paging. Later diff pages omit everything but the diff.",
+		),
+		mcp.WithString("repo",
+			mcp.Required(),
+			mcp.Description(
+				"GitHub repository in 'owner/repo' or 'host/owner/repo' format (GitHub Enterprise)",
+			),
+		),
+		mcp.WithString("base",
+			mcp.Required(),
+			mcp.Description("Base branch, tag or commit SHA"),
+		),
+		mcp.WithString("head",
+			mcp.Required(),
+			mcp.Description("Head branch, tag or commit SHA"),
+		),
+		mcp.WithString("path",
+			mcp.Description("Only show changed files and diff under this file or directory"),
+		),
+		mcp.WithNumber("offset",
+			mcp.DefaultNumber(0),
+			mcp.Description("Line of the diff to start reading from (0-based)"),
+		),
+		mcp.WithNumber("limit",
+			mcp.DefaultNumber(app.DefaultLinesPerPage),
+			mcp.Description(
+				fmt.Sprintf("Number of diff lines to read (default: %d)", app.DefaultLinesPerPage),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(gitHubCompare(forges)))
 
 	// Add Local search tool
 	tool = mcp.NewTool(
@@ -197,13 +610,14 @@ func Register(s *server.MCPServer, workdir string) error {
 		),
 		mcp.WithString("query",
 			mcp.Required(),
-			mcp.Description("Text to search for in file contents (case-sensitive exact match)"),
+			mcp.Description(
+				"Text to search for in file contents (case-sensitive substring by default, see mode)",
+			),
 		),
 		mcp.WithString("extension",
-			mcp.Required(),
 			mcp.Description(
-				"File extension to search without dot"+
-					" (e.g., 'go', 'py', 'rs', 'ts', 'js', 'ino', 'c', 'h', 'txt', 'yaml')",
+				"File extensions to search without dot, comma-separated"+
+					" (e.g., 'go', 'ts,tsx', 'c,h', 'yaml'); all files if empty",
 			),
 		),
 		mcp.WithNumber("max_matches",
@@ -211,6 +625,9 @@ func Register(s *server.MCPServer, workdir string) error {
 			mcp.Description("Maximum number of matches to show per file (default: 10)"),
 		),
 	)
+	addSearchModeOptions(&tool)
+	addIgnoreOption(&tool)
+	addFileFilterOptions(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(searchLocalFiles))
 
 	// Add Go Package Outline tool
@@ -238,9 +655,150 @@ func Register(s *server.MCPServer, workdir string) error {
 			mcp.DefaultBool(false),
 			mcp.Description("Skip the call graph section (largest section)"),
 		),
+		mcp.WithString("mode",
+			mcp.DefaultString(string(app.OutlineModeHeuristic)),
+			mcp.Enum(string(app.OutlineModeHeuristic), string(app.OutlineModeTyped)),
+			mcp.Description(
+				"Call graph analysis mode: 'heuristic' parses files in isolation (fast),"+
+					" 'typed' loads the packages with full type information so callees are"+
+					" fully qualified and interface calls are marked as dynamic",
+			),
+		),
 	)
+	addIgnoreOption(&tool)
+	addFileFilterOptions(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(outlineGoPackage))
 
+	// Add remote Go Package Outline tool
+	tool = mcp.NewTool(
+		"outline_remote_go_package",
+		mcp.WithDescription(
+			"Get the same outline as outline_go_package for a package that is not on disk:"+
+				" a directory of a GitHub repository, or a package of a module version"+
+				" from the Go module proxy. Sources are read in memory, and the archives of"+
+				" recently outlined versions are cached. The call graph is built heuristically.",
+		),
+		mcp.WithString("package",
+			mcp.Required(),
+			mcp.Description(
+				"Package as '[host/]owner/repo[@ref][/path]' (GitHub, e.g. 'fpt/go-dev-mcp@main/internal/app';"+
+					" host is a configured GitHub Enterprise host, github.com if omitted)"+
+					" or 'module[@version][/path]' (e.g. 'golang.org/x/mod@v0.20.0/semver';"+
+					" latest version if omitted)",
+			),
+		),
+		mcp.WithBoolean("skip_dependencies",
+			mcp.DefaultBool(false),
+			mcp.Description("Skip the dependencies section"),
+		),
+		mcp.WithBoolean("skip_declarations",
+			mcp.DefaultBool(false),
+			mcp.Description("Skip the declarations section"),
+		),
+		mcp.WithBoolean("skip_call_graph",
+			mcp.DefaultBool(false),
+			mcp.Description("Skip the call graph section (largest section)"),
+		),
+	)
+	sourceCache := infra.NewSourceCache(infra.DefaultSourceCacheSize)
+	s.AddTool(tool, mcp.NewTypedToolHandler(outlineRemoteGoPackage(forges, sourceCache)))
+
+	// Add Find References tool
+	tool = mcp.NewTool(
+		"find_references",
+		mcp.WithDescription(
+			"Find every reference to a Go identifier by type-checking the module."+
+				" Unlike search_local_files, comments and strings are not matched."+
+				" Results are grouped by package with the enclosing function and one line of context.",
+		),
+		mcp.WithString("directory",
+			mcp.Required(),
+			mcp.Description("Directory inside the Go module to analyze (absolute path)"),
+		),
+		mcp.WithString(
+			"target",
+			mcp.Required(),
+			mcp.Description(
+				"Identifier to look up: a position 'file.go:line:column' or a qualified name"+
+					" (e.g., 'internal/repository.FileWalker.Walk', 'net/http.Client.Do')",
+			),
+		),
+		mcp.WithNumber("max_references",
+			mcp.DefaultNumber(defaultMaxReferences),
+			mcp.Description(
+				fmt.Sprintf(
+					"Maximum number of references to show (default: %d)",
+					defaultMaxReferences,
+				),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(findReferences))
+
+	// Add Describe Symbol tool
+	tool = mcp.NewTool(
+		"describe_symbol",
+		mcp.WithDescription(
+			"Go to the definition of a Go symbol in the local module, the module cache or GOROOT."+
+				" Returns the signature, doc comment and source with line numbers,"+
+				" plus the method set and embedded fields for types.",
+		),
+		mcp.WithString("directory",
+			mcp.Required(),
+			mcp.Description(
+				"Directory inside the Go module used to resolve packages and versions (absolute path)",
+			),
+		),
+		mcp.WithString(
+			"symbol",
+			mcp.Required(),
+			mcp.Description(
+				"Qualified symbol (e.g., 'net/http.Client.Do', 'internal/app.ExtractDeclarations')"+
+					" or a position 'file.go:line:column'",
+			),
+		),
+		mcp.WithNumber("max_source_lines",
+			mcp.DefaultNumber(app.DefaultMaxSourceLines),
+			mcp.Description(
+				fmt.Sprintf(
+					"Maximum number of source lines to return (default: %d)",
+					app.DefaultMaxSourceLines,
+				),
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(describeSymbol))
+
+	// Add Find Implementations tool
+	tool = mcp.NewTool(
+		"find_implementations",
+		mcp.WithDescription(
+			"Relate Go types and interfaces by type-checking the module."+
+				" Given an interface, lists every concrete type that satisfies it and whether"+
+				" it does so with a value or pointer receiver."+
+				" Given a concrete type, lists the interfaces it implements.",
+		),
+		mcp.WithString("directory",
+			mcp.Required(),
+			mcp.Description("Directory inside the Go module to analyze (absolute path)"),
+		),
+		mcp.WithString(
+			"target",
+			mcp.Required(),
+			mcp.Description(
+				"Interface or type to look up: a position 'file.go:line:column' or a qualified name"+
+					" (e.g., 'internal/repository.DirWalker', 'internal/infra.FileWalker')",
+			),
+		),
+		mcp.WithBoolean("include_dependencies",
+			mcp.DefaultBool(false),
+			mcp.Description(
+				"Also consider types declared in dependencies, including the standard library (default: false)",
+			),
+		),
+	)
+	s.AddTool(tool, mcp.NewTypedToolHandler(findImplementations))
+
 	// Add Scan Markdown tool
 	tool = mcp.NewTool(
 		"scan_markdown",
@@ -256,6 +814,8 @@ func Register(s *server.MCPServer, workdir string) error {
 			),
 		),
 	)
+	addIgnoreOption(&tool)
+	addFileFilterOptions(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(scanMarkdown))
 
 	// Add Go Code Validation tool
@@ -289,8 +849,10 @@ func Register(s *server.MCPServer, workdir string) error {
 		"read_rustdoc",
 		mcp.WithDescription(
 			"Read Rust crate documentation from docs.rs with line-based paging and caching."+
-				" Supports offset/limit for efficient exploration of large docs."+
-				" Cached for 30min to speed up subsequent requests.",
+				" Supports offset/limit for efficient exploration of large docs,"+
+				" or symbol to read a single item."+
+				" Fetched pages are cached and revalidated after the server's -cache-ttl"+
+				" (default: 30m).",
 		),
 		mcp.WithString(
 			"crate_url",
@@ -310,6 +872,14 @@ func Register(s *server.MCPServer, workdir string) error {
 				fmt.Sprintf("Number of lines to read (default: %d)", app.DefaultLinesPerPage),
 			),
 		),
+		mcp.WithString(
+			"symbol",
+			mcp.Description(
+				"Only read the documentation of this item or member"+
+					" (e.g., 'Deserialize', 'serde::de::Visitor', 'Deserializer::deserialize_any')."+
+					" Offset/limit apply within it",
+			),
+		),
 	)
 	s.AddTool(tool, mcp.NewTypedToolHandler(readRustDoc))
 
@@ -330,13 +900,14 @@ func Register(s *server.MCPServer, workdir string) error {
 		mcp.WithString(
 			"keyword",
 			mcp.Required(),
-			mcp.Description("Keyword to search for within the documentation"),
+			mcp.Description("Keyword to search for within the documentation (see mode)"),
 		),
 		mcp.WithNumber("max_matches",
 			mcp.DefaultNumber(10),
 			mcp.Description("Maximum number of matches to return (default: 10)"),
 		),
 	)
+	addSearchModeOptions(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(searchWithinRustDoc))
 
 	// Add Python documentation search tool
@@ -359,8 +930,10 @@ func Register(s *server.MCPServer, workdir string) error {
 		mcp.WithDescription(
 			"Read Python standard library module documentation from docs.python.org"+
 				" with line-based paging and caching."+
-				" Supports offset/limit for efficient exploration of large docs."+
-				" Cached for 30min to speed up subsequent requests.",
+				" Supports offset/limit for efficient exploration of large docs,"+
+				" or symbol to read a single function or class."+
+				" Fetched pages are cached and revalidated after the server's -cache-ttl"+
+				" (default: 30m).",
 		),
 		mcp.WithString(
 			"module_name",
@@ -380,6 +953,13 @@ func Register(s *server.MCPServer, workdir string) error {
 				fmt.Sprintf("Number of lines to read (default: %d)", app.DefaultLinesPerPage),
 			),
 		),
+		mcp.WithString(
+			"symbol",
+			mcp.Description(
+				"Only read the documentation of this object"+
+					" (e.g., 'json.dumps', 'dumps', 'JSONDecoder.decode'). Offset/limit apply within it",
+			),
+		),
 	)
 	s.AddTool(tool, mcp.NewTypedToolHandler(readPyDoc))
 
@@ -400,13 +980,14 @@ func Register(s *server.MCPServer, workdir string) error {
 		mcp.WithString(
 			"keyword",
 			mcp.Required(),
-			mcp.Description("Keyword to search for within the documentation"),
+			mcp.Description("Keyword to search for within the documentation (see mode)"),
 		),
 		mcp.WithNumber("max_matches",
 			mcp.DefaultNumber(10),
 			mcp.Description("Maximum number of matches to return (default: 10)"),
 		),
 	)
+	addSearchModeOptions(&tool)
 	s.AddTool(tool, mcp.NewTypedToolHandler(searchWithinPyDoc))
 
 	return nil
@@ -19,12 +19,14 @@ type ReadRustDocArgs struct {
 	CrateURL string `json:"crate_url"`
 	Offset   int    `json:"offset,omitempty"`
 	Limit    int    `json:"limit,omitempty"`
+	Symbol   string `json:"symbol,omitempty"`
 }
 
 type SearchWithinRustDocArgs struct {
 	CrateURL   string `json:"crate_url"`
 	Keyword    string `json:"keyword"`
 	MaxMatches int    `json:"max_matches,omitempty"`
+	SearchModeArgs
 }
 
 func searchRustDoc(
@@ -37,7 +39,7 @@ func searchRustDoc(
 	}
 
 	httpcli := infra.NewHttpClient()
-	results, err := app.SearchRustDoc(httpcli, args.Query)
+	results, err := app.SearchRustDoc(ctx, httpcli, args.Query)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchRustDoc", "error", err)
 		return mcp.NewToolResultError(
@@ -70,8 +72,10 @@ func readRustDoc(
 
 	httpcli := infra.NewHttpClient()
 	result, totalLines, hasMore, err := app.ReadRustDocPaged(
+		ctx,
 		httpcli,
 		args.CrateURL,
+		args.Symbol,
 		args.Offset,
 		limit,
 	)
@@ -88,8 +92,12 @@ func readRustDoc(
 		endLine = args.Offset
 	}
 
+	title := args.CrateURL
+	if args.Symbol != "" {
+		title = fmt.Sprintf("%s in %s", args.Symbol, args.CrateURL)
+	}
 	response := fmt.Sprintf("Documentation for '%s' (Lines %d-%d of %d):\n%s",
-		args.CrateURL, startLine, endLine, totalLines, result)
+		title, startLine, endLine, totalLines, result)
 
 	if hasMore {
 		nextOffset := args.Offset + limit
@@ -110,6 +118,10 @@ func searchWithinRustDoc(
 	if args.Keyword == "" {
 		return mcp.NewToolResultError("Missing search keyword"), nil
 	}
+	query, err := args.query(args.Keyword)
+	if err != nil {
+		return mcp.NewToolResultError(err.Error()), nil
+	}
 
 	maxMatches := args.MaxMatches
 	if maxMatches == 0 {
@@ -117,7 +129,15 @@ func searchWithinRustDoc(
 	}
 
 	httpcli := infra.NewHttpClient()
-	result, err := app.SearchWithinRustDoc(httpcli, args.CrateURL, args.Keyword, maxMatches)
+	result, err := app.SearchWithinRustDoc(
+		ctx,
+		httpcli,
+		args.CrateURL,
+		query,
+		maxMatches,
+		args.Before,
+		args.After,
+	)
 	if err != nil {
 		slog.ErrorContext(ctx, "searchWithinRustDoc", "error", err)
 		return mcp.NewToolResultError(
@@ -127,18 +147,24 @@ func searchWithinRustDoc(
 
 	if len(result.Matches) == 0 {
 		return mcp.NewToolResultText(
-			fmt.Sprintf("No matches found for '%s' in crate '%s'", args.Keyword, args.CrateURL),
+			fmt.Sprintf(
+				"No matches found for %s in crate '%s'",
+				args.describe(args.Keyword),
+				args.CrateURL,
+			),
 		), nil
 	}
 
 	builder := strings.Builder{}
 	builder.WriteString(
-		fmt.Sprintf("Search results for '%s' in crate '%s':\n\n", args.Keyword, args.CrateURL),
+		fmt.Sprintf(
+			"Search results for %s in crate '%s':\n\n",
+			args.describe(args.Keyword),
+			args.CrateURL,
+		),
 	)
 
-	for _, match := range result.Matches {
-		builder.WriteString(fmt.Sprintf("- Line %d\n```\n%s\n```\n", match.LineNo, match.Text))
-	}
+	writeMatches(&builder, result.Matches, args.Before > 0 || args.After > 0)
 
 	if result.Truncated {
 		builder.WriteString("... (additional matches truncated)\n")
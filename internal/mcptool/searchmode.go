@@ -0,0 +1,151 @@
+package tool
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fpt/go-dev-mcp/internal/contentsearch"
+	"github.com/fpt/go-dev-mcp/internal/model"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// SearchModeArgs represents the matching options shared by the tools searching text.
+type SearchModeArgs struct {
+	Mode       string   `json:"mode,omitempty"`
+	IgnoreCase bool     `json:"ignore_case,omitempty"`
+	Terms      []string `json:"terms,omitempty"`
+	Operator   string   `json:"operator,omitempty"`
+	Before     int      `json:"before,omitempty"`
+	After      int      `json:"after,omitempty"`
+}
+
+// query returns the query of the first term and the additional terms.
+func (a SearchModeArgs) query(first string) (contentsearch.Query, error) {
+	mode, err := contentsearch.ParseMode(a.Mode)
+	if err != nil {
+		return contentsearch.Query{}, err
+	}
+	operator, err := contentsearch.ParseOperator(a.Operator)
+	if err != nil {
+		return contentsearch.Query{}, err
+	}
+	return contentsearch.Query{
+		Terms:      append([]string{first}, a.Terms...),
+		Mode:       mode,
+		IgnoreCase: a.IgnoreCase,
+		Operator:   operator,
+	}, nil
+}
+
+// describe quotes the terms of the query for messages, e.g. "'a' or 'b'".
+func (a SearchModeArgs) describe(first string) string {
+	operator := " and "
+	if strings.EqualFold(a.Operator, string(contentsearch.OperatorOr)) {
+		operator = " or "
+	}
+	quoted := make([]string, 0, len(a.Terms)+1)
+	for _, term := range append([]string{first}, a.Terms...) {
+		quoted = append(quoted, "'"+term+"'")
+	}
+	return strings.Join(quoted, operator)
+}
+
+// addSearchModeOptions adds the parameters of SearchModeArgs to a tool.
+func addSearchModeOptions(tool *mcp.Tool) {
+	for _, opt := range []mcp.ToolOption{
+		mcp.WithString("mode",
+			mcp.DefaultString(string(contentsearch.ModeLiteral)),
+			mcp.Enum(
+				string(contentsearch.ModeLiteral),
+				string(contentsearch.ModeRegex),
+				string(contentsearch.ModeWord),
+			),
+			mcp.Description(
+				"How terms match lines: 'literal' substrings, 'regex' regular expressions"+
+					` in RE2 syntax (e.g., 'func .*Walk\(') or 'word' whole words (default: literal)`,
+			),
+		),
+		mcp.WithBoolean("ignore_case",
+			mcp.DefaultBool(false),
+			mcp.Description("Match case-insensitively (default: false)"),
+		),
+		mcp.WithArray("terms",
+			mcp.WithStringItems(),
+			mcp.Description("Additional terms, combined with the first by operator"),
+		),
+		mcp.WithNumber("before",
+			mcp.DefaultNumber(0),
+			mcp.Description("Number of context lines to show before each match (default: 0)"),
+		),
+		mcp.WithNumber("after",
+			mcp.DefaultNumber(0),
+			mcp.Description("Number of context lines to show after each match (default: 0)"),
+		),
+		mcp.WithString("operator",
+			mcp.DefaultString(string(contentsearch.OperatorAnd)),
+			mcp.Enum(string(contentsearch.OperatorAnd), string(contentsearch.OperatorOr)),
+			mcp.Description(
+				"Whether lines must match all terms ('and') or any term ('or') (default: and)",
+			),
+		),
+	} {
+		opt(tool)
+	}
+}
+
+// writeMatches writes matches with their column ranges. Without context lines,
+// each match is a block of its own. With context lines, adjacent matches share
+// a block whose lines are numbered like grep: "12:5: " for a match at column 5,
+// "11- " for context.
+func writeMatches(b *strings.Builder, matches []model.SearchMatch, context bool) {
+	if !context {
+		for _, match := range matches {
+			fmt.Fprintf(b, "- Line %d%s\n```\n%s\n```\n",
+				match.LineNo, columnRange(match), match.Text)
+		}
+		return
+	}
+
+	for i := 0; i < len(matches); {
+		// Group the matches whose context windows touch
+		j := i + 1
+		for j < len(matches) &&
+			matches[j].LineNo-len(matches[j].Before) ==
+				matches[j-1].LineNo+len(matches[j-1].After)+1 {
+			j++
+		}
+		group := matches[i:j]
+		first, last := group[0], group[len(group)-1]
+		fmt.Fprintf(b, "- Lines %d-%d\n```\n",
+			first.LineNo-len(first.Before), last.LineNo+len(last.After))
+		for _, match := range group {
+			lineNo := match.LineNo - len(match.Before)
+			for _, line := range match.Before {
+				fmt.Fprintf(b, "%d- %s\n", lineNo, line)
+				lineNo++
+			}
+			if match.Column > 0 {
+				fmt.Fprintf(b, "%d:%d: %s\n", match.LineNo, match.Column, match.Text)
+			} else {
+				fmt.Fprintf(b, "%d: %s\n", match.LineNo, match.Text)
+			}
+			for k, line := range match.After {
+				fmt.Fprintf(b, "%d- %s\n", match.LineNo+k+1, line)
+			}
+		}
+		b.WriteString("```\n")
+		i = j
+	}
+}
+
+// columnRange formats the column range of a match, e.g. " (col 5-12)".
+func columnRange(match model.SearchMatch) string {
+	switch {
+	case match.Column == 0:
+		return ""
+	case match.EndColumn == match.Column:
+		return fmt.Sprintf(" (col %d)", match.Column)
+	default:
+		return fmt.Sprintf(" (col %d-%d)", match.Column, match.EndColumn)
+	}
+}
@@ -7,7 +7,6 @@ import (
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
-	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -16,6 +15,7 @@ type TreeDirArgs struct {
 	RootDir   string `json:"root_dir"`
 	IgnoreDot bool   `json:"ignore_dot"`
 	MaxDepth  int    `json:"max_depth,omitempty"`
+	IgnoreArgs
 }
 
 func treeDir(
@@ -35,7 +35,7 @@ func treeDir(
 
 	b := strings.Builder{}
 	b.WriteString(fmt.Sprintf("%s\n", args.RootDir))
-	walker := infra.NewDirWalker()
+	walker := args.dirWalker()
 	err := app.PrintTree(ctx, &b, walker, args.RootDir, args.IgnoreDot, maxDepth)
 	if err != nil {
 		slog.ErrorContext(ctx, "treeDir", "error", err)
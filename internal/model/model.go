@@ -3,6 +3,14 @@ package model
 type SearchMatch struct {
 	LineNo int
 	Text   string
+
+	// Column and EndColumn are the 1-based byte columns of the first and last
+	// character of the leftmost term found in Text, or 0 if there are no terms.
+	Column    int
+	EndColumn int
+
+	Before []string // Context lines before Text not reported with the previous match
+	After  []string // Context lines after Text, up to the next match
 }
 
 type SearchResult struct {
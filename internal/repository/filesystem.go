@@ -19,6 +19,24 @@ type DirWalker interface {
 	) error
 }
 
+// WalkFileOptions selects the files a FileWalker visits.
+type WalkFileOptions struct {
+	// Extensions of the files to visit, e.g. ".go"; all files if empty
+	Extensions []string
+	// Include are doublestar globs of the paths relative to the walked directory
+	// to visit, e.g. "**/*.{go,mod}"; all files if empty. Globs prefixed with "!"
+	// exclude paths, e.g. "!**/*_test.go".
+	Include []string
+	// Exclude are doublestar globs of the files and directories to skip
+	Exclude []string
+	// MaxFileSize skips files larger than this many bytes if positive
+	MaxFileSize int64
+	// IgnoreDot skips dot files and directories
+	IgnoreDot bool
+}
+
 type FileWalker interface {
-	Walk(ctx context.Context, function WalkFileFunc, path, extension string, ignoreDot bool) error
+	Walk(ctx context.Context, function WalkFileFunc, path string, opts WalkFileOptions) error
+	// ReadFile returns the content of a file visited by Walk.
+	ReadFile(path string) ([]byte, error)
 }
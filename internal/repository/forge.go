@@ -0,0 +1,30 @@
+package repository
+
+import "context"
+
+// Forge is a code hosting service such as GitHub, GitLab or Gitea. Repositories
+// are named by owner and repo; on GitLab the owner can be a nested group
+// such as "group/subgroup".
+type Forge interface {
+	// Host returns the host the client talks to, e.g. "github.com".
+	Host() string
+
+	SearchCode(ctx context.Context, query string, opt *SearchCodeOption) (SearchCodeResult, error)
+	// GetContent returns the content of a file, or a listing of a directory,
+	// at ref (the default branch if empty).
+	GetContent(ctx context.Context, owner, repo, path, ref string) (string, error)
+	// ResolveRef returns the commit SHA of a branch, tag or (abbreviated) commit SHA,
+	// or of the default branch if ref is empty.
+	ResolveRef(ctx context.Context, owner, repo, ref string) (string, error)
+	// ListTree returns all files and directories of the tree of commit, and
+	// whether the list is incomplete because the tree is too large to list at once.
+	ListTree(ctx context.Context, owner, repo, commit string) ([]TreeEntry, bool, error)
+	// ListDir returns the files and directories in a directory of the tree of commit.
+	ListDir(ctx context.Context, owner, repo, commit, path string) ([]TreeEntry, error)
+}
+
+// TreeEntry is a file or directory of a repository.
+type TreeEntry struct {
+	Path  string // From the root of the repository, e.g. "cmd/main.go"
+	IsDir bool
+}
@@ -1,25 +1,229 @@
 package repository
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 type SearchCodeOption struct {
-	Language *string
-	Repo     *string
+	Language  *string
+	Repo      *string
+	Org       *string
+	User      *string
+	Path      *string // Directory qualifier, e.g. "internal/app"
+	Filename  *string
+	Extension *string
+	Page      int // 1-based
+	PerPage   int
 }
 
 type SearchCodeResult struct {
 	Total int
 	Items []SearchCodeItem
+	Rate  RateLimit // Of the search API, which is limited separately
 }
 
 type SearchCodeItem struct {
 	Name       string
 	Path       string
 	Repository string
-	Fragments  []string
+	Fragments  []CodeFragment
+}
+
+// CodeFragment is a matching part of a file. Line is the line number of its
+// first line in the current file, or 0 if the fragment could not be located.
+type CodeFragment struct {
+	Text string
+	Line int
 }
 
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// GitHubClient is a GitHub host, which has more features than other forges.
 type GitHubClient interface {
-	SearchCode(ctx context.Context, query string, opt *SearchCodeOption) (SearchCodeResult, error)
-	GetContent(ctx context.Context, owner, repo, path string) (string, error)
+	Forge
+
+	SearchIssues(
+		ctx context.Context, query string, opt *SearchIssuesOption,
+	) (SearchIssuesResult, error)
+	GetIssue(ctx context.Context, owner, repo string, number int) (Issue, error)
+	// ListIssueComments returns a page (1-based) of comments and whether there are more.
+	ListIssueComments(
+		ctx context.Context, owner, repo string, number, page, perPage int,
+	) ([]IssueComment, bool, error)
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (PullRequest, error)
+	ListPullRequestFiles(
+		ctx context.Context, owner, repo string, number int,
+	) ([]PullRequestFile, error)
+	ListReviewComments(
+		ctx context.Context, owner, repo string, number int,
+	) ([]ReviewComment, error)
+	GetPullRequestDiff(ctx context.Context, owner, repo string, number int) (string, error)
+
+	ListCommits(
+		ctx context.Context, owner, repo string, opt *ListCommitsOption,
+	) ([]CommitSummary, error)
+	Blame(ctx context.Context, owner, repo, ref, path string) (BlameResult, error)
+	Compare(ctx context.Context, owner, repo, base, head string) (Comparison, error)
+
+	SearchRepositories(
+		ctx context.Context, query string, opt *SearchReposOption,
+	) (SearchReposResult, error)
+	GetRepositoryInfo(ctx context.Context, owner, repo string) (RepositoryInfo, error)
+	// ListReleases returns up to limit published releases, newest first.
+	ListReleases(ctx context.Context, owner, repo string, limit int) ([]Release, error)
+	// ListTags returns up to limit tag names in the order of the GitHub API.
+	ListTags(ctx context.Context, owner, repo string, limit int) ([]string, error)
+}
+
+type SearchIssuesOption struct {
+	Repo  *string
+	State *string // "open" or "closed"
+	Kind  *string // "issue" or "pr"
+	Page  int     // 1-based, 0 for the first page
+}
+
+type SearchIssuesResult struct {
+	Total int
+	Items []IssueSummary
+}
+
+type IssueSummary struct {
+	Repository    string
+	Number        int
+	Title         string
+	State         string
+	IsPullRequest bool
+	Author        string
+	Comments      int
+	UpdatedAt     time.Time
+}
+
+type Issue struct {
+	IssueSummary
+	Body      string
+	Labels    []string
+	CreatedAt time.Time
+	URL       string
+}
+
+type IssueComment struct {
+	Author    string
+	CreatedAt time.Time
+	Body      string
+}
+
+type PullRequest struct {
+	Issue
+	Base         string
+	Head         string
+	Draft        bool
+	Merged       bool
+	Additions    int
+	Deletions    int
+	ChangedFiles int
+}
+
+type PullRequestFile struct {
+	Filename  string
+	Status    string // "added", "modified", "removed", "renamed", ...
+	Additions int
+	Deletions int
+}
+
+type ReviewComment struct {
+	Author    string
+	Path      string
+	Line      int
+	CreatedAt time.Time
+	Body      string
+}
+
+type ListCommitsOption struct {
+	Ref   string // Branch, tag or commit SHA, the default branch if empty
+	Path  string // Only commits touching this file or directory
+	Limit int
+}
+
+type CommitSummary struct {
+	SHA     string
+	Author  string // Login if known, the git author name otherwise
+	Date    time.Time
+	Message string // First line of the commit message
+}
+
+type BlameRange struct {
+	StartLine int
+	EndLine   int
+	Commit    CommitSummary
+}
+
+type BlameResult struct {
+	Commit string // The commit the file was blamed at
+	Ranges []BlameRange
+}
+
+type Comparison struct {
+	Status   string // "ahead", "behind", "diverged" or "identical"
+	AheadBy  int
+	BehindBy int
+	Commits  []CommitSummary
+	Files    []PullRequestFile
+	Diff     string // Unified diff
+}
+
+type SearchReposOption struct {
+	Language *string
+	MinStars int
+	Sort     string // "stars", "forks" or "updated"; empty for best match
+	Page     int    // 1-based, 0 for the first page
+	PerPage  int
+}
+
+type SearchReposResult struct {
+	Total int
+	Items []RepositorySummary
+}
+
+type RepositorySummary struct {
+	FullName    string
+	Description string
+	Language    string
+	Stars       int
+	Archived    bool
+	PushedAt    time.Time
+}
+
+type RepositoryInfo struct {
+	FullName      string
+	Description   string
+	Homepage      string
+	DefaultBranch string
+	Stars         int
+	Forks         int
+	OpenIssues    int
+	License       string // SPDX identifier, or the name if there is none
+	Topics        []string
+	Archived      bool
+	PushedAt      time.Time
+	LatestRelease *Release // nil if the repository has no releases
+	Languages     []LanguageBytes
+}
+
+type Release struct {
+	Tag         string
+	Name        string
+	PublishedAt time.Time
+	Prerelease  bool
+	Body        string // Release notes, only set by ListReleases
+}
+
+// LanguageBytes is the size of the code in a language, as detected by GitHub.
+type LanguageBytes struct {
+	Language string
+	Bytes    int
 }
@@ -0,0 +1,159 @@
+package subcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/google/subcommands"
+)
+
+type CacheCmd struct {
+	cdr *subcommands.Commander
+}
+
+func (c *CacheCmd) Name() string     { return "cache" }
+func (c *CacheCmd) Synopsis() string { return "Manage the documentation cache." }
+func (c *CacheCmd) Usage() string {
+	return `cache <stats|clear|warm> [flags]:
+  Manage the on-disk documentation cache.
+`
+}
+
+func (c *CacheCmd) SetFlags(f *flag.FlagSet) {
+	cdr := subcommands.NewCommander(f, "")
+
+	cdr.Register(cdr.CommandsCommand(), "help")
+	cdr.Register(cdr.FlagsCommand(), "help")
+	cdr.Register(cdr.HelpCommand(), "help")
+	cdr.Register(&CacheStatsCmd{}, "")
+	cdr.Register(&CacheClearCmd{}, "")
+	cdr.Register(&CacheWarmCmd{}, "")
+
+	c.cdr = cdr
+}
+
+func (c *CacheCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	args ...any,
+) subcommands.ExitStatus {
+	return c.cdr.Execute(ctx, args...)
+}
+
+// CacheStatsCmd shows a summary of the cache.
+type CacheStatsCmd struct {
+	dir string
+}
+
+func (c *CacheStatsCmd) Name() string     { return "stats" }
+func (c *CacheStatsCmd) Synopsis() string { return "Show documentation cache statistics." }
+func (c *CacheStatsCmd) Usage() string {
+	return `cache stats [flags]:
+  Show documentation cache statistics.
+`
+}
+
+func (c *CacheStatsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.dir, "cache-dir", infra.DefaultCacheDir(), "Documentation cache directory")
+}
+
+func (c *CacheStatsCmd) Execute(
+	_ context.Context,
+	_ *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	result, err := app.DocCacheStats(c.dir)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+// CacheClearCmd removes all cached documentation.
+type CacheClearCmd struct {
+	dir string
+}
+
+func (c *CacheClearCmd) Name() string     { return "clear" }
+func (c *CacheClearCmd) Synopsis() string { return "Remove all cached documentation." }
+func (c *CacheClearCmd) Usage() string {
+	return `cache clear [flags]:
+  Remove all cached documentation.
+`
+}
+
+func (c *CacheClearCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.dir, "cache-dir", infra.DefaultCacheDir(), "Documentation cache directory")
+}
+
+func (c *CacheClearCmd) Execute(
+	_ context.Context,
+	_ *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	removed, err := app.ClearDocCache(c.dir)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Printf("Removed %d cached documents from %s\n", removed, c.dir)
+
+	return subcommands.ExitSuccess
+}
+
+// CacheWarmCmd fetches documentation into the cache ahead of use.
+type CacheWarmCmd struct {
+	dir string
+	ttl time.Duration
+}
+
+func (c *CacheWarmCmd) Name() string     { return "warm" }
+func (c *CacheWarmCmd) Synopsis() string { return "Fetch documentation into the cache." }
+func (c *CacheWarmCmd) Usage() string {
+	return `cache warm [flags] <source:name>...:
+  Fetch documentation into the cache, e.g.
+  cache warm godoc:golang.org/x/net/html rustdoc:serde pydoc:json
+`
+}
+
+func (c *CacheWarmCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.dir, "cache-dir", infra.DefaultCacheDir(), "Documentation cache directory")
+	f.DurationVar(&c.ttl, "cache-ttl", app.DefaultDocCacheTTL,
+		"Time after which cached documentation is revalidated")
+}
+
+func (c *CacheWarmCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing documentation references.")
+		return subcommands.ExitUsageError
+	}
+
+	if err := app.ConfigureDocCache(app.DocCacheConfig{Dir: c.dir, TTL: c.ttl}); err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	status := subcommands.ExitSuccess
+	httpcli := infra.NewHttpClient()
+	for _, ref := range f.Args() {
+		if err := app.WarmDocCache(ctx, httpcli, ref); err != nil {
+			fmt.Printf("%s: Error: %v\n", ref, err)
+			status = subcommands.ExitFailure
+			continue
+		}
+		fmt.Printf("%s: cached\n", ref)
+	}
+
+	return status
+}
@@ -0,0 +1,55 @@
+package subcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/google/subcommands"
+)
+
+type DescribeCmd struct {
+	directory      string
+	maxSourceLines int
+}
+
+func (*DescribeCmd) Name() string     { return "describe" }
+func (*DescribeCmd) Synopsis() string { return "Describe a Go symbol and show its source." }
+func (*DescribeCmd) Usage() string {
+	return `describe [flags] <qualified name | file:line:column>:
+  Resolve a Go symbol in the local module, the module cache or GOROOT and
+  show its signature, doc comment, source and (for types) method set.
+  Example: describe net/http.Client.Do
+`
+}
+
+func (p *DescribeCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.directory, "dir", ".", "Directory inside the module to resolve from")
+	f.IntVar(
+		&p.maxSourceLines,
+		"max-lines",
+		app.DefaultMaxSourceLines,
+		"Maximum number of source lines to show (0 for all)",
+	)
+}
+
+func (p *DescribeCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing symbol.")
+		return subcommands.ExitUsageError
+	}
+
+	desc, err := app.DescribeSymbol(ctx, p.directory, f.Arg(0))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	fmt.Print(app.FormatSymbolDescription(desc, p.maxSourceLines))
+	return subcommands.ExitSuccess
+}
@@ -0,0 +1,35 @@
+package subcmd
+
+import (
+	"flag"
+
+	"github.com/fpt/go-dev-mcp/internal/repository"
+)
+
+// fileFilterFlags selects the files of the commands walking local directories.
+type fileFilterFlags struct {
+	opts repository.WalkFileOptions
+}
+
+func (ff *fileFilterFlags) register(f *flag.FlagSet) {
+	f.Func("include",
+		"Only visit files matching this doublestar glob, e.g. '**/*.{go,mod}';"+
+			" '!'-prefixed globs exclude files (repeatable)",
+		func(value string) error {
+			ff.opts.Include = append(ff.opts.Include, value)
+			return nil
+		})
+	f.Func("exclude",
+		"Skip files and directories matching this doublestar glob, e.g. '**/testdata' (repeatable)",
+		func(value string) error {
+			ff.opts.Exclude = append(ff.opts.Exclude, value)
+			return nil
+		})
+	f.Int64Var(&ff.opts.MaxFileSize, "max-file-size", 0,
+		"Skip files larger than this many bytes (default: no limit)")
+}
+
+// walkOptions returns the options of the walk selecting the files.
+func (ff *fileFilterFlags) walkOptions() repository.WalkFileOptions {
+	return ff.opts
+}
@@ -4,15 +4,20 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
 	"github.com/fpt/go-dev-mcp/internal/infra"
+	"github.com/fpt/go-dev-mcp/internal/repository"
 	"github.com/google/subcommands"
+	"github.com/pkg/errors"
 )
 
 type GithubCmd struct {
-	cdr *subcommands.Commander
+	cdr   *subcommands.Commander
+	flags gitHubFlags
 }
 
 func (*GithubCmd) Name() string     { return "github" }
@@ -24,14 +29,25 @@ func (*GithubCmd) Usage() string {
 }
 
 func (c *GithubCmd) SetFlags(f *flag.FlagSet) {
+	c.flags.register(f, "")
+
 	cdr := subcommands.NewCommander(f, "")
 
 	cdr.Register(cdr.CommandsCommand(), "help")
 	cdr.Register(cdr.FlagsCommand(), "help")
 	cdr.Register(cdr.HelpCommand(), "help")
-	cdr.Register(&SearchCodeCmd{}, "searchcode")
-	cdr.Register(&GetContentCmd{}, "getcontent")
-	cdr.Register(&TreeRepoCmd{}, "tree")
+	cdr.Register(&SearchCodeCmd{flags: &c.flags}, "searchcode")
+	cdr.Register(&SearchReposCmd{flags: &c.flags}, "searchrepos")
+	cdr.Register(&RepoInfoCmd{flags: &c.flags}, "info")
+	cdr.Register(&ReleasesCmd{flags: &c.flags}, "releases")
+	cdr.Register(&GetContentCmd{flags: &c.flags}, "getcontent")
+	cdr.Register(&TreeRepoCmd{flags: &c.flags}, "tree")
+	cdr.Register(&SearchIssuesCmd{flags: &c.flags}, "searchissues")
+	cdr.Register(&IssueCmd{flags: &c.flags}, "issue")
+	cdr.Register(&PullRequestCmd{flags: &c.flags}, "pr")
+	cdr.Register(&LogCmd{flags: &c.flags}, "log")
+	cdr.Register(&BlameCmd{flags: &c.flags}, "blame")
+	cdr.Register(&CompareCmd{flags: &c.flags}, "compare")
 
 	c.cdr = cdr
 }
@@ -45,8 +61,17 @@ func (c *GithubCmd) Execute(
 }
 
 type SearchCodeCmd struct {
-	language string
-	repo     string
+	flags     *gitHubFlags
+	language  string
+	repo      string
+	org       string
+	user      string
+	path      string
+	filename  string
+	extension string
+	host      string
+	page      int
+	perPage   int
 }
 
 func (*SearchCodeCmd) Name() string     { return "searchcode" }
@@ -59,11 +84,19 @@ func (*SearchCodeCmd) Usage() string {
 
 func (c *SearchCodeCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&c.language, "language", "", "Language to filter search results")
-	f.StringVar(&c.repo, "repo", "", "Repository to search in (owner/repo)")
+	f.StringVar(&c.repo, "repo", "", "Repository to search in (owner/repo or host/owner/repo)")
+	f.StringVar(&c.org, "org", "", "Organization to search in")
+	f.StringVar(&c.user, "user", "", "User whose repositories to search in")
+	f.StringVar(&c.path, "path", "", "Directory to search in")
+	f.StringVar(&c.filename, "filename", "", "File name to match")
+	f.StringVar(&c.extension, "extension", "", "File extension to match, without the dot")
+	f.StringVar(&c.host, "host", "", "GitHub Enterprise host to search if -repo is not given")
+	f.IntVar(&c.page, "page", 1, "Page of results (1-based)")
+	f.IntVar(&c.perPage, "per-page", app.DefaultSearchCodePerPage, "Results per page")
 }
 
 func (c *SearchCodeCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -79,13 +112,36 @@ func (c *SearchCodeCmd) Execute(
 	}
 	fmt.Println("Searching GitHub for:", query)
 
-	gh, err := infra.NewGitHubClient()
+	host, repo := c.host, ""
+	if c.repo != "" {
+		repoHost, owner, name, err := infra.ParseRepo(c.repo)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return subcommands.ExitUsageError
+		}
+		if repoHost != "" {
+			host = repoHost
+		}
+		repo = owner + "/" + name
+	}
+
+	forge, err := c.flags.forge(ctx, host)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
 	}
 
-	result, err := app.GitHubSearchCode(context.Background(), gh, query, &c.language, &c.repo)
+	result, err := app.ForgeSearchCode(ctx, forge, query, &repository.SearchCodeOption{
+		Language:  &c.language,
+		Repo:      &repo,
+		Org:       &c.org,
+		User:      &c.user,
+		Path:      &c.path,
+		Filename:  &c.filename,
+		Extension: &c.extension,
+		Page:      c.page,
+		PerPage:   c.perPage,
+	})
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -95,7 +151,171 @@ func (c *SearchCodeCmd) Execute(
 	return subcommands.ExitSuccess
 }
 
-type GetContentCmd struct{}
+type SearchReposCmd struct {
+	flags    *gitHubFlags
+	language string
+	minStars int
+	sort     string
+	host     string
+	page     int
+}
+
+func (*SearchReposCmd) Name() string     { return "searchrepos" }
+func (*SearchReposCmd) Synopsis() string { return "Search repositories on GitHub." }
+func (*SearchReposCmd) Usage() string {
+	return `searchrepos [flags] <query>:
+  Search GitHub repositories, one line per repository.
+`
+}
+
+func (c *SearchReposCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.language, "language", "", "Primary language of the repositories")
+	f.IntVar(&c.minStars, "min-stars", 0, "Minimum number of stars")
+	f.StringVar(&c.sort, "sort", "", "Sort order: stars, forks or updated (default: best match)")
+	f.StringVar(&c.host, "host", "", "GitHub Enterprise host to search")
+	f.IntVar(&c.page, "page", 1, "Page of results (1-based)")
+}
+
+func (c *SearchReposCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 || f.Arg(0) == "" {
+		fmt.Println("Error: Missing search query.")
+		return subcommands.ExitUsageError
+	}
+	if c.sort != "" && !slices.Contains(app.RepoSortOrders, c.sort) {
+		fmt.Println("Error: Invalid sort:", c.sort)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, c.host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubSearchRepos(ctx, gh, f.Arg(0), &repository.SearchReposOption{
+		Language: &c.language,
+		MinStars: c.minStars,
+		Sort:     c.sort,
+		Page:     c.page,
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+type RepoInfoCmd struct {
+	flags *gitHubFlags
+}
+
+func (*RepoInfoCmd) Name() string     { return "info" }
+func (*RepoInfoCmd) Synopsis() string { return "Show metadata of a GitHub repository." }
+func (*RepoInfoCmd) Usage() string {
+	return `info <owner/repo>:
+  Show description, stars, license, topics, latest release and languages of a repository.
+`
+}
+
+func (c *RepoInfoCmd) SetFlags(f *flag.FlagSet) {}
+
+func (c *RepoInfoCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing owner/repo argument.")
+		fmt.Println("Usage: info <owner/repo>")
+		return subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubRepoInfo(ctx, gh, owner, repo)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+type ReleasesCmd struct {
+	flags  *gitHubFlags
+	from   string
+	to     string
+	offset int
+	limit  int
+}
+
+func (*ReleasesCmd) Name() string     { return "releases" }
+func (*ReleasesCmd) Synopsis() string { return "List GitHub releases or read release notes." }
+func (*ReleasesCmd) Usage() string {
+	return `releases [flags] <owner/repo>:
+  List releases and tags, or with -from and -to read the release notes in between.
+`
+}
+
+func (c *ReleasesCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.from, "from", "", "Read release notes of the versions after this one")
+	f.StringVar(&c.to, "to", "", "Last version to read release notes of (default: latest)")
+	f.IntVar(&c.offset, "offset", 0, "Line to start reading from (0-based)")
+	f.IntVar(&c.limit, "limit", app.DefaultLinesPerPage, "Number of lines to read")
+}
+
+func (c *ReleasesCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing owner/repo argument.")
+		fmt.Println("Usage: releases <owner/repo>")
+		return subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubReleases(ctx, gh, owner, repo, c.from, c.to, c.offset, c.limit)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+type GetContentCmd struct {
+	flags *gitHubFlags
+	ref   string
+}
 
 func (*GetContentCmd) Name() string     { return "getcontent" }
 func (*GetContentCmd) Synopsis() string { return "Get content of a file from GitHub." }
@@ -105,10 +325,15 @@ func (*GetContentCmd) Usage() string {
 `
 }
 
-func (*GetContentCmd) SetFlags(f *flag.FlagSet) {
+func (c *GetContentCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.ref, "ref", "", "Branch, tag or commit SHA (default: the default branch)")
 }
 
-func (*GetContentCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+func (c *GetContentCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
 	if f.NArg() < 2 {
 		fmt.Println("Error: Missing arguments.")
 		fmt.Println("Usage: getcontent <owner/repo> <path>")
@@ -116,24 +341,29 @@ func (*GetContentCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subc
 	}
 
 	ownerRepo := f.Arg(0)
-	parts := strings.Split(ownerRepo, "/")
-	if len(parts) != 2 {
-		fmt.Println("Error: Invalid repo format, expected 'owner/repo'.")
+	host, owner, repo, err := infra.ParseRepo(ownerRepo)
+	if err != nil {
+		fmt.Println("Error:", err)
 		return subcommands.ExitUsageError
 	}
-	owner := parts[0]
-	repo := parts[1]
 	path := f.Arg(1)
 
-	fmt.Println("Getting content from GitHub for:", owner, repo, path)
+	fmt.Println("Getting content for:", owner, repo, path)
+
+	forge, err := c.flags.forge(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
 
-	gh, err := infra.NewGitHubClient()
+	commit, err := forge.ResolveRef(ctx, owner, repo, c.ref)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
 	}
+	fmt.Println("Commit:", commit)
 
-	content, err := gh.GetContent(context.Background(), owner, repo, path)
+	content, err := forge.GetContent(ctx, owner, repo, path, commit)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -144,6 +374,8 @@ func (*GetContentCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subc
 }
 
 type TreeRepoCmd struct {
+	flags     *gitHubFlags
+	ref       string
 	ignoreDot bool
 	maxDepth  int
 }
@@ -165,9 +397,14 @@ func (c *TreeRepoCmd) SetFlags(f *flag.FlagSet) {
 		"Ignore dot files and directories (except .git which is always ignored)",
 	)
 	f.IntVar(&c.maxDepth, "max-depth", 3, "Maximum depth for directory traversal")
+	f.StringVar(&c.ref, "ref", "", "Branch, tag or commit SHA (default: the default branch)")
 }
 
-func (c *TreeRepoCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+func (c *TreeRepoCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
 	if f.NArg() < 1 {
 		fmt.Println("Error: Missing owner/repo argument.")
 		fmt.Println("Usage: tree <owner/repo> [path]")
@@ -175,14 +412,11 @@ func (c *TreeRepoCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subc
 	}
 
 	ownerRepo := f.Arg(0)
-	parts := strings.Split(ownerRepo, "/")
-	if len(parts) != 2 {
-		fmt.Println("Error: Invalid repo format, expected 'owner/repo'.")
+	host, owner, repo, err := infra.ParseRepo(ownerRepo)
+	if err != nil {
+		fmt.Println("Error:", err)
 		return subcommands.ExitUsageError
 	}
-
-	owner := parts[0]
-	repo := parts[1]
 	path := ""
 
 	// Optional path argument
@@ -190,19 +424,27 @@ func (c *TreeRepoCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subc
 		path = f.Arg(1)
 	}
 
-	gh, err := infra.NewGitHubClient()
+	forge, err := c.flags.forge(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	commit, err := forge.ResolveRef(ctx, owner, repo, c.ref)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
 	}
+	fmt.Println("Commit:", commit)
 
 	// Create a string builder for the tree output
 	b := strings.Builder{}
-	b.WriteString(fmt.Sprintf("%s/%s:%s\n", owner, repo, path))
+	b.WriteString(fmt.Sprintf("%s:%s\n", ownerRepo, path))
 
 	// Generate the tree using our new function
-	ctx := context.Background()
-	if err := app.PrintGitHubTree(ctx, &b, gh, owner, repo, path, c.ignoreDot, c.maxDepth); err != nil {
+	if err := app.PrintForgeTree(
+		ctx, &b, forge, owner, repo, commit, path, c.ignoreDot, c.maxDepth,
+	); err != nil {
 		fmt.Printf("Error generating tree: %v\n", err)
 		return subcommands.ExitFailure
 	}
@@ -211,3 +453,498 @@ func (c *TreeRepoCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subc
 	fmt.Println(b.String())
 	return subcommands.ExitSuccess
 }
+
+type SearchIssuesCmd struct {
+	flags *gitHubFlags
+	repo  string
+	host  string
+	state string
+	kind  string
+	page  int
+}
+
+func (*SearchIssuesCmd) Name() string { return "searchissues" }
+func (*SearchIssuesCmd) Synopsis() string {
+	return "Search issues and pull requests on GitHub."
+}
+func (*SearchIssuesCmd) Usage() string {
+	return `searchissues [flags] <query>:
+  Search issues and pull requests on GitHub.
+`
+}
+
+func (c *SearchIssuesCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.repo, "repo", "", "Repository to search in (owner/repo or host/owner/repo)")
+	f.StringVar(&c.host, "host", "", "GitHub Enterprise host to search if -repo is not given")
+	f.StringVar(&c.state, "state", "", "Only open or closed issues")
+	f.StringVar(&c.kind, "kind", "", "Only issues (issue) or pull requests (pr)")
+	f.IntVar(&c.page, "page", 1, "Page of results")
+}
+
+func (c *SearchIssuesCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 1 || f.Arg(0) == "" {
+		fmt.Println("Error: Missing search query.")
+		return subcommands.ExitUsageError
+	}
+
+	host, repo := c.host, ""
+	if c.repo != "" {
+		repoHost, owner, name, err := infra.ParseRepo(c.repo)
+		if err != nil {
+			fmt.Println("Error:", err)
+			return subcommands.ExitUsageError
+		}
+		if repoHost != "" {
+			host = repoHost
+		}
+		repo = owner + "/" + name
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubSearchIssues(ctx, gh, f.Arg(0), &repository.SearchIssuesOption{
+		Repo:  &repo,
+		State: &c.state,
+		Kind:  &c.kind,
+		Page:  c.page,
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Println(result)
+
+	return subcommands.ExitSuccess
+}
+
+type IssueCmd struct {
+	flags           *gitHubFlags
+	commentPage     int
+	commentsPerPage int
+}
+
+func (*IssueCmd) Name() string     { return "issue" }
+func (*IssueCmd) Synopsis() string { return "Read a GitHub issue with its comments." }
+func (*IssueCmd) Usage() string {
+	return `issue [flags] <owner/repo> <number>:
+  Read a GitHub issue with a page of its comments.
+`
+}
+
+func (c *IssueCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&c.commentPage, "comment-page", 1, "Page of comments")
+	f.IntVar(&c.commentsPerPage, "comments-per-page", app.DefaultCommentsPerPage,
+		"Number of comments per page")
+}
+
+func (c *IssueCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	gh, owner, repo, number, status := parseIssueArgs(ctx, c.flags, f, "issue")
+	if gh == nil {
+		return status
+	}
+
+	result, err := app.GitHubReadIssue(
+		ctx, gh, owner, repo, number, c.commentPage, c.commentsPerPage)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Println(result)
+
+	return subcommands.ExitSuccess
+}
+
+type PullRequestCmd struct {
+	flags      *gitHubFlags
+	diffOffset int
+	diffLimit  int
+}
+
+func (*PullRequestCmd) Name() string     { return "pr" }
+func (*PullRequestCmd) Synopsis() string { return "Read a GitHub pull request with its diff." }
+func (*PullRequestCmd) Usage() string {
+	return `pr [flags] <owner/repo> <number>:
+  Read a GitHub pull request: description, changed files, review comments and diff.
+`
+}
+
+func (c *PullRequestCmd) SetFlags(f *flag.FlagSet) {
+	f.IntVar(&c.diffOffset, "diff-offset", 0, "Line of the diff to start reading from (0-based)")
+	f.IntVar(&c.diffLimit, "diff-limit", app.DefaultLinesPerPage, "Number of diff lines to read")
+}
+
+func (c *PullRequestCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	gh, owner, repo, number, status := parseIssueArgs(ctx, c.flags, f, "pr")
+	if gh == nil {
+		return status
+	}
+
+	result, err := app.GitHubReadPullRequest(
+		ctx,
+		gh,
+		owner,
+		repo,
+		number,
+		c.diffOffset,
+		c.diffLimit,
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Println(result)
+
+	return subcommands.ExitSuccess
+}
+
+type LogCmd struct {
+	flags *gitHubFlags
+	ref   string
+	limit int
+}
+
+func (*LogCmd) Name() string     { return "log" }
+func (*LogCmd) Synopsis() string { return "Show the commit history of a GitHub repository." }
+func (*LogCmd) Usage() string {
+	return `log [flags] <owner/repo> [path]:
+  List commits of a GitHub repository, optionally only those touching path.
+`
+}
+
+func (c *LogCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.ref, "ref", "", "Branch, tag or commit SHA (default: the default branch)")
+	f.IntVar(&c.limit, "limit", app.DefaultLogLimit, "Maximum number of commits to list")
+}
+
+func (c *LogCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing owner/repo argument.")
+		fmt.Println("Usage: log <owner/repo> [path]")
+		return subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubLog(ctx, gh, owner, repo, &repository.ListCommitsOption{
+		Ref:   c.ref,
+		Path:  f.Arg(1),
+		Limit: c.limit,
+	})
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+type BlameCmd struct {
+	flags     *gitHubFlags
+	ref       string
+	startLine int
+	endLine   int
+}
+
+func (*BlameCmd) Name() string     { return "blame" }
+func (*BlameCmd) Synopsis() string { return "Show which commits last changed a GitHub file." }
+func (*BlameCmd) Usage() string {
+	return `blame [flags] <owner/repo> <path>:
+  Show the commit that last changed each line range of a file. Requires a token.
+`
+}
+
+func (c *BlameCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.ref, "ref", "", "Branch, tag or commit SHA (default: the default branch)")
+	f.IntVar(&c.startLine, "start-line", 0, "Only show ranges ending at or after this line")
+	f.IntVar(&c.endLine, "end-line", 0, "Only show ranges starting at or before this line")
+}
+
+func (c *BlameCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 2 {
+		fmt.Println("Error: Missing arguments.")
+		fmt.Println("Usage: blame <owner/repo> <path>")
+		return subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	result, err := app.GitHubBlame(
+		ctx, gh, owner, repo, c.ref, f.Arg(1), c.startLine, c.endLine,
+	)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Print(result)
+
+	return subcommands.ExitSuccess
+}
+
+type CompareCmd struct {
+	flags *gitHubFlags
+	path  string
+}
+
+func (*CompareCmd) Name() string     { return "compare" }
+func (*CompareCmd) Synopsis() string { return "Compare two refs of a GitHub repository." }
+func (*CompareCmd) Usage() string {
+	return `compare [flags] <owner/repo> <base> <head>:
+  Show the commits, changed files and diff between two branches, tags or commits.
+`
+}
+
+func (c *CompareCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&c.path, "path", "", "Only show changes under this file or directory")
+}
+
+func (c *CompareCmd) Execute(
+	ctx context.Context,
+	f *flag.FlagSet,
+	_ ...any,
+) subcommands.ExitStatus {
+	if f.NArg() < 3 {
+		fmt.Println("Error: Missing arguments.")
+		fmt.Println("Usage: compare <owner/repo> <base> <head>")
+		return subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	gh, err := c.flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
+	summary, diff, err := app.GitHubCompare(ctx, gh, owner, repo, f.Arg(1), f.Arg(2), c.path)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+	fmt.Println(summary)
+	fmt.Println(diff)
+
+	return subcommands.ExitSuccess
+}
+
+// parseIssueArgs parses the <owner/repo> <number> arguments of the issue and pr
+// commands. It returns a nil client and the exit status on errors.
+func parseIssueArgs(
+	ctx context.Context,
+	flags *gitHubFlags,
+	f *flag.FlagSet,
+	name string,
+) (*infra.GitHubClient, string, string, int, subcommands.ExitStatus) {
+	if f.NArg() < 2 {
+		fmt.Println("Error: Missing arguments.")
+		fmt.Printf("Usage: %s <owner/repo> <number>\n", name)
+		return nil, "", "", 0, subcommands.ExitUsageError
+	}
+	host, owner, repo, err := infra.ParseRepo(f.Arg(0))
+	if err != nil {
+		fmt.Println("Error:", err)
+		return nil, "", "", 0, subcommands.ExitUsageError
+	}
+	number, err := strconv.Atoi(strings.TrimPrefix(f.Arg(1), "#"))
+	if err != nil || number <= 0 {
+		fmt.Println("Error: Invalid number:", f.Arg(1))
+		return nil, "", "", 0, subcommands.ExitUsageError
+	}
+
+	gh, err := flags.client(ctx, host)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return nil, "", "", 0, subcommands.ExitFailure
+	}
+	return gh, owner, repo, number, subcommands.ExitSuccess
+}
+
+// gitHubFlags configures the GitHub, GitLab and Gitea hosts and tokens of the serve
+// and github commands.
+type gitHubFlags struct {
+	hosts  []infra.GitHubHost
+	tokens map[string]string // By host, "" for github.com
+
+	forgeHosts  []infra.ForgeHost
+	forgeTokens map[string]string // Of GitLab and Gitea hosts, by host
+}
+
+func (g *gitHubFlags) register(f *flag.FlagSet, prefix string) {
+	f.Func(prefix+"token",
+		"GitHub token as 'token' for github.com or 'host=token' (repeatable; default:"+
+			" GITHUB_TOKEN/GH_TOKEN, GH_ENTERPRISE_TOKEN, gh auth token or git credential helper)",
+		func(value string) error {
+			host, token, ok := strings.Cut(value, "=")
+			if !ok {
+				host, token = "", value
+			}
+			if g.tokens == nil {
+				g.tokens = make(map[string]string)
+			}
+			g.tokens[host] = token
+			return nil
+		})
+	f.Func(prefix+"host",
+		"GitHub Enterprise Server host as 'host' or 'host=apiURL[,uploadURL]' (repeatable)",
+		func(value string) error {
+			host, urls, _ := strings.Cut(value, "=")
+			if host == "" || strings.Contains(host, "/") {
+				return errors.Errorf("invalid GitHub host %q", value)
+			}
+			apiURL, uploadURL, _ := strings.Cut(urls, ",")
+			g.hosts = append(g.hosts, infra.GitHubHost{
+				Host:      host,
+				APIURL:    apiURL,
+				UploadURL: uploadURL,
+			})
+			return nil
+		})
+
+	for _, forge := range []struct{ kind, name, defaultHost, env string }{
+		{infra.ForgeGitLab, "GitLab", "gitlab.com", infra.ForgeAuthEnvGitLab},
+		{infra.ForgeGitea, "Gitea", "codeberg.org", infra.ForgeAuthEnvGitea},
+	} {
+		f.Func(forge.kind+"-token",
+			fmt.Sprintf("%s token as 'token' for %s or 'host=token' (repeatable; default:"+
+				" %s or git credential helper)", forge.name, forge.defaultHost, forge.env),
+			func(value string) error {
+				host, token, ok := strings.Cut(value, "=")
+				if !ok {
+					host, token = forge.defaultHost, value
+				}
+				if g.forgeTokens == nil {
+					g.forgeTokens = make(map[string]string)
+				}
+				g.forgeTokens[host] = token
+				return nil
+			})
+		f.Func(forge.kind+"-host",
+			fmt.Sprintf("%s host as 'host' or 'host=apiURL' (repeatable)", forge.name),
+			func(value string) error {
+				host, apiURL, _ := strings.Cut(value, "=")
+				if host == "" || strings.Contains(host, "/") {
+					return errors.Errorf("invalid %s host %q", forge.name, value)
+				}
+				g.forgeHosts = append(g.forgeHosts, infra.ForgeHost{
+					Kind:   forge.kind,
+					Host:   host,
+					APIURL: apiURL,
+				})
+				return nil
+			})
+	}
+}
+
+func (g *gitHubFlags) config() (infra.ForgeConfig, error) {
+	cfg := infra.ForgeConfig{GitHub: infra.GitHubConfig{Token: g.tokens[""]}}
+	if token, ok := g.tokens[infra.DefaultGitHubHost]; ok {
+		cfg.GitHub.Token = token
+	}
+	configured := map[string]bool{"": true, infra.DefaultGitHubHost: true}
+	for _, host := range g.hosts {
+		host.Token = g.tokens[host.Host]
+		cfg.GitHub.Hosts = append(cfg.GitHub.Hosts, host)
+		configured[host.Host] = true
+	}
+	for host := range g.tokens {
+		if !configured[host] {
+			return cfg, errors.Errorf(
+				"token given for GitHub host %s, which is not configured",
+				host,
+			)
+		}
+	}
+
+	forgeConfigured := map[string]bool{}
+	for _, host := range g.forgeHosts {
+		host.Token = g.forgeTokens[host.Host]
+		cfg.Hosts = append(cfg.Hosts, host)
+		forgeConfigured[host.Host] = true
+	}
+	for _, host := range infra.DefaultForgeHosts() {
+		if token, ok := g.forgeTokens[host.Host]; ok && !forgeConfigured[host.Host] {
+			host.Token = token
+			cfg.Hosts = append(cfg.Hosts, host)
+		}
+		forgeConfigured[host.Host] = true
+	}
+	for host := range g.forgeTokens {
+		if !forgeConfigured[host] {
+			return cfg, errors.Errorf("token given for host %s, which is not configured", host)
+		}
+	}
+	return cfg, nil
+}
+
+func (g *gitHubFlags) forges(ctx context.Context) (*infra.Forges, error) {
+	cfg, err := g.config()
+	if err != nil {
+		return nil, err
+	}
+	return infra.NewForges(ctx, cfg)
+}
+
+func (g *gitHubFlags) client(ctx context.Context, host string) (*infra.GitHubClient, error) {
+	forges, err := g.forges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gh, err := forges.Client(host)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Using %s credentials from %s\n", gh.Host(), gh.AuthSource())
+	return gh, nil
+}
+
+// forge is like client, but host may also be a GitLab or Gitea host.
+func (g *gitHubFlags) forge(ctx context.Context, host string) (infra.ForgeClient, error) {
+	forges, err := g.forges(ctx)
+	if err != nil {
+		return nil, err
+	}
+	forge, err := forges.Forge(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("Using %s credentials from %s\n", forge.Host(), forge.AuthSource())
+	return forge, nil
+}
@@ -58,7 +58,7 @@ func (c *GoDocSearchCmd) SetFlags(f *flag.FlagSet) {
 }
 
 func (c *GoDocSearchCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -74,7 +74,7 @@ func (c *GoDocSearchCmd) Execute(
 	}
 
 	httpcli := infra.NewHttpClient()
-	result, err := app.SearchGoDoc(httpcli, query)
+	result, err := app.SearchGoDoc(ctx, httpcli, query)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -86,14 +86,18 @@ func (c *GoDocSearchCmd) Execute(
 
 // GoDocReadCmd is a subcommand for reading Go documentation.
 type GoDocReadCmd struct {
-	offset int
-	limit  int
+	offset  int
+	limit   int
+	source  string
+	version string
+	symbol  string
+	workdir string
 }
 
 func (c *GoDocReadCmd) Name() string     { return "read" }
 func (c *GoDocReadCmd) Synopsis() string { return "Read Go documentation." }
 func (c *GoDocReadCmd) Usage() string {
-	return `godoc [flags]:
+	return `godoc read [flags] <package[@version]>:
   Read Go documentation.
 `
 }
@@ -101,10 +105,25 @@ func (c *GoDocReadCmd) Usage() string {
 func (c *GoDocReadCmd) SetFlags(f *flag.FlagSet) {
 	f.IntVar(&c.offset, "offset", 0, "Line offset to start reading from")
 	f.IntVar(&c.limit, "limit", app.DefaultLinesPerPage, "Number of lines to read")
+	f.StringVar(
+		&c.source,
+		"source",
+		string(app.DocSourceAuto),
+		"Documentation source: local, remote or auto",
+	)
+	f.StringVar(
+		&c.version,
+		"version",
+		"",
+		"Package version, or 'module' for the version pinned by go.mod"+
+			" (default: the pinned version when the package is found locally, otherwise latest)",
+	)
+	f.StringVar(&c.symbol, "symbol", "", "Only read this declaration (e.g. Client.Do)")
+	f.StringVar(&c.workdir, "workdir", ".", "Directory whose go.mod selects local package versions")
 }
 
 func (p *GoDocReadCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -113,14 +132,43 @@ func (p *GoDocReadCmd) Execute(
 		return subcommands.ExitUsageError
 	}
 
-	packageURL := f.Arg(0)
+	source, err := app.ParseDocSource(p.source)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitUsageError
+	}
+
+	pkg, version, err := app.ResolveGoDocVersion(p.workdir, f.Arg(0), p.version)
+	if err != nil {
+		fmt.Println("Error:", err)
+		return subcommands.ExitFailure
+	}
+
 	httpcli := infra.NewHttpClient()
-	result, totalLines, hasMore, err := app.ReadGoDocPaged(httpcli, packageURL, p.offset, p.limit)
+	result, totalLines, hasMore, err := app.ReadGoDocPaged(
+		ctx,
+		httpcli,
+		p.workdir,
+		pkg,
+		version,
+		p.symbol,
+		source,
+		p.offset,
+		p.limit,
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
 	}
 
+	ref := pkg
+	if version != "" {
+		ref = pkg + "@" + version
+	}
+	if p.symbol != "" {
+		ref = fmt.Sprintf("%s in %s", p.symbol, ref)
+	}
+
 	// Calculate line range for display
 	startLine := p.offset + 1
 	endLine := p.offset + len(strings.Split(strings.TrimSpace(result), "\n"))
@@ -130,7 +178,7 @@ func (p *GoDocReadCmd) Execute(
 
 	fmt.Printf(
 		"Documentation for '%s' (Lines %d-%d of %d):\n",
-		packageURL,
+		ref,
 		startLine,
 		endLine,
 		totalLines,
@@ -0,0 +1,49 @@
+package subcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/google/subcommands"
+)
+
+type ImplsCmd struct {
+	directory   string
+	includeDeps bool
+}
+
+func (*ImplsCmd) Name() string { return "impls" }
+func (*ImplsCmd) Synopsis() string {
+	return "Find implementations of an interface, or interfaces of a type."
+}
+func (*ImplsCmd) Usage() string {
+	return `impls [flags] <file:line:column | qualified name>:
+  Given an interface, list the concrete types that satisfy it and whether
+  they do so with a value or pointer receiver. Given a concrete type, list
+  the interfaces it implements.
+  Example: impls internal/repository.DirWalker
+`
+}
+
+func (p *ImplsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.directory, "dir", ".", "Directory inside the module to analyze")
+	f.BoolVar(&p.includeDeps, "deps", false, "Also consider types declared in dependencies")
+}
+
+func (p *ImplsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing target.")
+		return subcommands.ExitUsageError
+	}
+
+	result, err := app.FindImplementations(ctx, p.directory, f.Arg(0), p.includeDeps)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	fmt.Print(app.FormatImplementations(result, p.directory))
+	return subcommands.ExitSuccess
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/fpt/go-dev-mcp/internal/contentsearch"
 	"github.com/fpt/go-dev-mcp/internal/infra"
 	"github.com/google/subcommands"
 )
@@ -16,24 +17,42 @@ const defaultMaxMatchesPerFile = 10
 type LocalSearchCmd struct {
 	extension  string
 	maxMatches int
+	mode       string
+	ignoreCase bool
+	or         bool
+	before     int
+	after      int
+	context    int
+	noIgnore   bool
+	files      fileFilterFlags
 }
 
 func (*LocalSearchCmd) Name() string     { return "localsearch" }
 func (*LocalSearchCmd) Synopsis() string { return "Search for files locally." }
 func (*LocalSearchCmd) Usage() string {
-	return `localsearch [flags]:
-  Search for files locally.
+	return `localsearch [flags] <path> <term> [term...]:
+  Search for files locally. Lines must match all terms, or any with -or.
 `
 }
 
 func (p *LocalSearchCmd) SetFlags(f *flag.FlagSet) {
-	f.StringVar(&p.extension, "extension", "", "File extension to search for")
+	f.StringVar(&p.extension, "extension", "", "Comma-separated file extensions to search for")
 	f.IntVar(
 		&p.maxMatches,
 		"max-matches",
 		defaultMaxMatchesPerFile,
 		"Maximum number of matches per file",
 	)
+	f.StringVar(&p.mode, "mode", string(contentsearch.ModeLiteral),
+		"How terms match lines: literal, regex or word")
+	f.BoolVar(&p.ignoreCase, "i", false, "Match case-insensitively")
+	f.BoolVar(&p.or, "or", false, "Match lines matching any term instead of all terms")
+	f.IntVar(&p.before, "B", 0, "Number of context lines to show before each match")
+	f.IntVar(&p.after, "A", 0, "Number of context lines to show after each match")
+	f.IntVar(&p.context, "C", 0, "Number of context lines to show around each match")
+	f.BoolVar(&p.noIgnore, "no-ignore", false,
+		"Include files ignored by .gitignore and .godevmcpignore files")
+	p.files.register(f)
 }
 
 func (p *LocalSearchCmd) Execute(
@@ -41,7 +60,7 @@ func (p *LocalSearchCmd) Execute(
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
-	fw := infra.NewFileWalker()
+	fw := infra.NewFileWalkerWithIgnore(!p.noIgnore)
 	path := f.Arg(0)
 	query := f.Arg(1)
 	if path == "" {
@@ -53,18 +72,37 @@ func (p *LocalSearchCmd) Execute(
 		return subcommands.ExitFailure
 	}
 
-	extension := p.extension
-	if extension != "" && !strings.HasPrefix(extension, ".") {
-		extension = "." + extension
+	opts := p.files.walkOptions()
+	for ext := range strings.SplitSeq(p.extension, ",") {
+		if ext = strings.TrimSpace(ext); ext != "" {
+			opts.Extensions = append(opts.Extensions, ext)
+		}
+	}
+
+	mode, err := contentsearch.ParseMode(p.mode)
+	if err != nil {
+		fmt.Println(err)
+		return subcommands.ExitUsageError
+	}
+	operator := contentsearch.OperatorAnd
+	if p.or {
+		operator = contentsearch.OperatorOr
 	}
 
 	results, err := app.SearchLocalFiles(
 		context.Background(),
 		fw,
 		path,
-		extension,
-		query,
+		opts,
+		contentsearch.Query{
+			Terms:      f.Args()[1:],
+			Mode:       mode,
+			IgnoreCase: p.ignoreCase,
+			Operator:   operator,
+		},
 		p.maxMatches,
+		max(p.before, p.context),
+		max(p.after, p.context),
 	)
 	if err != nil {
 		fmt.Printf("Error searching local files: %v\n", err)
@@ -77,9 +115,14 @@ func (p *LocalSearchCmd) Execute(
 	}
 	for _, result := range results {
 		for _, match := range result.Matches {
-			fmt.Printf("Found file: %s\nMatch: %s (Line: %d)\n",
-				result.Filename, match.Text, match.LineNo,
-			)
+			fmt.Printf("Found file: %s\n", result.Filename)
+			for i, line := range match.Before {
+				fmt.Printf("%d- %s\n", match.LineNo-len(match.Before)+i, line)
+			}
+			fmt.Printf("Match: %s (Line: %d)\n", match.Text, match.LineNo)
+			for i, line := range match.After {
+				fmt.Printf("%d- %s\n", match.LineNo+i+1, line)
+			}
 		}
 		if result.Truncated {
 			fmt.Println("... (additional matches truncated)")
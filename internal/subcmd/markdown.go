@@ -11,7 +11,9 @@ import (
 )
 
 type MarkdownCmd struct {
-	path string
+	path     string
+	noIgnore bool
+	files    fileFilterFlags
 }
 
 func (*MarkdownCmd) Name() string     { return "markdown" }
@@ -29,6 +31,9 @@ func (p *MarkdownCmd) SetFlags(f *flag.FlagSet) {
 		".",
 		"Directory path to scan for markdown files or path to a single markdown file",
 	)
+	f.BoolVar(&p.noIgnore, "no-ignore", false,
+		"Include files ignored by .gitignore and .godevmcpignore files")
+	p.files.register(f)
 }
 
 func (p *MarkdownCmd) Execute(
@@ -36,8 +41,8 @@ func (p *MarkdownCmd) Execute(
 	f *flag.FlagSet,
 	args ...interface{},
 ) subcommands.ExitStatus {
-	fw := infra.NewFileWalker()
-	results, err := app.ScanMarkdownFiles(ctx, fw, p.path)
+	fw := infra.NewFileWalkerWithIgnore(!p.noIgnore)
+	results, err := app.ScanMarkdownFiles(ctx, fw, p.path, p.files.walkOptions())
 	if err != nil {
 		fmt.Printf("Error scanning markdown files: %v\n", err)
 		return subcommands.ExitFailure
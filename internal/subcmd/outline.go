@@ -15,6 +15,12 @@ type OutlineGoPackageCmd struct {
 	skipDependencies bool
 	skipDeclarations bool
 	skipCallGraph    bool
+	mode             string
+	remote           string
+	noIgnore         bool
+	files            fileFilterFlags
+
+	github gitHubFlags
 }
 
 func (*OutlineGoPackageCmd) Name() string { return "outline" }
@@ -26,7 +32,8 @@ func (*OutlineGoPackageCmd) Synopsis() string {
 func (*OutlineGoPackageCmd) Usage() string {
 	return `outline [flags] <directory>:
   Show dependencies, exported declarations, and call graph
-  for all Go source files in the specified directory.
+  for all Go source files in the specified directory, or of a
+  remote package given with -remote.
 `
 }
 
@@ -35,6 +42,19 @@ func (p *OutlineGoPackageCmd) SetFlags(f *flag.FlagSet) {
 	f.BoolVar(&p.skipDependencies, "skip-deps", false, "Skip the dependencies section")
 	f.BoolVar(&p.skipDeclarations, "skip-decl", false, "Skip the declarations section")
 	f.BoolVar(&p.skipCallGraph, "skip-cg", false, "Skip the call graph section")
+	f.StringVar(
+		&p.mode,
+		"mode",
+		string(app.OutlineModeHeuristic),
+		"Call graph analysis mode: heuristic or typed (local directories only)",
+	)
+	f.StringVar(&p.remote, "remote", "",
+		"Remote package as [host/]owner/repo[@ref][/path] (GitHub, host a configured"+
+			" GitHub Enterprise host) or module[@version][/path]")
+	p.github.register(f, "github-")
+	f.BoolVar(&p.noIgnore, "no-ignore", false,
+		"Include files ignored by .gitignore and .godevmcpignore files")
+	p.files.register(f)
 }
 
 func (p *OutlineGoPackageCmd) Execute(
@@ -47,12 +67,25 @@ func (p *OutlineGoPackageCmd) Execute(
 		directory = f.Arg(0)
 	}
 
-	fw := infra.NewFileWalker()
-	output, err := app.OutlineGoPackage(ctx, fw, directory, app.OutlineGoPackageOptions{
+	mode, err := app.ParseOutlineMode(p.mode)
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return subcommands.ExitUsageError
+	}
+
+	opts := app.OutlineGoPackageOptions{
 		SkipDependencies: p.skipDependencies,
 		SkipDeclarations: p.skipDeclarations,
 		SkipCallGraph:    p.skipCallGraph,
-	})
+		Mode:             mode,
+		Files:            p.files.walkOptions(),
+	}
+	var output string
+	if p.remote != "" {
+		output, err = p.outlineRemote(ctx, opts)
+	} else {
+		output, err = app.OutlineGoPackage(ctx, infra.NewFileWalkerWithIgnore(!p.noIgnore), directory, opts)
+	}
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return subcommands.ExitFailure
@@ -61,3 +94,16 @@ func (p *OutlineGoPackageCmd) Execute(
 	fmt.Print(output)
 	return subcommands.ExitSuccess
 }
+
+func (p *OutlineGoPackageCmd) outlineRemote(
+	ctx context.Context,
+	opts app.OutlineGoPackageOptions,
+) (string, error) {
+	forges, err := p.github.forges(ctx)
+	if err != nil {
+		return "", err
+	}
+	cache := infra.NewSourceCache(infra.DefaultSourceCacheSize)
+	return app.OutlineRemoteGoPackage(
+		ctx, forges, infra.NewGoModuleProxy(), cache, p.remote, opts)
+}
@@ -57,7 +57,7 @@ func (c *PyDocSearchCmd) SetFlags(f *flag.FlagSet) {
 }
 
 func (c *PyDocSearchCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -73,7 +73,7 @@ func (c *PyDocSearchCmd) Execute(
 	}
 
 	httpcli := infra.NewHttpClient()
-	result, err := app.SearchPyDoc(httpcli, query)
+	result, err := app.SearchPyDoc(ctx, httpcli, query)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -86,6 +86,7 @@ func (c *PyDocSearchCmd) Execute(
 type PyDocReadCmd struct {
 	offset int
 	limit  int
+	symbol string
 }
 
 func (c *PyDocReadCmd) Name() string { return "read" }
@@ -101,10 +102,11 @@ func (c *PyDocReadCmd) Usage() string {
 func (c *PyDocReadCmd) SetFlags(f *flag.FlagSet) {
 	f.IntVar(&c.offset, "offset", 0, "Line offset to start reading from")
 	f.IntVar(&c.limit, "limit", app.DefaultLinesPerPage, "Number of lines to read")
+	f.StringVar(&c.symbol, "symbol", "", "Only read this object (e.g. json.dumps)")
 }
 
 func (p *PyDocReadCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -115,7 +117,14 @@ func (p *PyDocReadCmd) Execute(
 
 	moduleName := f.Arg(0)
 	httpcli := infra.NewHttpClient()
-	result, totalLines, hasMore, err := app.ReadPyDocPaged(httpcli, moduleName, p.offset, p.limit)
+	result, totalLines, hasMore, err := app.ReadPyDocPaged(
+		ctx,
+		httpcli,
+		moduleName,
+		p.symbol,
+		p.offset,
+		p.limit,
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
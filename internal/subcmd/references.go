@@ -0,0 +1,47 @@
+package subcmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/google/subcommands"
+)
+
+type RefsCmd struct {
+	directory     string
+	maxReferences int
+}
+
+func (*RefsCmd) Name() string     { return "refs" }
+func (*RefsCmd) Synopsis() string { return "Find references to a Go identifier." }
+func (*RefsCmd) Usage() string {
+	return `refs [flags] <file:line:column | qualified name>:
+  Type-check the module and list every reference to the identifier,
+  grouped by package. The target is either a position such as
+  internal/app/ast.go:70:6 or a qualified name such as
+  internal/repository.FileWalker.Walk.
+`
+}
+
+func (p *RefsCmd) SetFlags(f *flag.FlagSet) {
+	f.StringVar(&p.directory, "dir", ".", "Directory inside the module to analyze")
+	f.IntVar(&p.maxReferences, "max-refs", 0, "Maximum number of references to show (0 for all)")
+}
+
+func (p *RefsCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
+	if f.NArg() < 1 {
+		fmt.Println("Error: Missing target.")
+		return subcommands.ExitUsageError
+	}
+
+	result, err := app.FindReferences(ctx, p.directory, f.Arg(0))
+	if err != nil {
+		fmt.Printf("Error: %v\n", err)
+		return subcommands.ExitFailure
+	}
+
+	fmt.Print(app.FormatReferences(result, p.directory, p.maxReferences))
+	return subcommands.ExitSuccess
+}
@@ -57,7 +57,7 @@ func (c *RustDocSearchCmd) SetFlags(f *flag.FlagSet) {
 }
 
 func (c *RustDocSearchCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -73,7 +73,7 @@ func (c *RustDocSearchCmd) Execute(
 	}
 
 	httpcli := infra.NewHttpClient()
-	result, err := app.SearchRustDoc(httpcli, query)
+	result, err := app.SearchRustDoc(ctx, httpcli, query)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -86,6 +86,7 @@ func (c *RustDocSearchCmd) Execute(
 type RustDocReadCmd struct {
 	offset int
 	limit  int
+	symbol string
 }
 
 func (c *RustDocReadCmd) Name() string     { return "read" }
@@ -99,10 +100,11 @@ func (c *RustDocReadCmd) Usage() string {
 func (c *RustDocReadCmd) SetFlags(f *flag.FlagSet) {
 	f.IntVar(&c.offset, "offset", 0, "Line offset to start reading from")
 	f.IntVar(&c.limit, "limit", app.DefaultLinesPerPage, "Number of lines to read")
+	f.StringVar(&c.symbol, "symbol", "", "Only read this item (e.g. serde::de::Visitor)")
 }
 
 func (p *RustDocReadCmd) Execute(
-	_ context.Context,
+	ctx context.Context,
 	f *flag.FlagSet,
 	_ ...any,
 ) subcommands.ExitStatus {
@@ -113,7 +115,14 @@ func (p *RustDocReadCmd) Execute(
 
 	crateURL := f.Arg(0)
 	httpcli := infra.NewHttpClient()
-	result, totalLines, hasMore, err := app.ReadRustDocPaged(httpcli, crateURL, p.offset, p.limit)
+	result, totalLines, hasMore, err := app.ReadRustDocPaged(
+		ctx,
+		httpcli,
+		crateURL,
+		p.symbol,
+		p.offset,
+		p.limit,
+	)
 	if err != nil {
 		fmt.Println("Error:", err)
 		return subcommands.ExitFailure
@@ -6,7 +6,10 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"time"
 
+	"github.com/fpt/go-dev-mcp/internal/app"
+	"github.com/fpt/go-dev-mcp/internal/infra"
 	tool "github.com/fpt/go-dev-mcp/internal/mcptool"
 	"github.com/mark3labs/mcp-go/server"
 
@@ -21,6 +24,17 @@ type ServeCmd struct {
 	sse     bool
 	debug   bool
 	logFile string
+
+	cacheDir    string
+	cacheTTL    time.Duration
+	noDiskCache bool
+
+	httpTimeout time.Duration
+	httpRetries int
+
+	sites app.DocSites
+
+	github gitHubFlags
 }
 
 func (*ServeCmd) Name() string     { return "serve" }
@@ -37,6 +51,26 @@ func (p *ServeCmd) SetFlags(f *flag.FlagSet) {
 	f.StringVar(&p.addr, "addr", DefaultSSEServerAddr, "SSE server address")
 	f.BoolVar(&p.debug, "debug", os.Getenv("DEBUG") != "", "Enable debug mode")
 	f.StringVar(&p.logFile, "logfile", os.Getenv("LOGFILE"), "Log file path")
+	f.StringVar(&p.cacheDir, "cache-dir", infra.DefaultCacheDir(), "Documentation cache directory")
+	f.DurationVar(&p.cacheTTL, "cache-ttl", app.DefaultDocCacheTTL,
+		"Time after which cached documentation is revalidated")
+	f.BoolVar(&p.noDiskCache, "no-disk-cache", false, "Keep cached documentation in memory only")
+
+	httpDefaults := infra.DefaultHttpClientConfig()
+	f.DurationVar(&p.httpTimeout, "http-timeout", httpDefaults.Timeout,
+		"Timeout of each HTTP request to documentation sites")
+	f.IntVar(&p.httpRetries, "http-retries", httpDefaults.MaxRetries,
+		"Retries of HTTP requests failing with 429, 5xx or network errors")
+
+	sites := app.DocSitesFromEnv()
+	f.StringVar(&p.sites.GoDoc, "godoc-url", sites.GoDoc,
+		"Base URL of the Go documentation site (env "+app.EnvGoDocURL+")")
+	f.StringVar(&p.sites.RustDoc, "rustdoc-url", sites.RustDoc,
+		"Base URL of the Rust documentation site (env "+app.EnvRustDocURL+")")
+	f.StringVar(&p.sites.PyDoc, "pydoc-url", sites.PyDoc,
+		"Base URL of the Python documentation site (env "+app.EnvPyDocURL+")")
+
+	p.github.register(f, "github-")
 }
 
 func (p *ServeCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
@@ -58,7 +92,37 @@ func (p *ServeCmd) Execute(_ context.Context, f *flag.FlagSet, _ ...any) subcomm
 		slog.SetDefault(slog.New(slog.NewTextHandler(os.Stdout, nil)))
 	}
 
-	if err := tool.Register(s, p.workdir); err != nil {
+	httpCfg := infra.DefaultHttpClientConfig()
+	httpCfg.Timeout = p.httpTimeout
+	httpCfg.MaxRetries = p.httpRetries
+	infra.ConfigureHttpClient(httpCfg)
+
+	if err := app.ConfigureDocSites(p.sites); err != nil {
+		slog.ErrorContext(ctx, "Error configuring documentation sites", "error", err)
+		return subcommands.ExitFailure
+	}
+
+	cacheCfg := app.DocCacheConfig{Dir: p.cacheDir, TTL: p.cacheTTL}
+	if p.noDiskCache {
+		cacheCfg.Dir = ""
+	}
+	if err := app.ConfigureDocCache(cacheCfg); err != nil {
+		slog.ErrorContext(ctx, "Error configuring documentation cache", "error", err)
+		return subcommands.ExitFailure
+	}
+
+	forges, err := p.github.forges(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "Error creating forge clients", "error", err)
+		return subcommands.ExitFailure
+	}
+	// Credentials of GitLab and Gitea hosts are looked up and logged on first use
+	for _, host := range forges.GitHubClients.Hosts() {
+		client, _ := forges.Client(host)
+		slog.InfoContext(ctx, "GitHub credentials", "host", host, "source", client.AuthSource())
+	}
+
+	if err := tool.Register(s, p.workdir, forges); err != nil {
 		slog.ErrorContext(ctx, "Error registering tools", "error", err)
 		return subcommands.ExitFailure
 	}
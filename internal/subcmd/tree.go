@@ -15,6 +15,7 @@ type TreeCmd struct {
 	workdir   string
 	ignoreDot bool
 	maxDepth  int
+	noIgnore  bool
 }
 
 func (*TreeCmd) Name() string     { return "tree" }
@@ -34,13 +35,15 @@ func (p *TreeCmd) SetFlags(f *flag.FlagSet) {
 		"Ignore dot files and directories (except .git which is always ignored)",
 	)
 	f.IntVar(&p.maxDepth, "max-depth", 4, "Maximum depth for directory traversal")
+	f.BoolVar(&p.noIgnore, "no-ignore", false,
+		"Include files ignored by .gitignore and .godevmcpignore files")
 }
 
 func (p *TreeCmd) Execute(ctx context.Context, f *flag.FlagSet, _ ...any) subcommands.ExitStatus {
 	rootDir := p.workdir
 	b := strings.Builder{}
 	b.WriteString(fmt.Sprintf("%s\n", rootDir))
-	walker := infra.NewDirWalker()
+	walker := infra.NewDirWalkerWithIgnore(!p.noIgnore)
 	err := app.PrintTree(ctx, &b, walker, rootDir, p.ignoreDot, p.maxDepth)
 	if err != nil {
 		fmt.Printf("Error printing tree: %v\n", err)